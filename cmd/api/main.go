@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -11,9 +12,19 @@ import (
 	"time"
 
 	"github.com/nettojulio/ufape-crawler-golang/docs"
+	"github.com/nettojulio/ufape-crawler-golang/internal/auth"
 	"github.com/nettojulio/ufape-crawler-golang/internal/config"
 	"github.com/nettojulio/ufape-crawler-golang/internal/crawler"
+	grpcapi "github.com/nettojulio/ufape-crawler-golang/internal/grpc"
+	"github.com/nettojulio/ufape-crawler-golang/internal/health"
 	server "github.com/nettojulio/ufape-crawler-golang/internal/http"
+	"github.com/nettojulio/ufape-crawler-golang/internal/live"
+	"github.com/nettojulio/ufape-crawler-golang/internal/logging"
+	"github.com/nettojulio/ufape-crawler-golang/internal/monitor"
+	"github.com/nettojulio/ufape-crawler-golang/internal/snapshot"
+	"github.com/nettojulio/ufape-crawler-golang/internal/storage"
+	"github.com/nettojulio/ufape-crawler-golang/internal/tracing"
+	"github.com/nettojulio/ufape-crawler-golang/internal/warc"
 )
 
 var Version = "development"
@@ -31,28 +42,214 @@ var Version = "development"
 // @license.url https://opensource.org/licenses/MIT
 
 // @BasePath /
+
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+// @description Chave de API, exigida quando AUTH_ENABLED=true. Também aceita no cabeçalho Authorization: Bearer.
 func main() {
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		os.Exit(runConfig(os.Args[2:]))
+	}
 
 	cfg, err := config.Load(Version)
 	if err != nil {
-		logger.Error("failed to load config", "error", err)
+		slog.Error("failed to load config", "error", err)
 		os.Exit(1)
 	}
 
+	logger, err := logging.New(os.Stdout, cfg.Log)
+	if err != nil {
+		slog.Error("failed to configure logging", "error", err)
+		os.Exit(1)
+	}
+	slog.SetDefault(logger)
+
 	logger.Info("starting application", "version", Version)
 
+	docs.SwaggerInfo.Host = cfg.Host
+
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing, Version)
 	if err != nil {
-		logger.Error("failed to load config", "error", err)
+		logger.Error("failed to set up tracing", "exporter", cfg.Tracing.Exporter, "error", err)
 		os.Exit(1)
 	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			logger.Error("failed to flush traces", "error", err)
+		}
+	}()
 
-	docs.SwaggerInfo.Host = cfg.Host
+	repository, err := storage.Open(context.Background(), cfg.Storage)
+	if err != nil {
+		logger.Error("failed to open storage", "driver", cfg.Storage.Driver, "error", err)
+		os.Exit(1)
+	}
+	if repository != nil {
+		defer repository.Close()
+		logger.Info("storage enabled", "driver", cfg.Storage.Driver)
+	}
+
+	var authStore *auth.Store
+	if cfg.Auth.Enabled {
+		authStore, err = auth.LoadFile(cfg.Auth.KeysFile)
+		if err != nil {
+			logger.Error("failed to load api keys", "file", cfg.Auth.KeysFile, "error", err)
+			os.Exit(1)
+		}
+		logger.Info("api key authentication enabled", "keys", len(authStore.Usage()))
+	}
+
+	crawler.SetDefaults(crawler.NewDefaults(cfg.Crawler))
+	clientOpts := []crawler.HTTPClientOption{
+		crawler.WithLogger(logger),
+		crawler.WithUserAgent(cfg.Crawler.UserAgent),
+		crawler.WithMaxRedirects(cfg.Crawler.MaxRedirects),
+	}
+	var proxies *crawler.ProxyPool
+	if len(cfg.Crawler.Proxy.URLs) > 0 {
+		proxies, err = crawler.NewProxyPool(cfg.Crawler.Proxy.URLs, crawler.ProxyPoolOptions{
+			Strategy:      crawler.ProxyStrategy(cfg.Crawler.Proxy.Strategy),
+			MaxFailures:   cfg.Crawler.Proxy.MaxFailures,
+			HealthTimeout: cfg.Crawler.Proxy.HealthTimeout,
+			Logger:        logger,
+		})
+		if err != nil {
+			logger.Error("invalid proxy pool", "error", err)
+			os.Exit(1)
+		}
+		clientOpts = append(clientOpts, crawler.WithProxyPool(proxies))
+		logger.Info("outbound proxies enabled", "proxies", len(cfg.Crawler.Proxy.URLs), "strategy", cfg.Crawler.Proxy.Strategy)
+	}
+	if cfg.Crawler.Proxy.AllowPerRequest {
+		clientOpts = append(clientOpts, crawler.WithRequestProxies())
+	}
+	var sessions *crawler.SessionStore
+	if cfg.Crawler.SessionsFile != "" {
+		sessions, err = crawler.LoadSessions(cfg.Crawler.SessionsFile)
+		if err != nil {
+			logger.Error("failed to load sessions", "file", cfg.Crawler.SessionsFile, "error", err)
+			os.Exit(1)
+		}
+		clientOpts = append(clientOpts, crawler.WithSessions(sessions))
+		logger.Info("crawler sessions enabled", "sessions", sessions.Names())
+	}
+	var guard *crawler.AddressGuard
+	if cfg.SSRF.Enabled {
+		guard, err = crawler.NewAddressGuard(cfg.SSRF.Allowlist)
+		if err != nil {
+			logger.Error("invalid ssrf allowlist", "error", err)
+			os.Exit(1)
+		}
+		clientOpts = append(clientOpts, crawler.WithAddressGuard(guard))
+	} else {
+		logger.Warn("ssrf protection disabled, the crawler can reach internal addresses")
+	}
+	if cfg.Archive.Dir != "" {
+		archive, err := warc.NewWriter(warc.Options{
+			Dir:     cfg.Archive.Dir,
+			Prefix:  cfg.Archive.Prefix,
+			MaxSize: cfg.Archive.MaxSize,
+			Gzip:    cfg.Archive.Gzip,
+		})
+		if err != nil {
+			logger.Error("failed to open warc archive", "dir", cfg.Archive.Dir, "error", err)
+			os.Exit(1)
+		}
+		defer archive.Close()
+		clientOpts = append(clientOpts, crawler.WithRecorder(archive))
+		logger.Info("warc archiving enabled", "dir", cfg.Archive.Dir)
+	}
+
+	httpClient := crawler.NewHTTPClient(cfg.Crawler.Timeout, clientOpts...)
+	proxyCtx, stopProxyChecks := context.WithCancel(context.Background())
+	defer stopProxyChecks()
+	if proxies != nil && cfg.Crawler.Proxy.HealthInterval > 0 {
+		go proxies.Run(proxyCtx, cfg.Crawler.Proxy.HealthInterval)
+	}
+	crawlerService := crawler.NewService(httpClient, crawler.WithServiceLogger(logger))
+
+	var scheduler *monitor.Scheduler
+	if cfg.Scheduler.Enabled {
+		var snapshots *snapshot.Store
+		if cfg.Scheduler.SnapshotDir != "" {
+			snapshots, err = snapshot.NewStore(cfg.Scheduler.SnapshotDir)
+			if err != nil {
+				logger.Error("failed to open snapshot store", "dir", cfg.Scheduler.SnapshotDir, "error", err)
+				os.Exit(1)
+			}
+		}
+		scheduler, err = monitor.NewScheduler(crawlerService, repository, monitor.Options{
+			File:           cfg.Scheduler.File,
+			WebhookTimeout: cfg.Scheduler.WebhookTimeout,
+			Snapshots:      snapshots,
+			AddressGuard:   guard,
+			Logger:         logger,
+		})
+		if err != nil {
+			logger.Error("failed to start scheduler", "error", err)
+			os.Exit(1)
+		}
+		scheduler.Start()
+		logger.Info("scheduler enabled", "schedules", len(scheduler.List()))
+	}
+
+	jobs := live.NewManager(crawlerService, repository, live.Options{
+		MaxRunning: cfg.Crawls.MaxRunning,
+		Retain:     cfg.Crawls.Retain,
+		MaxDepth:   cfg.Crawls.MaxDepth,
+		Sessions:   sessions,
+		Logger:     logger,
+	})
+
+	checker := health.NewChecker(cfg.Health.Timeout)
+	if repository != nil {
+		checker.Add("storage", health.Storage(repository))
+	}
+	checker.Add("crawls", health.Capacity(jobs.Running))
+	if proxies != nil {
+		checker.Add("proxies", health.Proxies(proxies.Available))
+	}
+	if cfg.Health.ProbeURL != "" {
+		checker.Add("outbound", health.Outbound(&http.Client{}, cfg.Health.ProbeURL))
+	}
 
-	httpClient := crawler.NewHTTPClient(60 * time.Second)
-	crawlerService := crawler.NewService(httpClient)
+	rateLimits := server.NewRateLimits(cfg.RateLimit)
+	e := server.NewServer(cfg, server.Dependencies{
+		CrawlerService: crawlerService,
+		Repository:     repository,
+		Jobs:           jobs,
+		Scheduler:      scheduler,
+		Logger:         logger,
+		Health:         checker,
+		Auth:           authStore,
+		RateLimits:     rateLimits,
+	})
 
-	e := server.NewServer(cfg, crawlerService)
+	var grpcServer *grpcapi.Server
+	if cfg.GRPC.Enabled {
+		lis, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.GRPC.Port))
+		if err != nil {
+			logger.Error("failed to listen for grpc", "port", cfg.GRPC.Port, "error", err)
+			os.Exit(1)
+		}
+		grpcServer = grpcapi.NewServer(grpcapi.Dependencies{
+			CrawlerService: crawlerService,
+			Repository:     repository,
+			Jobs:           jobs,
+			Logger:         logger,
+			Auth:           authStore,
+			RateLimits:     rateLimits,
+		})
+		go func() {
+			logger.Info("grpc server starting", "address", lis.Addr().String())
+			if err := grpcServer.Serve(lis); err != nil {
+				logger.Error("grpc server stopped", "error", err)
+			}
+		}()
+	}
 
 	go func() {
 		addr := fmt.Sprintf(":%d", cfg.Port)
@@ -68,12 +265,46 @@ func main() {
 
 	logger.Info("server is shutting down...")
 
+	// O /readyz passa a responder 503 enquanto o servidor ainda aceita conexões,
+	// dando ao balanceador tempo de retirar a instância.
+	checker.Shutdown()
+	time.Sleep(cfg.Health.ShutdownDelay)
+
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	// Parar os crawlings antes encerra os streams SSE e WebSocket dos eventos e os
+	// de CrawlSite, que Shutdown e GracefulStop aguardam.
+	jobs.Stop()
 	if err := e.Shutdown(ctx); err != nil {
-		e.Logger.Fatal(err)
+		logger.Error("failed to shut down the http server", "error", err)
+	}
+	if grpcServer != nil {
+		grpcServer.GracefulStop()
+	}
+	if scheduler != nil {
+		scheduler.Stop()
 	}
 
 	logger.Info("server shutdown complete")
 }
+
+// runConfig executa o subcomando "config". "config print" escreve as
+// configurações efetivas em YAML, com os valores do ambiente, do CONFIG_FILE e
+// os padrões já aplicados e validados.
+func runConfig(args []string) int {
+	if len(args) != 1 || args[0] != "print" {
+		fmt.Fprintln(os.Stderr, "usage: api config print")
+		return 2
+	}
+	cfg, err := config.Load(Version)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", err)
+		return 1
+	}
+	if err := cfg.WriteYAML(os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to print config: %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -1,273 +1,234 @@
 package main
 
 import (
-	"bytes"
-	"container/list"
-	"encoding/json"
+	"context"
+	"flag"
 	"fmt"
-	"io"
-	"log"
+	"log/slog"
 	"math"
-	"net/http"
-	"net/url"
 	"os"
-	"strings"
-	"time"
 
+	"github.com/nettojulio/ufape-crawler-golang/internal/config"
 	"github.com/nettojulio/ufape-crawler-golang/internal/crawler"
+	"github.com/nettojulio/ufape-crawler-golang/internal/extractor"
+	"github.com/nettojulio/ufape-crawler-golang/internal/graph"
+	"github.com/nettojulio/ufape-crawler-golang/internal/logging"
+	"github.com/nettojulio/ufape-crawler-golang/internal/snapshot"
+	"github.com/nettojulio/ufape-crawler-golang/internal/storage"
+	"github.com/nettojulio/ufape-crawler-golang/internal/warc"
 )
 
-const (
-	DEFAULT_TIMEOUT = 60 * time.Second
-	INITIAL_URL     = "https://ufape.edu.br"
-	MAX_DEPTH       = math.MaxInt
-)
-
-type FinalResponse struct {
-	Nodes       []Node `json:"nodes"`
-	Links       []Link `json:"links"`
-	GeneratedAt int64  `json:"generatedAt"`
-}
-
-type Node struct {
-	ID          string `json:"id"`
-	Depth       int    `json:"depth"`
-	StatusCode  int    `json:"statusCode"`
-	ContentType string `json:"contentType"`
-	ElapsedTime int64  `json:"elapsedTime"`
-	Title       string `json:"title"`
-	Domain      string `json:"domain"`
-}
-
-func NewNode(url string, depth int, response *crawler.ResponseDTO) Node {
-	return Node{
-		ID:          url,
-		Depth:       depth,
-		StatusCode:  response.StatusCode,
-		ContentType: response.ContentType,
-		ElapsedTime: response.ElapsedTime,
-		Title:       response.Title,
-		Domain:      response.Details.Original.Host,
-	}
-}
-
-type Link struct {
-	Source string `json:"source"`
-	Target string `json:"target"`
-}
-
-func NewLink(source, target string) Link {
-	return Link{
-		Source: source,
-		Target: target,
-	}
-}
-
-func NewRequestPayload(url string) *crawler.Payload {
-	return &crawler.Payload{
-		AllowedDomains:    &[]string{"ufape.edu.br"},
+func NewRequestPayload(cfg config.ExtractorConfig) crawler.Payload {
+	return crawler.Payload{
+		AllowedDomains:    &cfg.AllowedDomains,
 		CanRetry:          boolPtr(false),
 		CollectSubdomains: boolPtr(true),
 		LowerCaseURLs:     boolPtr(false),
 		MaxAttempts:       intPtr(1),
 		RemoveFragment:    boolPtr(false),
-		Timeout:           intPtr(60),
-		Url:               url,
+		Timeout:           intPtr(int(cfg.Timeout.Seconds())),
+		RequestOptions:    crawler.RequestOptions{Session: cfg.Session},
 	}
 }
 
-type CrawlItem struct {
-	URL   string
-	Depth int
+// options são as opções de linha de comando do extractor.
+type options struct {
+	fullGraph   bool
+	output      string
+	streamFile  string
+	warcDir     string
+	snapshotDir string
+	warcMaxSize int64
 }
 
-type Crawler struct {
-	httpClient *http.Client
-	baseURL    string
-
-	queue   *list.List
-	visited map[string]struct{}
-	result  *FinalResponse
+func main() {
+	var opts options
+	flag.BoolVar(&opts.fullGraph, "full-graph", true, "registra todas as arestas internas, incluindo links para páginas já visitadas")
+	flag.StringVar(&opts.output, "output", "grafo_salvo.json", "arquivo JSON do grafo gerado ao final do crawling")
+	flag.StringVar(&opts.streamFile, "stream", "", "se definido, grava nós e arestas em NDJSON neste arquivo durante o crawling, sem manter o grafo em memória")
+	flag.StringVar(&opts.warcDir, "warc", "", "se definido, busca as páginas diretamente, sem a API, e arquiva requisições e respostas em WARC neste diretório")
+	flag.StringVar(&opts.snapshotDir, "snapshots", "", "se definido, guarda o HTML das páginas neste diretório, endereçado pelo SHA-256 do conteúdo")
+	flag.Int64Var(&opts.warcMaxSize, "warc-max-size", warc.DefaultMaxSize, "tamanho em bytes a partir do qual um novo arquivo WARC é iniciado")
+	flag.Parse()
+
+	cfg, err := config.Load("")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Erro fatal ao carregar a configuração: %v\n", err)
+		os.Exit(1)
+	}
+	logger := newLogger(cfg.Log)
 
-	maxDepth int
+	if err := run(cfg, opts, logger); err != nil {
+		os.Exit(1)
+	}
 }
 
-func NewCrawler(baseURL string, timeout time.Duration, maxDepth int) *Crawler {
-	transport := &http.Transport{
-		MaxIdleConns:        100,
-		MaxIdleConnsPerHost: 10,
-		IdleConnTimeout:     90 * time.Second,
+// run executa o crawling. Os erros já chegam registrados no log; retorná-los,
+// em vez de encerrar o processo, garante que os arquivos WARC e NDJSON sejam
+// fechados e que a execução registrada seja marcada como falha.
+func run(cfg *config.Config, opts options, logger *slog.Logger) (err error) {
+	fail := func(msg string, cause error) error {
+		logger.Error(msg, "error", cause)
+		return cause
 	}
 
-	return &Crawler{
-		httpClient: &http.Client{
-			Timeout:   timeout,
-			Transport: transport,
-		},
-		baseURL: baseURL,
-		queue:   list.New(),
-		visited: make(map[string]struct{}),
-		result: &FinalResponse{
-			Nodes:       []Node{},
-			Links:       []Link{},
-			GeneratedAt: time.Now().UTC().UnixMilli(),
-		},
-		maxDepth: maxDepth,
+	maxDepth := cfg.Extractor.MaxDepth
+	if maxDepth == 0 {
+		maxDepth = math.MaxInt
+		logger.Warn("EXTRACTOR_MAX_DEPTH não definido: a profundidade é ilimitada. O crawling pode demorar muito ou nunca terminar.")
 	}
-}
-
-func (c *Crawler) Crawl(initialURL string) {
-	normalizedInitialURL := c.normalizeLink(initialURL)
-	c.enqueue(&CrawlItem{URL: normalizedInitialURL, Depth: 1})
-	c.markAsVisited(normalizedInitialURL)
+	crawler.SetDefaults(crawler.NewDefaults(cfg.Crawler))
 
-	for c.queue.Len() > 0 {
-		item := c.dequeue()
-		if item == nil {
-			continue
+	ctx := context.Background()
+	payload := NewRequestPayload(cfg.Extractor)
+	var snapshots *snapshot.Store
+	if opts.snapshotDir != "" {
+		snapshots, err = snapshot.NewStore(opts.snapshotDir)
+		if err != nil {
+			return fail("Erro fatal ao abrir o diretório de snapshots", err)
 		}
+		payload = extractor.SnapshotPayload(payload)
+	}
 
-		if item.Depth > c.maxDepth {
-			continue
+	var fetcher extractor.Fetcher = extractor.NewAPIFetcher(cfg.Extractor.APIURL, cfg.Extractor.Timeout, payload)
+	if opts.warcDir != "" {
+		// Sem :=, para que o defer abaixo altere o err retornado.
+		var archive *warc.Writer
+		archive, err = warc.NewWriter(warc.Options{Dir: opts.warcDir, MaxSize: opts.warcMaxSize, Gzip: true})
+		if err != nil {
+			return fail("Erro fatal ao criar o arquivo WARC", err)
 		}
+		defer func() {
+			if cerr := archive.Close(); cerr != nil && err == nil {
+				err = fail("Erro fatal ao finalizar o arquivo WARC", cerr)
+			}
+		}()
 
-		fmt.Printf("Depth: %d | Crawling: %s\n", item.Depth, item.URL)
-
-		response, err := c.fetchLinks(item.URL)
-		if err != nil {
-			log.Printf("AVISO: Falha ao buscar %s: %v. Continuando...", item.URL, err)
-			continue
+		clientOpts := []crawler.HTTPClientOption{
+			crawler.WithRecorder(archive),
+			crawler.WithLogger(logger),
+			crawler.WithUserAgent(cfg.Crawler.UserAgent),
+			crawler.WithMaxRedirects(cfg.Crawler.MaxRedirects),
+		}
+		if len(cfg.Crawler.Proxy.URLs) > 0 {
+			proxies, err := crawler.NewProxyPool(cfg.Crawler.Proxy.URLs, crawler.ProxyPoolOptions{
+				Strategy:      crawler.ProxyStrategy(cfg.Crawler.Proxy.Strategy),
+				MaxFailures:   cfg.Crawler.Proxy.MaxFailures,
+				HealthTimeout: cfg.Crawler.Proxy.HealthTimeout,
+				Logger:        logger,
+			})
+			if err != nil {
+				return fail("Erro fatal ao configurar os proxies", err)
+			}
+			if cfg.Crawler.Proxy.HealthInterval > 0 {
+				go proxies.Run(ctx, cfg.Crawler.Proxy.HealthInterval)
+			}
+			clientOpts = append(clientOpts, crawler.WithProxyPool(proxies))
 		}
-		newAvailable := []string{}
-		for _, link := range response.Links.Available {
-			normalizedLink := c.normalizeLink(link)
-			if c.shouldVisit(normalizedLink) {
-				c.enqueue(&CrawlItem{URL: normalizedLink, Depth: item.Depth + 1})
-				c.markAsVisited(normalizedLink)
-				newAvailable = append(newAvailable, normalizedLink)
+		// Os cookies recebidos valem para o restante do crawling, como em um
+		// navegador.
+		clientOpts = append(clientOpts, crawler.WithCookieJar(crawler.NewCookieJar()))
+		var sessions *crawler.SessionStore
+		if cfg.Crawler.SessionsFile != "" {
+			sessions, err = crawler.LoadSessions(cfg.Crawler.SessionsFile)
+			if err != nil {
+				return fail("Erro fatal ao carregar as sessões", err)
 			}
+			clientOpts = append(clientOpts, crawler.WithSessions(sessions))
 		}
-		response.Links.Available = newAvailable
-		c.addResponseToGraph(response, item)
-	}
-	fmt.Println("Crawling finalizado.")
-}
-
-func (c *Crawler) fetchLinks(url string) (*crawler.ResponseDTO, error) {
-	payload := NewRequestPayload(url)
-	jsonData, err := json.Marshal(payload)
-	if err != nil {
-		return nil, fmt.Errorf("falha ao serializar payload: %w", err)
-	}
-
-	req, err := http.NewRequest("POST", c.baseURL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("falha ao criar requisição: %w", err)
+		if cfg.Extractor.Session != "" {
+			if _, err := sessions.Get(cfg.Extractor.Session); err != nil {
+				return fail("Erro fatal ao usar a sessão de EXTRACTOR_SESSION", err)
+			}
+		}
+		httpClient := crawler.NewHTTPClient(cfg.Crawler.Timeout, clientOpts...)
+		service := crawler.NewService(httpClient, crawler.WithServiceLogger(logger))
+		fetcher = extractor.NewServiceFetcher(service, payload)
+		logger.Info("Arquivamento WARC habilitado. As páginas serão buscadas diretamente, sem a API.", "dir", opts.warcDir)
 	}
-	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("falha na requisição http: %w", err)
+	if cfg.Extractor.Session != "" {
+		logger.Info("Usando a sessão autenticada em todas as páginas", "session", cfg.Extractor.Session)
 	}
-	defer res.Body.Close()
 
-	if res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("status inesperado: %s", res.Status)
-	}
+	crawler := extractor.NewCrawler(fetcher, extractor.Options{
+		MaxDepth:  maxDepth,
+		FullGraph: opts.fullGraph,
+		Snapshots: snapshots,
+		Logger:    logger,
+	})
 
-	body, err := io.ReadAll(res.Body)
+	// finished indica que a execução registrada já foi finalizada; nos
+	// caminhos de erro ela é marcada como falha antes de repository.Close.
+	finished := false
+	repository, err := storage.Open(ctx, cfg.Storage)
 	if err != nil {
-		return nil, fmt.Errorf("falha ao ler corpo da resposta: %w", err)
+		return fail("Erro fatal ao abrir a persistência", err)
 	}
-
-	var apiResponse crawler.ResponseDTO
-	if err := json.Unmarshal(body, &apiResponse); err != nil {
-		return nil, fmt.Errorf("falha ao decodificar JSON: %w", err)
+	if repository != nil {
+		defer repository.Close()
+		if err := crawler.RecordTo(ctx, repository, cfg.Extractor.SeedURL); err != nil {
+			return fail("Erro fatal ao registrar a execução", err)
+		}
+		logger.Info("Persistência habilitada", "driver", cfg.Storage.Driver, "run_id", crawler.RunID())
+		defer func() {
+			if finished {
+				return
+			}
+			if err := crawler.FinishRecording(ctx, storage.RunStatusFailed); err != nil {
+				logger.Warn("Falha ao finalizar a execução", "error", err)
+			}
+		}()
 	}
 
-	return &apiResponse, nil
-}
-
-func (c *Crawler) addResponseToGraph(response *crawler.ResponseDTO, sourceItem *CrawlItem) {
-	node := NewNode(sourceItem.URL, sourceItem.Depth, response)
-	c.result.Nodes = append(c.result.Nodes, node)
-
-	for _, targetLink := range response.Links.Available {
-		link := NewLink(sourceItem.URL, targetLink)
-		c.result.Links = append(c.result.Links, link)
+	var stream *graph.StreamWriter
+	if opts.streamFile != "" {
+		stream, err = graph.CreateStream(opts.streamFile, graph.StreamOptions{})
+		if err != nil {
+			return fail("Erro fatal ao criar o fluxo NDJSON", err)
+		}
+		// Fecha o fluxo nos caminhos de erro; no de sucesso, Close já foi chamado.
+		defer stream.Close()
+		if err := crawler.StreamTo(stream); err != nil {
+			return fail("Erro fatal ao gravar o fluxo NDJSON", err)
+		}
 	}
-}
 
-func (c *Crawler) normalizeLink(link string) string {
-	parsedURL, err := url.Parse(link)
-	if err != nil {
-		return link
+	if err := crawler.Crawl(ctx, cfg.Extractor.SeedURL); err != nil {
+		return fail("Erro fatal durante o crawling", err)
 	}
-	parsedURL.Fragment = ""
-	return strings.TrimSuffix(parsedURL.String(), "/")
-}
-
-func (c *Crawler) shouldVisit(u string) bool {
-	_, exists := c.visited[u]
-	return !exists
-}
-
-func (c *Crawler) markAsVisited(u string) {
-	c.visited[u] = struct{}{}
 
-	if strings.HasPrefix(u, "https://") {
-		httpVersion := "http" + u[5:]
-		c.visited[httpVersion] = struct{}{}
-	} else if strings.HasPrefix(u, "http://") {
-		httpsVersion := "httpss" + u[4:]
-		c.visited[httpsVersion] = struct{}{}
+	finished = true
+	if err := crawler.FinishRecording(ctx, storage.RunStatusCompleted); err != nil {
+		logger.Warn("Falha ao finalizar a execução", "error", err)
 	}
-}
 
-func (c *Crawler) SaveResult(filename string) error {
-	fileData, err := json.MarshalIndent(c.result, "", "  ")
-	if err != nil {
-		return fmt.Errorf("falha ao serializar resultado: %w", err)
+	if stream != nil {
+		if err := stream.Close(); err != nil {
+			return fail("Erro fatal ao finalizar o fluxo NDJSON", err)
+		}
+		logger.Info("Fluxo NDJSON salvo com sucesso. Use 'graphtool convert' para gerar o JSON agregado.", "file", opts.streamFile)
+		return nil
 	}
 
-	if err := os.WriteFile(filename, fileData, 0644); err != nil {
-		return fmt.Errorf("falha ao escrever no arquivo %s: %w", filename, err)
+	if err := crawler.SaveResult(opts.output); err != nil {
+		return fail("Erro fatal ao salvar o arquivo", err)
 	}
-
-	fmt.Printf("Resultado salvo com sucesso em %s\n", filename)
 	return nil
 }
 
-func (c *Crawler) enqueue(item *CrawlItem) {
-	c.queue.PushBack(item)
-}
-
-func (c *Crawler) dequeue() *CrawlItem {
-	if c.queue.Len() == 0 {
-		return nil
-	}
-	element := c.queue.Front()
-	c.queue.Remove(element)
-	return element.Value.(*CrawlItem)
-}
-
-func main() {
-	apiURL := os.Getenv("API_URL")
-	if apiURL == "" {
-		apiURL = "http://localhost:8080/"
-		log.Println("AVISO: Variável de ambiente API_URL não definida. Usando URL padrão:", apiURL)
+// newLogger cria o logger do extractor conforme LOG_LEVEL e LOG_FORMAT, com
+// saída em texto por padrão.
+func newLogger(cfg config.LogConfig) *slog.Logger {
+	if cfg.Format == "" {
+		cfg.Format = logging.FormatText
 	}
-	if MAX_DEPTH == math.MaxInt {
-		log.Println("AVISO: MAX_DEPTH está configurado como 'infinito' (math.MaxInt). O crawling pode demorar muito ou nunca terminar.")
-	}
-
-	crawler := NewCrawler(apiURL, DEFAULT_TIMEOUT, MAX_DEPTH)
-	crawler.Crawl(INITIAL_URL)
-
-	if err := crawler.SaveResult("grafo_salvo.json"); err != nil {
-		log.Fatalf("Erro fatal ao salvar o arquivo: %v", err)
+	logger, err := logging.New(os.Stdout, cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Erro fatal ao configurar os logs: %v\n", err)
+		os.Exit(1)
 	}
+	return logger
 }
 
 func boolPtr(b bool) *bool {
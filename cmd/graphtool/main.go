@@ -0,0 +1,213 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/nettojulio/ufape-crawler-golang/internal/diff"
+	"github.com/nettojulio/ufape-crawler-golang/internal/graph"
+	"github.com/nettojulio/ufape-crawler-golang/internal/snapshot"
+)
+
+const usage = `Uso: graphtool <comando> [opções]
+
+Comandos:
+  analyze   calcula métricas de grafo (grau, PageRank, HITS, componentes, profundidade e betweenness)
+  convert   agrega um fluxo NDJSON do extractor no JSON do grafo
+  diff      compara dois crawlings e relata páginas, links, status, títulos e conteúdos alterados
+  snapshot  extrai o HTML guardado de uma página, pelo hash do conteúdo ou pela URL em um grafo
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "analyze":
+		err = runAnalyze(os.Args[2:])
+	case "convert":
+		err = runConvert(os.Args[2:])
+	case "diff":
+		err = runDiff(os.Args[2:])
+	case "snapshot":
+		err = runSnapshot(os.Args[2:])
+	case "-h", "--help", "help":
+		fmt.Print(usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "comando desconhecido: %s\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		log.Fatalf("Erro: %v", err)
+	}
+}
+
+func runAnalyze(args []string) error {
+	fs := flag.NewFlagSet("analyze", flag.ExitOnError)
+	input := fs.String("input", "grafo_salvo.json", "arquivo do grafo gerado pelo extractor")
+	reportFile := fs.String("report", "relatorio_grafo.json", "arquivo de saída do relatório JSON")
+	annotated := fs.String("annotate", "", "se definido, grava uma cópia do grafo com as métricas em cada nó")
+	home := fs.String("home", "", "página inicial usada na profundidade de cliques (padrão: nó de menor profundidade)")
+	keyPages := fs.Int("key-pages", 10, "quantidade de páginas principais que recebem betweenness")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	g, err := loadGraph(*input)
+	if err != nil {
+		return err
+	}
+
+	report := graph.Analyze(g, graph.Options{Home: *home, KeyPages: *keyPages})
+	if err := graph.SaveReport(report, *reportFile); err != nil {
+		return err
+	}
+	fmt.Printf("Relatório salvo com sucesso em %s\n", *reportFile)
+
+	if *annotated != "" {
+		graph.Annotate(g, report)
+		if err := graph.Save(g, *annotated); err != nil {
+			return err
+		}
+		fmt.Printf("Grafo anotado salvo com sucesso em %s\n", *annotated)
+	}
+	return nil
+}
+
+func runConvert(args []string) error {
+	fs := flag.NewFlagSet("convert", flag.ExitOnError)
+	input := fs.String("input", "grafo_salvo.ndjson", "fluxo NDJSON gerado pelo extractor com -stream")
+	output := fs.String("output", "grafo_salvo.json", "arquivo JSON do grafo agregado")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	g, err := graph.LoadStream(*input)
+	if err != nil {
+		return err
+	}
+	if err := graph.Save(g, *output); err != nil {
+		return err
+	}
+	fmt.Printf("Grafo com %d nós e %d arestas salvo com sucesso em %s\n", len(g.Nodes), len(g.Links), *output)
+	return nil
+}
+
+func runDiff(args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	oldFile := fs.String("old", "", "grafo do crawling anterior (JSON ou NDJSON)")
+	newFile := fs.String("new", "", "grafo do crawling atual (JSON ou NDJSON)")
+	format := fs.String("format", "markdown", "formato da saída: json ou markdown")
+	output := fs.String("output", "", "arquivo de saída (padrão: saída padrão)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *oldFile == "" || *newFile == "" {
+		return fmt.Errorf("-old e -new são obrigatórios")
+	}
+
+	previous, err := loadGraph(*oldFile)
+	if err != nil {
+		return err
+	}
+	current, err := loadGraph(*newFile)
+	if err != nil {
+		return err
+	}
+	report := diff.Compare(previous, current)
+
+	if *output == "" {
+		return writeReport(os.Stdout, report, *format)
+	}
+	f, err := os.Create(*output)
+	if err != nil {
+		return fmt.Errorf("falha ao criar %s: %w", *output, err)
+	}
+	if err := writeReport(f, report, *format); err != nil {
+		f.Close()
+		return err
+	}
+	// O erro de Close indica que o relatório pode ter sido gravado pela metade.
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("falha ao gravar %s: %w", *output, err)
+	}
+	return nil
+}
+
+// writeReport escreve report em out no formato informado.
+func writeReport(out io.Writer, report *diff.Report, format string) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	case "markdown", "md":
+		return report.WriteMarkdown(out)
+	default:
+		return fmt.Errorf("formato desconhecido: %s", format)
+	}
+}
+
+func runSnapshot(args []string) error {
+	fs := flag.NewFlagSet("snapshot", flag.ExitOnError)
+	dir := fs.String("dir", "snapshots", "diretório de snapshots do extractor")
+	hash := fs.String("hash", "", "SHA-256 do conteúdo da página")
+	input := fs.String("graph", "", "grafo usado para encontrar o hash da página informada em -url")
+	pageURL := fs.String("url", "", "URL da página no grafo informado em -graph")
+	output := fs.String("output", "", "arquivo de saída (padrão: saída padrão)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *hash == "" {
+		if *input == "" || *pageURL == "" {
+			return fmt.Errorf("informe -hash ou -graph e -url")
+		}
+		g, err := loadGraph(*input)
+		if err != nil {
+			return err
+		}
+		for _, n := range g.Nodes {
+			if n.ID == *pageURL {
+				*hash = n.ContentHash
+				break
+			}
+		}
+		if *hash == "" {
+			return fmt.Errorf("página %s sem hash de conteúdo no grafo", *pageURL)
+		}
+	}
+
+	store, err := snapshot.OpenStore(*dir)
+	if err != nil {
+		return err
+	}
+	body, err := store.Get(*hash)
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		_, err = os.Stdout.Write(body)
+		return err
+	}
+	return os.WriteFile(*output, body, 0o644)
+}
+
+// loadGraph lê um grafo em JSON ou, pela extensão .ndjson, um fluxo NDJSON.
+func loadGraph(filename string) (*graph.FinalResponse, error) {
+	if strings.HasSuffix(filename, ".ndjson") {
+		return graph.LoadStream(filename)
+	}
+	return graph.Load(filename)
+}
@@ -26,7 +26,7 @@ const docTemplate = `{
     "paths": {
         "/": {
             "get": {
-                "description": "Retorna o status \"OK\" e a versão atual da aplicação.",
+                "description": "Retorna o status \"OK\" e a versão atual da aplicação. Mantido por compatibilidade; prefira GET /healthz.",
                 "produces": [
                     "application/json"
                 ],
@@ -44,6 +44,11 @@ const docTemplate = `{
                 }
             },
             "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "description": "Recebe uma URL e configurações opcionais para iniciar o crawling de uma página web.",
                 "consumes": [
                     "application/json"
@@ -72,12 +77,704 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/crawler.ResponseDTO"
                         }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/usage": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retorna, para cada chave, as requisições e as páginas buscadas no dia corrente (UTC) e a cota restante. Requer o escopo admin.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Consumo das chaves de API",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/auth.Usage"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/batch": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Recebe uma lista de payloads e opções compartilhadas em defaults, aplicadas aos campos omitidos em cada item. Os itens são buscados em paralelo, respeitando o limite de concorrência do servidor. Falhas de um item são retornadas no próprio item, sem falhar o lote. Com stream, a resposta é NDJSON com um resultado por linha, na ordem em que terminam.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "Crawler"
+                ],
+                "summary": "Executa o crawling de várias URLs",
+                "parameters": [
+                    {
+                        "description": "Itens do lote - URL é obrigatório em cada item",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/crawler.BatchPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/crawler.BatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/crawls": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Crawls"
+                ],
+                "summary": "Lista os crawlings de várias páginas",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/live.Job"
+                            }
+                        }
                     }
                 }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Percorre o site em largura a partir de seedUrl, em segundo plano. O progresso pode ser acompanhado em /crawls/{id}/events (SSE) ou /crawls/{id}/ws (WebSocket).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Crawls"
+                ],
+                "summary": "Inicia um crawling de várias páginas",
+                "parameters": [
+                    {
+                        "description": "Crawling - seedUrl é obrigatório",
+                        "name": "crawl",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/live.JobRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/live.Job"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/crawls/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retorna o status e os contadores de progresso do crawling.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Crawls"
+                ],
+                "summary": "Consulta um crawling de várias páginas",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do crawling",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/live.Job"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "Crawls"
+                ],
+                "summary": "Cancela um crawling de várias páginas",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do crawling",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/crawls/{id}/events": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Envia um evento SSE por etapa do crawling (crawl_started, page_started, page_fetched, links_discovered, page_failed, crawl_finished), com os contadores de progresso. O campo id de cada evento pode ser enviado em Last-Event-ID, ou no parâmetro after, para retomar sem perder eventos. A conexão é encerrada ao fim do crawling.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "Crawls"
+                ],
+                "summary": "Acompanha um crawling por Server-Sent Events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do crawling",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Envia apenas eventos posteriores a este id",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Último id recebido",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/live.Message"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/crawls/{id}/ws": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Após o handshake, envia cada evento do crawling como uma mensagem JSON, no mesmo formato do SSE, e fecha a conexão ao fim do crawling. O parâmetro after retoma a partir de um evento. Navegadores, que não definem cabeçalhos no handshake, enviam a chave nos subprotocolos ufape-crawler e api-key.\u003cchave em base64url\u003e.",
+                "tags": [
+                    "Crawls"
+                ],
+                "summary": "Acompanha um crawling por WebSocket",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do crawling",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Envia apenas eventos posteriores a este seq",
+                        "name": "after",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/healthz": {
+            "get": {
+                "description": "Responde sempre que o servidor atende requisições, sem verificar dependências. Use como liveness probe.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Verifica se o processo está vivo",
+                "responses": {
+                    "200": {
+                        "description": "Processo está vivo",
+                        "schema": {
+                            "$ref": "#/definitions/crawler.APIHealth"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Verifica a persistência, a ocupação dos crawlings de várias páginas e, quando configurada, a conectividade de saída. Estados \"degraded\" respondem 200 com os detalhes; \"unavailable\", inclusive durante o encerramento, responde 503.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Verifica se a API pode receber tráfego",
+                "responses": {
+                    "200": {
+                        "description": "Pronta, possivelmente com restrições",
+                        "schema": {
+                            "$ref": "#/definitions/health.Report"
+                        }
+                    },
+                    "503": {
+                        "description": "Não pronta",
+                        "schema": {
+                            "$ref": "#/definitions/health.Report"
+                        }
+                    }
+                }
+            }
+        },
+        "/schedules": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schedules"
+                ],
+                "summary": "Lista os crawlings agendados",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/monitor.Schedule"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Agenda crawlings recorrentes de uma URL, por expressão cron de cinco campos ou por intervalo. Quando uma execução encontra mudanças em relação à anterior, um POST JSON assinado com HMAC-SHA256 é enviado ao webhook. O segredo é retornado apenas nesta resposta.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schedules"
+                ],
+                "summary": "Cria um crawling agendado",
+                "parameters": [
+                    {
+                        "description": "Agendamento - seedUrl e cron ou interval são obrigatórios",
+                        "name": "schedule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/monitor.ScheduleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/monitor.Schedule"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/schedules/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retorna o agendamento, a próxima execução e o resumo das mudanças da última.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schedules"
+                ],
+                "summary": "Consulta um crawling agendado",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do agendamento",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/monitor.Schedule"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "Schedules"
+                ],
+                "summary": "Remove um crawling agendado",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do agendamento",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/schedules/{id}/run": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Inicia uma execução fora do horário previsto. A próxima execução agendada não é alterada.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schedules"
+                ],
+                "summary": "Executa um crawling agendado imediatamente",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do agendamento",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "auth.Scope": {
+            "type": "string",
+            "enum": [
+                "crawl",
+                "jobs",
+                "admin"
+            ],
+            "x-enum-varnames": [
+                "ScopeCrawl",
+                "ScopeJobs",
+                "ScopeAdmin"
+            ]
+        },
+        "auth.Usage": {
+            "type": "object",
+            "properties": {
+                "dailyQuota": {
+                    "type": "integer",
+                    "example": 1000
+                },
+                "day": {
+                    "type": "string",
+                    "example": "2026-01-31"
+                },
+                "keyId": {
+                    "type": "string",
+                    "example": "ci"
+                },
+                "lastUsed": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Integração contínua"
+                },
+                "pages": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "remaining": {
+                    "description": "Remaining é -1 quando a chave não tem cota.",
+                    "type": "integer",
+                    "example": 958
+                },
+                "requests": {
+                    "type": "integer",
+                    "example": 17
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/auth.Scope"
+                    },
+                    "example": [
+                        "crawl"
+                    ]
+                }
             }
-        }
-    },
-    "definitions": {
+        },
         "crawler.APIHealth": {
             "type": "object",
             "properties": {
@@ -91,6 +788,84 @@ const docTemplate = `{
                 }
             }
         },
+        "crawler.BasicAuth": {
+            "type": "object",
+            "required": [
+                "username"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "example": "senha"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "usuario"
+                }
+            }
+        },
+        "crawler.BatchItemResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "invalid url"
+                },
+                "index": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "result": {
+                    "$ref": "#/definitions/crawler.ResponseDTO"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "http://ufape.edu.br"
+                }
+            }
+        },
+        "crawler.BatchPayload": {
+            "type": "object",
+            "required": [
+                "items"
+            ],
+            "properties": {
+                "defaults": {
+                    "$ref": "#/definitions/crawler.Payload"
+                },
+                "items": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/crawler.Payload"
+                    }
+                },
+                "stream": {
+                    "description": "Stream devolve os resultados em NDJSON, um por linha, à medida que terminam.",
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "crawler.BatchResponse": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/crawler.BatchItemResult"
+                    }
+                },
+                "succeeded": {
+                    "type": "integer",
+                    "example": 9
+                }
+            }
+        },
         "crawler.DetailsResponseDTO": {
             "type": "object",
             "properties": {
@@ -106,6 +881,39 @@ const docTemplate = `{
                 }
             }
         },
+        "crawler.ErrorClass": {
+            "type": "string",
+            "enum": [
+                "timeout",
+                "canceled",
+                "dns",
+                "connection_refused",
+                "connection_reset",
+                "tls",
+                "too_many_redirects",
+                "http_status",
+                "invalid_response",
+                "unknown",
+                "blocked",
+                "proxy",
+                "session"
+            ],
+            "x-enum-varnames": [
+                "ErrorClassTimeout",
+                "ErrorClassCanceled",
+                "ErrorClassDNS",
+                "ErrorClassConnectionRefused",
+                "ErrorClassConnectionReset",
+                "ErrorClassTLS",
+                "ErrorClassTooManyRedirects",
+                "ErrorClassHTTPStatus",
+                "ErrorClassInvalidResponse",
+                "ErrorClassUnknown",
+                "ErrorClassBlocked",
+                "ErrorClassProxy",
+                "ErrorClassSession"
+            ]
+        },
         "crawler.LinksResponse": {
             "type": "object",
             "properties": {
@@ -118,6 +926,13 @@ const docTemplate = `{
                         "http://ufape.edu.br/link-valido"
                     ]
                 },
+                "occurrences": {
+                    "description": "Occurrences conta quantas âncoras da página apontam para cada link normalizado.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
                 "unavailable": {
                     "type": "array",
                     "items": {
@@ -132,9 +947,15 @@ const docTemplate = `{
         "crawler.Payload": {
             "type": "object",
             "required": [
+                "cookies",
+                "headers",
                 "url"
             ],
             "properties": {
+                "accept_language": {
+                    "type": "string",
+                    "example": "en-US,en;q=0.9"
+                },
                 "allowed_domains": {
                     "type": "array",
                     "items": {
@@ -144,6 +965,9 @@ const docTemplate = `{
                         "ufape.edu.br"
                     ]
                 },
+                "basic_auth": {
+                    "$ref": "#/definitions/crawler.BasicAuth"
+                },
                 "can_retry": {
                     "type": "boolean",
                     "example": false
@@ -152,31 +976,101 @@ const docTemplate = `{
                     "type": "boolean",
                     "example": true
                 },
+                "cookies": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "headers": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "include_body": {
+                    "type": "string",
+                    "enum": [
+                        "none",
+                        "text",
+                        "base64"
+                    ],
+                    "example": "none"
+                },
                 "lower_case_urls": {
                     "type": "boolean",
                     "example": false
                 },
                 "max_attempts": {
+                    "description": "MaxAttempts é o número de tentativas da busca, até 10. Cada tentativa\nconta como uma página na cota da chave de API.",
                     "type": "integer",
+                    "maximum": 10,
                     "example": 1
                 },
+                "max_body_size": {
+                    "type": "integer",
+                    "maximum": 33554432,
+                    "example": 1048576
+                },
+                "proxy": {
+                    "description": "Proxy é a URL (http, https, socks5 ou socks5h) do proxy desta busca,\naceita apenas quando PROXY_ALLOW_PER_REQUEST está habilitado.",
+                    "type": "string",
+                    "example": "socks5://proxy.ufape.edu.br:1080"
+                },
                 "remove_fragment": {
                     "type": "boolean",
                     "example": false
                 },
+                "session": {
+                    "description": "Session é o nome de uma sessão configurada em CRAWLER_SESSIONS_FILE,\ncujos cookies, obtidos no login, são usados na busca.",
+                    "type": "string",
+                    "example": "intranet"
+                },
                 "timeout": {
+                    "description": "Timeout limita a busca, em segundos, incluindo os redirecionamentos e a\nleitura do corpo. CRAWLER_TIMEOUT continua sendo o limite máximo.",
                     "type": "integer",
                     "example": 60
                 },
                 "url": {
                     "type": "string",
                     "example": "http://ufape.edu.br"
+                },
+                "user_agent": {
+                    "description": "UserAgent substitui o User-Agent configurado no cliente.",
+                    "type": "string",
+                    "example": "ufape-crawler/1.0"
                 }
             }
         },
         "crawler.ResponseDTO": {
             "type": "object",
             "properties": {
+                "body": {
+                    "description": "Body é o corpo da página, presente apenas quando solicitado em include_body,\ncodificado conforme BodyEncoding.",
+                    "type": "string",
+                    "example": "\u003c!DOCTYPE html\u003e..."
+                },
+                "bodyEncoding": {
+                    "type": "string",
+                    "enum": [
+                        "text",
+                        "base64"
+                    ],
+                    "example": "text"
+                },
+                "bodyTruncated": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "charset": {
+                    "description": "Charset é a codificação detectada da página, convertida para UTF-8 antes\nda extração do título e dos links.",
+                    "type": "string",
+                    "example": "windows-1252"
+                },
+                "contentHash": {
+                    "type": "string",
+                    "example": "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+                },
                 "contentType": {
                     "type": "string",
                     "example": "text/html; charset=utf-8"
@@ -188,6 +1082,14 @@ const docTemplate = `{
                     "type": "integer",
                     "example": 150
                 },
+                "errorClass": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/crawler.ErrorClass"
+                        }
+                    ],
+                    "example": "timeout"
+                },
                 "links": {
                     "$ref": "#/definitions/crawler.LinksResponse"
                 },
@@ -265,6 +1167,385 @@ const docTemplate = `{
                     "example": "user"
                 }
             }
+        },
+        "diff.Summary": {
+            "type": "object",
+            "properties": {
+                "addedLinks": {
+                    "type": "integer"
+                },
+                "addedPages": {
+                    "type": "integer"
+                },
+                "contentChanges": {
+                    "type": "integer"
+                },
+                "newlyBrokenLinks": {
+                    "type": "integer"
+                },
+                "removedLinks": {
+                    "type": "integer"
+                },
+                "removedPages": {
+                    "type": "integer"
+                },
+                "statusChanges": {
+                    "type": "integer"
+                },
+                "titleChanges": {
+                    "type": "integer"
+                }
+            }
+        },
+        "extractor.Counters": {
+            "type": "object",
+            "properties": {
+                "discovered": {
+                    "description": "Discovered conta as páginas internas já enfileiradas, incluindo a inicial.",
+                    "type": "integer"
+                },
+                "external": {
+                    "type": "integer"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "fetched": {
+                    "type": "integer"
+                },
+                "links": {
+                    "type": "integer"
+                },
+                "queued": {
+                    "type": "integer"
+                }
+            }
+        },
+        "extractor.EventType": {
+            "type": "string",
+            "enum": [
+                "crawl_started",
+                "page_started",
+                "page_fetched",
+                "links_discovered",
+                "page_failed",
+                "crawl_finished"
+            ],
+            "x-enum-varnames": [
+                "EventStarted",
+                "EventPageStarted",
+                "EventPageFetched",
+                "EventLinksDiscovered",
+                "EventPageFailed",
+                "EventFinished"
+            ]
+        },
+        "extractor.LinkCounts": {
+            "type": "object",
+            "properties": {
+                "external": {
+                    "type": "integer"
+                },
+                "internal": {
+                    "type": "integer"
+                },
+                "new": {
+                    "description": "New conta os links internos que ainda não tinham sido vistos.",
+                    "type": "integer"
+                }
+            }
+        },
+        "health.Report": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/health.Result"
+                    }
+                },
+                "status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/health.Status"
+                        }
+                    ],
+                    "example": "ok"
+                }
+            }
+        },
+        "health.Result": {
+            "type": "object",
+            "properties": {
+                "detail": {
+                    "description": "Detail explica um estado diferente de ok.",
+                    "type": "string",
+                    "example": "2 of 2 crawl slots in use, new crawls are rejected"
+                },
+                "status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/health.Status"
+                        }
+                    ],
+                    "example": "ok"
+                }
+            }
+        },
+        "health.Status": {
+            "type": "string",
+            "enum": [
+                "ok",
+                "degraded",
+                "unavailable"
+            ],
+            "x-enum-varnames": [
+                "StatusOK",
+                "StatusDegraded",
+                "StatusUnavailable"
+            ]
+        },
+        "live.Job": {
+            "type": "object",
+            "properties": {
+                "counters": {
+                    "$ref": "#/definitions/extractor.Counters"
+                },
+                "error": {
+                    "description": "Error explica por que o crawling foi interrompido, quando não foi um cancelamento.",
+                    "type": "string",
+                    "example": "daily page quota exceeded"
+                },
+                "finishedAt": {
+                    "type": "string"
+                },
+                "fullGraph": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "maxDepth": {
+                    "type": "integer"
+                },
+                "owner": {
+                    "type": "string"
+                },
+                "runId": {
+                    "type": "string"
+                },
+                "seedUrl": {
+                    "type": "string"
+                },
+                "session": {
+                    "type": "string"
+                },
+                "startedAt": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/live.Status"
+                }
+            }
+        },
+        "live.JobRequest": {
+            "type": "object",
+            "required": [
+                "seedUrl"
+            ],
+            "properties": {
+                "allowedDomains": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "ufape.edu.br"
+                    ]
+                },
+                "fullGraph": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "maxDepth": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 3
+                },
+                "seedUrl": {
+                    "type": "string",
+                    "example": "https://ufape.edu.br"
+                },
+                "session": {
+                    "description": "Session é a sessão configurada cujos cookies são usados em todas as\npáginas do crawling.",
+                    "type": "string",
+                    "example": "intranet"
+                }
+            }
+        },
+        "live.Message": {
+            "type": "object",
+            "properties": {
+                "counters": {
+                    "$ref": "#/definitions/extractor.Counters"
+                },
+                "depth": {
+                    "type": "integer"
+                },
+                "elapsedTime": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "errorClass": {
+                    "type": "string"
+                },
+                "links": {
+                    "$ref": "#/definitions/extractor.LinkCounts"
+                },
+                "seq": {
+                    "type": "integer"
+                },
+                "statusCode": {
+                    "type": "integer"
+                },
+                "time": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/extractor.EventType"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "live.Status": {
+            "type": "string",
+            "enum": [
+                "running",
+                "completed",
+                "canceled"
+            ],
+            "x-enum-varnames": [
+                "StatusRunning",
+                "StatusCompleted",
+                "StatusCanceled"
+            ]
+        },
+        "monitor.Schedule": {
+            "type": "object",
+            "properties": {
+                "baselineGraph": {
+                    "description": "BaselineGraph é o hash do grafo da última execução concluída no\narmazenamento de grafos, usado quando a persistência está desabilitada.",
+                    "type": "string"
+                },
+                "baselineRunId": {
+                    "description": "BaselineRunID é a última execução concluída, base da próxima comparação\nquando a persistência está habilitada.",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "cron": {
+                    "description": "Cron é uma expressão de cinco campos. Exclusivo com Interval.",
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "fullGraph": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "interval": {
+                    "description": "Interval é uma duração no formato Go, como \"30m\" ou \"6h\".",
+                    "type": "string"
+                },
+                "lastChanges": {
+                    "$ref": "#/definitions/diff.Summary"
+                },
+                "lastError": {
+                    "type": "string"
+                },
+                "lastRunAt": {
+                    "type": "string"
+                },
+                "lastRunId": {
+                    "type": "string"
+                },
+                "lastStatus": {
+                    "type": "string"
+                },
+                "maxDepth": {
+                    "type": "integer"
+                },
+                "nextRunAt": {
+                    "type": "string"
+                },
+                "seedUrl": {
+                    "type": "string"
+                },
+                "webhookSecret": {
+                    "type": "string"
+                },
+                "webhookUrl": {
+                    "description": "WebhookURL recebe um POST assinado quando uma execução encontra mudanças.",
+                    "type": "string"
+                }
+            }
+        },
+        "monitor.ScheduleRequest": {
+            "type": "object",
+            "required": [
+                "seedUrl"
+            ],
+            "properties": {
+                "cron": {
+                    "type": "string",
+                    "example": "0 3 * * *"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "fullGraph": {
+                    "type": "boolean"
+                },
+                "interval": {
+                    "type": "string",
+                    "example": "6h"
+                },
+                "maxDepth": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 3
+                },
+                "seedUrl": {
+                    "type": "string",
+                    "example": "https://ufape.edu.br"
+                },
+                "webhookSecret": {
+                    "type": "string"
+                },
+                "webhookUrl": {
+                    "type": "string",
+                    "example": "https://example.com/hooks/crawler"
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "ApiKeyAuth": {
+            "description": "Chave de API, exigida quando AUTH_ENABLED=true. Também aceita no cabeçalho Authorization: Bearer.",
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
         }
     }
 }`
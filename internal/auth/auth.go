@@ -0,0 +1,300 @@
+// Package auth autentica os clientes da API por chaves de API, com escopos e
+// cotas diárias de páginas buscadas. As chaves são lidas de um arquivo JSON; o
+// consumo fica em memória e recomeça a cada dia (UTC) ou reinício.
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nettojulio/ufape-crawler-golang/internal/metrics"
+)
+
+// Scope limita as rotas que uma chave pode usar.
+type Scope string
+
+const (
+	// ScopeCrawl permite buscar páginas avulsas (POST / e POST /batch).
+	ScopeCrawl Scope = "crawl"
+	// ScopeJobs permite os crawlings de várias páginas (/crawls).
+	ScopeJobs Scope = "jobs"
+	// ScopeAdmin permite os agendamentos e o relatório de consumo, e inclui
+	// os demais escopos.
+	ScopeAdmin Scope = "admin"
+)
+
+var (
+	ErrMissingKey    = errors.New("missing api key")
+	ErrInvalidKey    = errors.New("invalid api key")
+	ErrForbidden     = errors.New("api key lacks the required scope")
+	ErrQuotaExceeded = errors.New("daily page quota exceeded")
+)
+
+// Key é uma chave de API configurada.
+type Key struct {
+	ID     string  `json:"id"`
+	Name   string  `json:"name,omitempty"`
+	Scopes []Scope `json:"scopes"`
+	// DailyQuota é o máximo de páginas buscadas por dia (UTC). Zero não limita.
+	DailyQuota int `json:"dailyQuota,omitempty"`
+
+	// Key é a chave em texto puro. SHA256 é a alternativa para não guardar a
+	// chave no arquivo: o hash SHA-256 da chave, em hexadecimal.
+	Key    string `json:"key,omitempty"`
+	SHA256 string `json:"sha256,omitempty"`
+}
+
+// Allows informa se a chave tem o escopo informado.
+func (k *Key) Allows(scope Scope) bool {
+	for _, s := range k.Scopes {
+		if s == scope || s == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// Usage é o consumo de uma chave no dia corrente.
+type Usage struct {
+	KeyID      string  `json:"keyId" example:"ci"`
+	Name       string  `json:"name,omitempty" example:"Integração contínua"`
+	Scopes     []Scope `json:"scopes" example:"crawl"`
+	DailyQuota int     `json:"dailyQuota" example:"1000"`
+	// Remaining é -1 quando a chave não tem cota.
+	Remaining int       `json:"remaining" example:"958"`
+	Pages     int       `json:"pages" example:"42"`
+	Requests  int       `json:"requests" example:"17"`
+	Day       string    `json:"day" example:"2026-01-31"`
+	LastUsed  time.Time `json:"lastUsed,omitzero"`
+}
+
+// Store guarda as chaves configuradas e o consumo de cada uma.
+type Store struct {
+	keys map[[sha256.Size]byte]*Key
+	now  func() time.Time
+
+	mu    sync.Mutex
+	usage map[string]*Usage
+}
+
+// NewStore valida keys e cria um Store.
+func NewStore(keys []Key) (*Store, error) {
+	s := &Store{
+		keys:  make(map[[sha256.Size]byte]*Key, len(keys)),
+		now:   time.Now,
+		usage: make(map[string]*Usage, len(keys)),
+	}
+	for i := range keys {
+		k := &keys[i]
+		if k.ID == "" {
+			return nil, fmt.Errorf("api key %d: missing id", i)
+		}
+		if _, ok := s.usage[k.ID]; ok {
+			return nil, fmt.Errorf("api key %q: duplicate id", k.ID)
+		}
+		if len(k.Scopes) == 0 {
+			return nil, fmt.Errorf("api key %q: no scopes", k.ID)
+		}
+		for _, scope := range k.Scopes {
+			if scope != ScopeCrawl && scope != ScopeJobs && scope != ScopeAdmin {
+				return nil, fmt.Errorf("api key %q: unknown scope %q", k.ID, scope)
+			}
+		}
+		if k.DailyQuota < 0 {
+			return nil, fmt.Errorf("api key %q: negative daily quota", k.ID)
+		}
+
+		var hash [sha256.Size]byte
+		switch {
+		case k.Key != "" && k.SHA256 != "":
+			return nil, fmt.Errorf("api key %q: set either key or sha256", k.ID)
+		case k.Key != "":
+			hash = sha256.Sum256([]byte(k.Key))
+		case k.SHA256 != "":
+			decoded, err := hex.DecodeString(k.SHA256)
+			if err != nil || len(decoded) != sha256.Size {
+				return nil, fmt.Errorf("api key %q: sha256 must be 64 hex characters", k.ID)
+			}
+			copy(hash[:], decoded)
+		default:
+			return nil, fmt.Errorf("api key %q: missing key or sha256", k.ID)
+		}
+		if _, ok := s.keys[hash]; ok {
+			return nil, fmt.Errorf("api key %q: key already used by another id", k.ID)
+		}
+		k.Key, k.SHA256 = "", ""
+		s.keys[hash] = k
+		s.usage[k.ID] = &Usage{}
+	}
+	return s, nil
+}
+
+// LoadFile lê as chaves de um arquivo JSON com uma lista de Key.
+func LoadFile(path string) (*Store, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read api keys: %w", err)
+	}
+	var keys []Key
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("failed to decode api keys: %w", err)
+	}
+	return NewStore(keys)
+}
+
+// Authenticate retorna a chave correspondente a token.
+func (s *Store) Authenticate(token string) (*Key, error) {
+	if token == "" {
+		return nil, ErrMissingKey
+	}
+	hash := sha256.Sum256([]byte(token))
+	for candidate, key := range s.keys {
+		if subtle.ConstantTimeCompare(hash[:], candidate[:]) == 1 {
+			s.mu.Lock()
+			u := s.usageLocked(key)
+			u.Requests++
+			u.LastUsed = s.now().UTC()
+			s.mu.Unlock()
+			return key, nil
+		}
+	}
+	return nil, ErrInvalidKey
+}
+
+// Charge desconta pages da cota diária de key. A cobrança é integral: se não
+// houver cota para todas as páginas, nada é descontado e ErrQuotaExceeded é
+// retornado. pages igual a zero apenas verifica se ainda resta cota.
+func (s *Store) Charge(key *Key, pages int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u := s.usageLocked(key)
+	if key.DailyQuota > 0 && (u.Pages+pages > key.DailyQuota || u.Pages >= key.DailyQuota) {
+		return ErrQuotaExceeded
+	}
+	u.Pages += pages
+	metrics.APIKeyPages.WithLabelValues(key.ID).Add(float64(pages))
+	return nil
+}
+
+// Usage retorna o consumo de todas as chaves no dia corrente, ordenado por ID.
+func (s *Store) Usage() []Usage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := make([]Usage, 0, len(s.keys))
+	for _, key := range s.keys {
+		u := *s.usageLocked(key)
+		u.KeyID, u.Name, u.Scopes, u.DailyQuota = key.ID, key.Name, key.Scopes, key.DailyQuota
+		u.Remaining = -1
+		if key.DailyQuota > 0 {
+			u.Remaining = max(key.DailyQuota-u.Pages, 0)
+		}
+		list = append(list, u)
+	}
+	sort.Slice(list, func(i, k int) bool { return list[i].KeyID < list[k].KeyID })
+	return list
+}
+
+// usageLocked retorna o consumo de key, zerado quando o dia mudou.
+func (s *Store) usageLocked(key *Key) *Usage {
+	day := s.now().UTC().Format(time.DateOnly)
+	u := s.usage[key.ID]
+	if u.Day != day {
+		*u = Usage{Day: day}
+	}
+	return u
+}
+
+// TokenFromHeaders extrai a chave do cabeçalho X-API-Key ou, na ausência
+// dele, de Authorization: Bearer.
+func TokenFromHeaders(apiKey, authorization string) string {
+	if apiKey != "" {
+		return apiKey
+	}
+	scheme, token, ok := strings.Cut(authorization, " ")
+	if ok && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(token)
+	}
+	return ""
+}
+
+// WebSocketKeyPrefix antecede a chave, em base64url sem preenchimento, no
+// subprotocolo com que os navegadores a enviam no handshake de WebSocket,
+// onde não podem definir cabeçalhos.
+const WebSocketKeyPrefix = "api-key."
+
+// TokenFromWebSocketProtocol extrai a chave do cabeçalho
+// Sec-WebSocket-Protocol, enviada como WebSocketKeyPrefix seguido da chave
+// em base64url.
+func TokenFromWebSocketProtocol(header string) string {
+	for _, protocol := range strings.Split(header, ",") {
+		encoded, ok := strings.CutPrefix(strings.TrimSpace(protocol), WebSocketKeyPrefix)
+		if !ok {
+			continue
+		}
+		if key, err := base64.RawURLEncoding.DecodeString(encoded); err == nil {
+			return string(key)
+		}
+	}
+	return ""
+}
+
+// Caller é o cliente autenticado de uma requisição.
+type Caller struct {
+	Key   *Key
+	store *Store
+}
+
+// Charge desconta pages da cota do cliente. Em um Caller nil, isto é, com a
+// autenticação desabilitada, não faz nada.
+func (c *Caller) Charge(pages int) error {
+	if c == nil {
+		return nil
+	}
+	return c.store.Charge(c.Key, pages)
+}
+
+// Owner retorna o dono dos recursos criados pelo cliente, como os
+// crawlings: o ID da chave, ou "" com a autenticação desabilitada.
+func (c *Caller) Owner() string {
+	if c == nil {
+		return ""
+	}
+	return c.Key.ID
+}
+
+// Visible retorna o dono cujos recursos o cliente pode consultar: o próprio
+// ou "", que vale para todos, com o escopo admin ou com a autenticação
+// desabilitada.
+func (c *Caller) Visible() string {
+	if c == nil || slices.Contains(c.Key.Scopes, ScopeAdmin) {
+		return ""
+	}
+	return c.Key.ID
+}
+
+type contextKey struct{}
+
+// WithCaller retorna uma cópia de ctx que carrega o cliente autenticado por key.
+func WithCaller(ctx context.Context, store *Store, key *Key) context.Context {
+	return context.WithValue(ctx, contextKey{}, &Caller{Key: key, store: store})
+}
+
+// CallerFromContext retorna o cliente guardado por WithCaller, ou nil.
+func CallerFromContext(ctx context.Context) *Caller {
+	caller, _ := ctx.Value(contextKey{}).(*Caller)
+	return caller
+}
@@ -0,0 +1,161 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	hash := sha256.Sum256([]byte("admin-secret"))
+	s, err := NewStore([]Key{
+		{ID: "ci", Name: "Integração contínua", Key: "ci-secret", Scopes: []Scope{ScopeCrawl}, DailyQuota: 3},
+		{ID: "ops", SHA256: hex.EncodeToString(hash[:]), Scopes: []Scope{ScopeAdmin}},
+	})
+	if err != nil {
+		t.Fatalf("NewStore returned an error: %v", err)
+	}
+	return s
+}
+
+func TestNewStoreValidation(t *testing.T) {
+	tests := map[string][]Key{
+		"missing id":     {{Key: "k", Scopes: []Scope{ScopeCrawl}}},
+		"duplicate id":   {{ID: "a", Key: "k1", Scopes: []Scope{ScopeCrawl}}, {ID: "a", Key: "k2", Scopes: []Scope{ScopeCrawl}}},
+		"duplicate key":  {{ID: "a", Key: "k", Scopes: []Scope{ScopeCrawl}}, {ID: "b", Key: "k", Scopes: []Scope{ScopeCrawl}}},
+		"no scopes":      {{ID: "a", Key: "k"}},
+		"unknown scope":  {{ID: "a", Key: "k", Scopes: []Scope{"root"}}},
+		"missing key":    {{ID: "a", Scopes: []Scope{ScopeCrawl}}},
+		"key and sha256": {{ID: "a", Key: "k", SHA256: "00", Scopes: []Scope{ScopeCrawl}}},
+		"invalid sha256": {{ID: "a", SHA256: "not-hex", Scopes: []Scope{ScopeCrawl}}},
+		"negative quota": {{ID: "a", Key: "k", Scopes: []Scope{ScopeCrawl}, DailyQuota: -1}},
+	}
+	for name, keys := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewStore(keys); err == nil {
+				t.Error("expected a validation error")
+			}
+		})
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	s := newTestStore(t)
+
+	key, err := s.Authenticate("ci-secret")
+	if err != nil || key.ID != "ci" {
+		t.Fatalf("expected the ci key, got %+v, %v", key, err)
+	}
+	if !key.Allows(ScopeCrawl) || key.Allows(ScopeJobs) || key.Allows(ScopeAdmin) {
+		t.Errorf("unexpected scopes for ci: %v", key.Scopes)
+	}
+
+	key, err = s.Authenticate("admin-secret")
+	if err != nil || key.ID != "ops" {
+		t.Fatalf("expected the key configured by hash, got %+v, %v", key, err)
+	}
+	if !key.Allows(ScopeCrawl) || !key.Allows(ScopeJobs) {
+		t.Error("expected admin to include the other scopes")
+	}
+
+	if _, err := s.Authenticate(""); !errors.Is(err, ErrMissingKey) {
+		t.Errorf("expected ErrMissingKey, got %v", err)
+	}
+	if _, err := s.Authenticate("wrong"); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("expected ErrInvalidKey, got %v", err)
+	}
+}
+
+func TestTokenFromHeaders(t *testing.T) {
+	if got := TokenFromHeaders("header-key", "Bearer other"); got != "header-key" {
+		t.Errorf("expected X-API-Key to take precedence, got %q", got)
+	}
+	if got := TokenFromHeaders("", "bearer token-1"); got != "token-1" {
+		t.Errorf("expected the bearer token, got %q", got)
+	}
+	if got := TokenFromHeaders("", "Basic dXNlcjpwYXNz"); got != "" {
+		t.Errorf("expected other schemes to be ignored, got %q", got)
+	}
+}
+
+func TestChargeAndUsage(t *testing.T) {
+	s := newTestStore(t)
+	today := time.Date(2026, 1, 31, 23, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return today }
+
+	key, _ := s.Authenticate("ci-secret")
+	if err := s.Charge(key, 2); err != nil {
+		t.Fatalf("Charge returned an error: %v", err)
+	}
+	if err := s.Charge(key, 2); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("expected the whole charge to be refused, got %v", err)
+	}
+	if err := s.Charge(key, 1); err != nil {
+		t.Fatalf("expected the remaining page to be charged, got %v", err)
+	}
+	if err := s.Charge(key, 0); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("expected an exhausted quota to refuse even a check, got %v", err)
+	}
+
+	usage := s.Usage()
+	if len(usage) != 2 || usage[0].KeyID != "ci" || usage[1].KeyID != "ops" {
+		t.Fatalf("expected usage for both keys ordered by id, got %+v", usage)
+	}
+	if u := usage[0]; u.Pages != 3 || u.Remaining != 0 || u.Requests != 1 || u.Day != "2026-01-31" {
+		t.Errorf("unexpected ci usage: %+v", u)
+	}
+	if u := usage[1]; u.Remaining != -1 || u.Pages != 0 {
+		t.Errorf("expected ops without quota, got %+v", u)
+	}
+
+	today = today.Add(2 * time.Hour)
+	if err := s.Charge(key, 3); err != nil {
+		t.Errorf("expected the quota to reset on a new day, got %v", err)
+	}
+}
+
+func TestCaller(t *testing.T) {
+	var none *Caller
+	if err := none.Charge(100); err != nil {
+		t.Errorf("expected a nil caller to charge nothing, got %v", err)
+	}
+	if CallerFromContext(context.Background()) != nil {
+		t.Error("expected no caller in an empty context")
+	}
+
+	s := newTestStore(t)
+	key, _ := s.Authenticate("ci-secret")
+	caller := CallerFromContext(WithCaller(context.Background(), s, key))
+	if caller == nil || caller.Key.ID != "ci" {
+		t.Fatalf("expected the ci caller, got %+v", caller)
+	}
+	if err := caller.Charge(4); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("expected the caller to use the key quota, got %v", err)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api_keys.json")
+	data := `[{"id": "ci", "key": "ci-secret", "scopes": ["crawl", "jobs"], "dailyQuota": 100}]`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile returned an error: %v", err)
+	}
+	if key, err := s.Authenticate("ci-secret"); err != nil || key.DailyQuota != 100 || !key.Allows(ScopeJobs) {
+		t.Errorf("unexpected key loaded: %+v, %v", key, err)
+	}
+
+	if _, err := LoadFile(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
@@ -3,18 +3,211 @@ package config
 import (
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/caarlos0/env/v10"
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	Version string `env:"APP_VERSION"`
-	Port    int    `env:"APP_PORT" envDefault:"8080"`
-	Host    string `env:"APP_HOST" envDefault:"localhost:8080"`
+	Version   string          `env:"APP_VERSION" yaml:"version"`
+	Port      int             `env:"APP_PORT" envDefault:"8080" yaml:"port"`
+	Host      string          `env:"APP_HOST" envDefault:"localhost:8080" yaml:"host"`
+	Log       LogConfig       `yaml:"log"`
+	Storage   StorageConfig   `yaml:"storage"`
+	Scheduler SchedulerConfig `yaml:"scheduler"`
+	Archive   ArchiveConfig   `yaml:"archive"`
+	Batch     BatchConfig     `yaml:"batch"`
+	Crawls    CrawlsConfig    `yaml:"crawls"`
+	GRPC      GRPCConfig      `yaml:"grpc"`
+	Tracing   TracingConfig   `yaml:"tracing"`
+	Health    HealthConfig    `yaml:"health"`
+	Auth      AuthConfig      `yaml:"auth"`
+	RateLimit RateLimitConfig `yaml:"rateLimit"`
+	SSRF      SSRFConfig      `yaml:"ssrf"`
+	Crawler   CrawlerConfig   `yaml:"crawler"`
+	Extractor ExtractorConfig `yaml:"extractor"`
 }
 
-// Load carrega as configurações da aplicação
+// LogConfig define o nível e o formato dos logs.
+type LogConfig struct {
+	// Level aceita "debug", "info", "warn" ou "error".
+	Level string `env:"LOG_LEVEL" envDefault:"info" yaml:"level"`
+	// Format aceita "json" ou "text". Vazio usa o padrão de cada programa:
+	// JSON na API e texto nas ferramentas de linha de comando.
+	Format string `env:"LOG_FORMAT" yaml:"format"`
+}
+
+// StorageConfig define o backend de persistência dos crawlings.
+type StorageConfig struct {
+	// Driver aceita "none", "file" ou "postgres".
+	Driver string `env:"STORAGE_DRIVER" envDefault:"none" yaml:"driver"`
+	DSN    string `env:"STORAGE_DSN" yaml:"dsn"`
+	Path   string `env:"STORAGE_PATH" envDefault:"data" yaml:"path"`
+}
+
+// SchedulerConfig define os crawlings agendados executados pelo servidor.
+type SchedulerConfig struct {
+	Enabled bool `env:"SCHEDULER_ENABLED" envDefault:"true" yaml:"enabled"`
+	// File guarda os agendamentos entre reinícios. Vazio os mantém apenas em memória.
+	File           string        `env:"SCHEDULER_FILE" envDefault:"data/schedules.json" yaml:"file"`
+	WebhookTimeout time.Duration `env:"SCHEDULER_WEBHOOK_TIMEOUT" envDefault:"10s" yaml:"webhookTimeout"`
+	// SnapshotDir, quando definido, guarda o HTML das páginas de cada execução.
+	SnapshotDir string `env:"SCHEDULER_SNAPSHOT_DIR" yaml:"snapshotDir"`
+}
+
+// ArchiveConfig define o arquivamento WARC das páginas buscadas pelo crawler.
+type ArchiveConfig struct {
+	// Dir habilita o arquivamento quando definido.
+	Dir     string `env:"WARC_DIR" yaml:"dir"`
+	Prefix  string `env:"WARC_PREFIX" envDefault:"ufape-crawler" yaml:"prefix"`
+	MaxSize int64  `env:"WARC_MAX_SIZE" envDefault:"1073741824" yaml:"maxSize"`
+	Gzip    bool   `env:"WARC_GZIP" envDefault:"true" yaml:"gzip"`
+}
+
+// BatchConfig limita o endpoint de crawling em lote.
+type BatchConfig struct {
+	MaxItems int `env:"BATCH_MAX_ITEMS" envDefault:"100" yaml:"maxItems"`
+	// Concurrency é o máximo de buscas simultâneas, somando todos os lotes em andamento.
+	Concurrency int `env:"BATCH_CONCURRENCY" envDefault:"8" yaml:"concurrency"`
+}
+
+// CrawlsConfig limita os crawlings de várias páginas iniciados pela API.
+type CrawlsConfig struct {
+	MaxRunning int `env:"CRAWLS_MAX_RUNNING" envDefault:"2" yaml:"maxRunning"`
+	// Retain é quantos crawlings encerrados permanecem consultáveis.
+	Retain int `env:"CRAWLS_RETAIN" envDefault:"50" yaml:"retain"`
+	// MaxDepth é a maior profundidade aceita em um crawling.
+	MaxDepth int `env:"CRAWLS_MAX_DEPTH" envDefault:"10" yaml:"maxDepth"`
+}
+
+// GRPCConfig configura o servidor gRPC que roda ao lado da API REST.
+type GRPCConfig struct {
+	Enabled bool `env:"GRPC_ENABLED" envDefault:"true" yaml:"enabled"`
+	Port    int  `env:"GRPC_PORT" envDefault:"9090" yaml:"port"`
+}
+
+// TracingConfig define a exportação dos traces OpenTelemetry.
+type TracingConfig struct {
+	// Exporter aceita "none", "otlp", "stdout" ou "file". O OTLP é configurado
+	// pelas variáveis padrão OTEL_EXPORTER_OTLP_*.
+	Exporter    string  `env:"TRACING_EXPORTER" envDefault:"none" yaml:"exporter"`
+	File        string  `env:"TRACING_FILE" envDefault:"traces.json" yaml:"file"`
+	ServiceName string  `env:"TRACING_SERVICE_NAME" envDefault:"ufape-crawler" yaml:"serviceName"`
+	SampleRatio float64 `env:"TRACING_SAMPLE_RATIO" envDefault:"1" yaml:"sampleRatio"`
+}
+
+// HealthConfig configura as verificações do GET /readyz.
+type HealthConfig struct {
+	// Timeout limita cada verificação.
+	Timeout time.Duration `env:"HEALTH_TIMEOUT" envDefault:"2s" yaml:"timeout"`
+	// ProbeURL, quando definida, é buscada para verificar a conectividade de saída.
+	ProbeURL string `env:"HEALTH_PROBE_URL" yaml:"probeUrl"`
+	// ShutdownDelay é quanto tempo o /readyz responde 503 antes de o servidor
+	// parar de aceitar conexões, para que o balanceador retire a instância.
+	ShutdownDelay time.Duration `env:"HEALTH_SHUTDOWN_DELAY" envDefault:"0s" yaml:"shutdownDelay"`
+}
+
+// AuthConfig define a autenticação por chaves de API.
+type AuthConfig struct {
+	Enabled bool `env:"AUTH_ENABLED" envDefault:"false" yaml:"enabled"`
+	// KeysFile é o arquivo JSON com as chaves, seus escopos e cotas.
+	KeysFile string `env:"AUTH_KEYS_FILE" envDefault:"api_keys.json" yaml:"keysFile"`
+}
+
+// RateLimitConfig limita a taxa de requisições de cada cliente, identificado
+// pela chave de API ou pelo IP. Cada cliente pode fazer Burst requisições
+// seguidas e recupera Rate requisições a cada Window.
+type RateLimitConfig struct {
+	Enabled bool          `env:"RATE_LIMIT_ENABLED" envDefault:"false" yaml:"enabled"`
+	Window  time.Duration `env:"RATE_LIMIT_WINDOW" envDefault:"1m" yaml:"window"`
+	// CrawlRate e CrawlBurst valem para o POST / e o Crawl do gRPC.
+	CrawlRate  int `env:"RATE_LIMIT_CRAWL_RATE" envDefault:"60" yaml:"crawlRate"`
+	CrawlBurst int `env:"RATE_LIMIT_CRAWL_BURST" envDefault:"10" yaml:"crawlBurst"`
+	// JobsRate e JobsBurst valem para o POST /batch, o POST /crawls, o POST
+	// /schedules/{id}/run e o CrawlSite do gRPC.
+	JobsRate  int `env:"RATE_LIMIT_JOBS_RATE" envDefault:"6" yaml:"jobsRate"`
+	JobsBurst int `env:"RATE_LIMIT_JOBS_BURST" envDefault:"2" yaml:"jobsBurst"`
+	// TrustProxy usa o cabeçalho X-Forwarded-For para identificar o IP do
+	// cliente. Habilite apenas atrás de um proxy reverso que o defina.
+	TrustProxy bool `env:"RATE_LIMIT_TRUST_PROXY" envDefault:"false" yaml:"trustProxy"`
+}
+
+// SSRFConfig impede que o crawler da API busque endereços internos, como
+// loopback, redes privadas e os serviços de metadados das nuvens.
+type SSRFConfig struct {
+	Enabled bool `env:"SSRF_PROTECTION" envDefault:"true" yaml:"enabled"`
+	// Allowlist lista, separados por vírgula, os destinos internos permitidos:
+	// endereços IP, faixas CIDR ou nomes de host.
+	Allowlist []string `env:"SSRF_ALLOWLIST" envSeparator:"," yaml:"allowlist"`
+}
+
+// CrawlerConfig define o cliente HTTP do crawler e o intervalo entre as
+// tentativas de uma busca.
+type CrawlerConfig struct {
+	// Timeout limita cada busca, incluindo os redirecionamentos. O timeout do
+	// payload só pode reduzi-lo.
+	Timeout      time.Duration `env:"CRAWLER_TIMEOUT" envDefault:"60s" yaml:"timeout"`
+	UserAgent    string        `env:"CRAWLER_USER_AGENT" envDefault:"Mozilla/5.0 (X11; Linux x86_64; rv:145.0) Gecko/20100101 Firefox/145.0" yaml:"userAgent"`
+	MaxRedirects int           `env:"CRAWLER_MAX_REDIRECTS" envDefault:"10" yaml:"maxRedirects"`
+	RetryDelay   time.Duration `env:"CRAWLER_RETRY_DELAY" envDefault:"1s" yaml:"retryDelay"`
+	// SessionsFile é o arquivo JSON com as sessões nomeadas e seus logins.
+	// Vazio desabilita as sessões.
+	SessionsFile string        `env:"CRAWLER_SESSIONS_FILE" yaml:"sessionsFile"`
+	Payload      PayloadConfig `yaml:"payload"`
+	Proxy        ProxyConfig   `yaml:"proxy"`
+}
+
+// ProxyConfig define os proxies de saída do crawler.
+type ProxyConfig struct {
+	// URLs lista, separadas por vírgula, as URLs dos proxies (http, https,
+	// socks5 ou socks5h). Vazio usa HTTP_PROXY, HTTPS_PROXY e NO_PROXY.
+	URLs []string `env:"PROXY_URLS" envSeparator:"," yaml:"urls"`
+	// Strategy aceita "round-robin" ou "sticky" (o mesmo proxy para cada host).
+	Strategy string `env:"PROXY_STRATEGY" envDefault:"round-robin" yaml:"strategy"`
+	// MaxFailures é quantas falhas de conexão seguidas afastam um proxy.
+	MaxFailures int `env:"PROXY_MAX_FAILURES" envDefault:"3" yaml:"maxFailures"`
+	// HealthInterval é o intervalo entre as verificações dos proxies, que
+	// devolvem ao pool os afastados que voltaram a responder. Zero desabilita.
+	HealthInterval time.Duration `env:"PROXY_HEALTH_INTERVAL" envDefault:"30s" yaml:"healthInterval"`
+	HealthTimeout  time.Duration `env:"PROXY_HEALTH_TIMEOUT" envDefault:"5s" yaml:"healthTimeout"`
+	// AllowPerRequest permite que cada requisição escolha seu proxy no campo
+	// proxy do payload.
+	AllowPerRequest bool `env:"PROXY_ALLOW_PER_REQUEST" envDefault:"false" yaml:"allowPerRequest"`
+}
+
+// PayloadConfig define os valores dos campos omitidos no corpo das
+// requisições de crawling.
+type PayloadConfig struct {
+	Timeout           int  `env:"PAYLOAD_TIMEOUT" envDefault:"60" yaml:"timeout"`
+	RemoveFragment    bool `env:"PAYLOAD_REMOVE_FRAGMENT" envDefault:"true" yaml:"removeFragment"`
+	CollectSubdomains bool `env:"PAYLOAD_COLLECT_SUBDOMAINS" envDefault:"true" yaml:"collectSubdomains"`
+	LowerCaseURLs     bool `env:"PAYLOAD_LOWER_CASE_URLS" envDefault:"false" yaml:"lowerCaseUrls"`
+	CanRetry          bool `env:"PAYLOAD_CAN_RETRY" envDefault:"false" yaml:"canRetry"`
+	// MaxAttempts vale quando canRetry é false e RetryMaxAttempts quando é true.
+	MaxAttempts      int `env:"PAYLOAD_MAX_ATTEMPTS" envDefault:"1" yaml:"maxAttempts"`
+	RetryMaxAttempts int `env:"PAYLOAD_RETRY_MAX_ATTEMPTS" envDefault:"10" yaml:"retryMaxAttempts"`
+	// IncludeBody aceita "none", "text" ou "base64".
+	IncludeBody string `env:"PAYLOAD_INCLUDE_BODY" envDefault:"none" yaml:"includeBody"`
+	MaxBodySize int    `env:"PAYLOAD_MAX_BODY_SIZE" envDefault:"1048576" yaml:"maxBodySize"`
+}
+
+// ExtractorConfig define o crawling executado pelo cmd/extractor.
+type ExtractorConfig struct {
+	APIURL  string `env:"API_URL" envDefault:"http://localhost:8080/" yaml:"apiUrl"`
+	SeedURL string `env:"EXTRACTOR_SEED_URL" envDefault:"https://ufape.edu.br" yaml:"seedUrl"`
+	// AllowedDomains limita os domínios visitados, separados por vírgula.
+	AllowedDomains []string `env:"EXTRACTOR_ALLOWED_DOMAINS" envSeparator:"," envDefault:"ufape.edu.br" yaml:"allowedDomains"`
+	// MaxDepth limita a profundidade a partir da semente. Zero não limita.
+	MaxDepth int           `env:"EXTRACTOR_MAX_DEPTH" envDefault:"0" yaml:"maxDepth"`
+	Timeout  time.Duration `env:"EXTRACTOR_TIMEOUT" envDefault:"60s" yaml:"timeout"`
+	// Session é a sessão de CRAWLER_SESSIONS_FILE usada em todas as páginas.
+	Session string `env:"EXTRACTOR_SESSION" yaml:"session"`
+}
+
+// Load carrega as configurações da aplicação. Os valores vêm, em ordem de
+// prioridade, das variáveis de ambiente (incluindo o .env), do arquivo
+// indicado por CONFIG_FILE e dos padrões. O resultado é validado.
 func Load(version string) (*Config, error) {
 	if err := godotenv.Load(); err != nil {
 		slog.Warn("no .env file found, using environment variables")
@@ -24,9 +217,16 @@ func Load(version string) (*Config, error) {
 		Version: version,
 	}
 
-	if err := env.Parse(&cfg); err != nil {
+	environment, err := environment()
+	if err != nil {
+		return nil, err
+	}
+	if err := env.ParseWithOptions(&cfg, env.Options{Environment: environment}); err != nil {
 		return nil, fmt.Errorf("failed to parse config from environment: %w", err)
 	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
 
 	return &cfg, nil
 }
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/caarlos0/env/v10"
+	"go.yaml.in/yaml/v3"
+)
+
+// FileEnv é a variável de ambiente com o caminho do arquivo de configuração
+// opcional, em YAML (.yaml ou .yml) ou TOML (.toml).
+const FileEnv = "CONFIG_FILE"
+
+// environment retorna as variáveis usadas pelo parse: as do arquivo de
+// configuração, quando houver, sobrescritas pelas do ambiente.
+func environment() (map[string]string, error) {
+	vars := make(map[string]string)
+	if path := os.Getenv(FileEnv); path != "" {
+		fileVars, err := readFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load config file %s: %w", path, err)
+		}
+		vars = fileVars
+	}
+	for key, value := range env.ToMap(os.Environ()) {
+		vars[key] = value
+	}
+	return vars, nil
+}
+
+// readFile lê o arquivo de configuração e o converte nas variáveis de ambiente
+// equivalentes. As chaves seguem as tags yaml de Config, com uma seção por
+// grupo (por exemplo, crawler.maxRedirects equivale a CRAWLER_MAX_REDIRECTS).
+// Chaves desconhecidas são recusadas.
+func readFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := make(map[string]any)
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &doc)
+	case ".toml":
+		err = toml.Unmarshal(data, &doc)
+	default:
+		return nil, fmt.Errorf("unsupported config file extension %q, use .yaml, .yml or .toml", ext)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	vars := make(map[string]string)
+	if err := flatten(reflect.TypeFor[Config](), doc, "", vars); err != nil {
+		return nil, err
+	}
+	return vars, nil
+}
+
+// flatten percorre doc guiado pelos campos de t, gravando em vars o valor de
+// cada campo com a tag env.
+func flatten(t reflect.Type, doc map[string]any, prefix string, vars map[string]string) error {
+	fields := make(map[string]reflect.StructField, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if name := field.Tag.Get("yaml"); name != "" {
+			fields[name] = field
+		}
+	}
+
+	keys := make([]string, 0, len(doc))
+	for key := range doc {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		name := prefix + key
+		field, ok := fields[key]
+		if !ok {
+			return fmt.Errorf("unknown key %q", name)
+		}
+		if envName := field.Tag.Get("env"); envName != "" {
+			value, err := scalar(doc[key], field.Tag.Get("envSeparator"))
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			vars[envName] = value
+			continue
+		}
+		section, ok := doc[key].(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: expected a section", name)
+		}
+		if err := flatten(field.Type, section, name+".", vars); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scalar converte um valor do arquivo no texto da variável de ambiente. Listas
+// são aceitas nos campos com envSeparator.
+func scalar(value any, separator string) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case map[string]any:
+		return "", errors.New("expected a value, got a section")
+	case []any:
+		if separator == "" {
+			return "", errors.New("expected a single value, got a list")
+		}
+		items := make([]string, len(v))
+		for i, item := range v {
+			s, err := scalar(item, "")
+			if err != nil {
+				return "", err
+			}
+			items[i] = s
+		}
+		return strings.Join(items, separator), nil
+	default:
+		return fmt.Sprint(v), nil
+	}
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"io"
+	"net/url"
+	"regexp"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// redactedPassword substitui as senhas na saída de WriteYAML.
+const redactedPassword = "xxxxx"
+
+var dsnPassword = regexp.MustCompile(`(password=)('[^']*'|\S+)`)
+
+// WriteYAML escreve em w os valores efetivos de c no formato do arquivo de
+// configuração, com as senhas de STORAGE_DSN e PROXY_URLS ocultadas. A saída
+// pode ser usada como ponto de partida para um CONFIG_FILE.
+func (c Config) WriteYAML(w io.Writer) error {
+	c.Storage.DSN = redactDSN(c.Storage.DSN)
+	proxies := make([]string, len(c.Crawler.Proxy.URLs))
+	for i, raw := range c.Crawler.Proxy.URLs {
+		proxies[i] = redactURL(raw)
+	}
+	c.Crawler.Proxy.URLs = proxies
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(c); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// redactDSN oculta a senha de um DSN no formato URL ou chave=valor.
+func redactDSN(dsn string) string {
+	if u, err := url.Parse(dsn); err == nil && u.User != nil {
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(u.User.Username(), redactedPassword)
+			return u.String()
+		}
+	}
+	return dsnPassword.ReplaceAllString(dsn, "${1}"+redactedPassword)
+}
+
+// redactURL oculta a senha de uma URL.
+func redactURL(raw string) string {
+	if u, err := url.Parse(raw); err == nil {
+		return u.Redacted()
+	}
+	return "<invalid url>"
+}
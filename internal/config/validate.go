@@ -0,0 +1,165 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Validate verifica os valores carregados e retorna todos os problemas
+// encontrados, cada um identificado pela variável de ambiente correspondente.
+func (c *Config) Validate() error {
+	v := &validator{}
+	v.port("APP_PORT", c.Port)
+	c.Log.validate(v)
+	c.Storage.validate(v)
+	c.Scheduler.validate(v)
+	c.Archive.validate(v)
+	c.Batch.validate(v)
+	c.Crawls.validate(v)
+	c.GRPC.validate(v)
+	c.Tracing.validate(v)
+	c.Health.validate(v)
+	c.RateLimit.validate(v)
+	c.Crawler.validate(v)
+	c.Extractor.validate(v)
+	return v.err()
+}
+
+func (c *LogConfig) validate(v *validator) {
+	var level slog.Level
+	v.check(level.UnmarshalText([]byte(c.Level)) == nil, "LOG_LEVEL", "must be debug, info, warn or error, got %q", c.Level)
+	v.oneOf("LOG_FORMAT", strings.ToLower(c.Format), "", "json", "text")
+}
+
+func (c *StorageConfig) validate(v *validator) {
+	v.oneOf("STORAGE_DRIVER", c.Driver, "", "none", "file", "postgres")
+	v.check(c.Driver != "postgres" || c.DSN != "", "STORAGE_DSN", "is required for the postgres storage driver")
+	v.check(c.Driver != "file" || c.Path != "", "STORAGE_PATH", "is required for the file storage driver")
+}
+
+func (c *SchedulerConfig) validate(v *validator) {
+	v.positiveDuration("SCHEDULER_WEBHOOK_TIMEOUT", c.WebhookTimeout)
+}
+
+func (c *ArchiveConfig) validate(v *validator) {
+	v.check(c.MaxSize > 0, "WARC_MAX_SIZE", "must be positive, got %d", c.MaxSize)
+}
+
+func (c *BatchConfig) validate(v *validator) {
+	v.positive("BATCH_MAX_ITEMS", c.MaxItems)
+	v.positive("BATCH_CONCURRENCY", c.Concurrency)
+}
+
+func (c *CrawlsConfig) validate(v *validator) {
+	v.positive("CRAWLS_MAX_RUNNING", c.MaxRunning)
+	v.check(c.Retain >= 0, "CRAWLS_RETAIN", "must not be negative, got %d", c.Retain)
+	v.positive("CRAWLS_MAX_DEPTH", c.MaxDepth)
+}
+
+func (c *GRPCConfig) validate(v *validator) {
+	if c.Enabled {
+		v.port("GRPC_PORT", c.Port)
+	}
+}
+
+func (c *TracingConfig) validate(v *validator) {
+	v.oneOf("TRACING_EXPORTER", c.Exporter, "", "none", "otlp", "stdout", "file")
+	v.check(c.SampleRatio >= 0 && c.SampleRatio <= 1, "TRACING_SAMPLE_RATIO", "must be between 0 and 1, got %g", c.SampleRatio)
+}
+
+func (c *HealthConfig) validate(v *validator) {
+	v.positiveDuration("HEALTH_TIMEOUT", c.Timeout)
+	v.check(c.ShutdownDelay >= 0, "HEALTH_SHUTDOWN_DELAY", "must not be negative, got %s", c.ShutdownDelay)
+	if c.ProbeURL != "" {
+		v.httpURL("HEALTH_PROBE_URL", c.ProbeURL)
+	}
+}
+
+func (c *RateLimitConfig) validate(v *validator) {
+	if !c.Enabled {
+		return
+	}
+	v.positiveDuration("RATE_LIMIT_WINDOW", c.Window)
+	v.positive("RATE_LIMIT_CRAWL_RATE", c.CrawlRate)
+	v.positive("RATE_LIMIT_JOBS_RATE", c.JobsRate)
+}
+
+func (c *CrawlerConfig) validate(v *validator) {
+	v.positiveDuration("CRAWLER_TIMEOUT", c.Timeout)
+	v.check(strings.TrimSpace(c.UserAgent) != "", "CRAWLER_USER_AGENT", "must not be empty")
+	v.check(c.MaxRedirects >= 0, "CRAWLER_MAX_REDIRECTS", "must not be negative, got %d", c.MaxRedirects)
+	v.check(c.RetryDelay >= 0, "CRAWLER_RETRY_DELAY", "must not be negative, got %s", c.RetryDelay)
+
+	p := c.Payload
+	v.positive("PAYLOAD_TIMEOUT", p.Timeout)
+	v.positive("PAYLOAD_MAX_ATTEMPTS", p.MaxAttempts)
+	v.positive("PAYLOAD_RETRY_MAX_ATTEMPTS", p.RetryMaxAttempts)
+	// O mesmo limite da validação de max_attempts em crawler.Payload.
+	v.check(p.MaxAttempts <= 10, "PAYLOAD_MAX_ATTEMPTS", "must be at most 10, got %d", p.MaxAttempts)
+	v.check(p.RetryMaxAttempts <= 10, "PAYLOAD_RETRY_MAX_ATTEMPTS", "must be at most 10, got %d", p.RetryMaxAttempts)
+	v.oneOf("PAYLOAD_INCLUDE_BODY", p.IncludeBody, "none", "text", "base64")
+	v.positive("PAYLOAD_MAX_BODY_SIZE", p.MaxBodySize)
+
+	x := c.Proxy
+	v.oneOf("PROXY_STRATEGY", x.Strategy, "round-robin", "sticky")
+	v.positive("PROXY_MAX_FAILURES", x.MaxFailures)
+	v.check(x.HealthInterval >= 0, "PROXY_HEALTH_INTERVAL", "must not be negative, got %s", x.HealthInterval)
+	v.positiveDuration("PROXY_HEALTH_TIMEOUT", x.HealthTimeout)
+	for _, raw := range x.URLs {
+		u, err := url.Parse(raw)
+		v.check(err == nil && slices.Contains([]string{"http", "https", "socks5", "socks5h"}, u.Scheme) && u.Host != "",
+			"PROXY_URLS", "must contain http, https, socks5 or socks5h URLs, got %q", redactURL(raw))
+	}
+}
+
+func (c *ExtractorConfig) validate(v *validator) {
+	v.httpURL("API_URL", c.APIURL)
+	v.httpURL("EXTRACTOR_SEED_URL", c.SeedURL)
+	v.check(c.MaxDepth >= 0, "EXTRACTOR_MAX_DEPTH", "must not be negative, got %d", c.MaxDepth)
+	v.positiveDuration("EXTRACTOR_TIMEOUT", c.Timeout)
+}
+
+// validator acumula os problemas encontrados por Validate.
+type validator struct {
+	errs []error
+}
+
+func (v *validator) check(ok bool, key, format string, args ...any) {
+	if !ok {
+		v.errs = append(v.errs, fmt.Errorf("%s %s", key, fmt.Sprintf(format, args...)))
+	}
+}
+
+func (v *validator) port(key string, port int) {
+	v.check(port > 0 && port <= 65535, key, "must be between 1 and 65535, got %d", port)
+}
+
+func (v *validator) positive(key string, n int) {
+	v.check(n > 0, key, "must be positive, got %d", n)
+}
+
+func (v *validator) positiveDuration(key string, d time.Duration) {
+	v.check(d > 0, key, "must be positive, got %s", d)
+}
+
+func (v *validator) oneOf(key, value string, allowed ...string) {
+	if slices.Contains(allowed, value) {
+		return
+	}
+	listed := slices.DeleteFunc(slices.Clone(allowed), func(s string) bool { return s == "" })
+	v.check(false, key, "must be one of %s, got %q", strings.Join(listed, ", "), value)
+}
+
+func (v *validator) httpURL(key, raw string) {
+	u, err := url.Parse(raw)
+	v.check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "", key, "must be an http or https URL, got %q", raw)
+}
+
+func (v *validator) err() error {
+	return errors.Join(v.errs...)
+}
@@ -0,0 +1,52 @@
+package crawler
+
+import (
+	"bytes"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html/charset"
+	"golang.org/x/text/encoding"
+)
+
+// utf8BOM é a marca de ordem de bytes do UTF-8, removida antes da
+// interpretação do HTML.
+var utf8BOM = []byte("\xEF\xBB\xBF")
+
+// DetectCharset identifica a codificação de body, na ordem do padrão HTML: o
+// BOM, o charset do cabeçalho Content-Type e a tag meta dos primeiros 1024
+// bytes. Sem BOM nem cabeçalho, um corpo que é UTF-8 válido é tratado como
+// UTF-8 mesmo que a tag meta diga outra coisa, já que textos em outras
+// codificações com acentos raramente formam UTF-8 válido. Sem nenhuma pista, o
+// padrão é windows-1252. O nome retornado é o nome canônico da codificação.
+func DetectCharset(body []byte, contentType string) (encoding.Encoding, string) {
+	enc, name, certain := charset.DetermineEncoding(body, contentType)
+	if !certain && name != "utf-8" && utf8.Valid(body) {
+		return encoding.Nop, "utf-8"
+	}
+	return enc, name
+}
+
+// ToUTF8 converte body, na codificação name, para UTF-8, sem o BOM. Bytes
+// inválidos viram U+FFFD.
+func ToUTF8(body []byte, name string) []byte {
+	if name == "" || name == "utf-8" {
+		return []byte(strings.ToValidUTF8(string(bytes.TrimPrefix(body, utf8BOM)), "\uFFFD"))
+	}
+	enc, _ := charset.Lookup(name)
+	if enc == nil {
+		return []byte(strings.ToValidUTF8(string(body), "\uFFFD"))
+	}
+	decoded, err := enc.NewDecoder().Bytes(body)
+	if err != nil {
+		return []byte(strings.ToValidUTF8(string(body), "\uFFFD"))
+	}
+	return decoded
+}
+
+// isTextMediaType informa se mediaType descreve um documento de texto, cuja
+// codificação deve ser detectada.
+func isTextMediaType(mediaType string) bool {
+	return strings.HasPrefix(mediaType, "text/") || strings.HasSuffix(mediaType, "+xml") ||
+		mediaType == "application/xml"
+}
@@ -0,0 +1,48 @@
+package crawler
+
+import (
+	"testing"
+)
+
+func TestDetectCharset(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		contentType string
+		expected    string
+	}{
+		{"content-type header", "<p>Gradua\xe7\xe3o</p>", "text/html; charset=ISO-8859-1", "windows-1252"},
+		{"header wins over meta", `<meta charset="utf-8"><p>Gradua` + "\xe7\xe3o</p>", "text/html; charset=windows-1252", "windows-1252"},
+		{"meta charset", `<meta charset="iso-8859-15"><p>` + "\xa4</p>", "text/html", "iso-8859-15"},
+		{"meta http-equiv", `<meta http-equiv="Content-Type" content="text/html; charset=windows-1252"><p>` + "\x93</p>", "", "windows-1252"},
+		{"bom wins over header", "\xef\xbb\xbf<p>Graduação</p>", "text/html; charset=iso-8859-1", "utf-8"},
+		{"utf-16 bom", "\xff\xfe<\x00p\x00>\x00", "text/html", "utf-16le"},
+		{"valid utf-8 despite meta", `<meta charset="iso-8859-1"><p>Graduação</p>`, "text/html", "utf-8"},
+		{"undeclared latin-1", "<p>Gradua\xe7\xe3o</p>", "text/html", "windows-1252"},
+		{"undeclared utf-8", "<p>Graduação</p>", "text/html", "utf-8"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, got := DetectCharset([]byte(tc.body), tc.contentType); got != tc.expected {
+				t.Errorf("DetectCharset() = %q, expected %q", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestToUTF8(t *testing.T) {
+	tests := []struct {
+		body, charset, expected string
+	}{
+		{"Gradua\xe7\xe3o", "windows-1252", "Graduação"},
+		{"\x93aspas\x94", "windows-1252", "“aspas”"},
+		{"\xef\xbb\xbfGraduação", "utf-8", "Graduação"},
+		{"Gradua\xe7\xe3o", "utf-8", "Gradua\uFFFDo"},
+		{"Gradua\xe7\xe3o", "", "Gradua\uFFFDo"},
+	}
+	for _, tc := range tests {
+		if got := string(ToUTF8([]byte(tc.body), tc.charset)); got != tc.expected {
+			t.Errorf("ToUTF8(%q, %q) = %q, expected %q", tc.body, tc.charset, got, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package crawler
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+)
+
+// NewResponseDTO converte o resultado interno do crawler para o DTO da API.
+func NewResponseDTO(result *CrawlResult, originalURL *url.URL) ResponseDTO {
+	return ResponseDTO{
+		StatusCode:  result.StatusCode,
+		ContentType: result.ContentType,
+		Charset:     result.Charset,
+		ElapsedTime: result.ElapsedTime.Nanoseconds(),
+		Links:       result.Links,
+		Title:       result.Title,
+		ErrorClass:  result.ErrorClass,
+		ContentHash: result.ContentHash,
+		Details: DetailsResponseDTO{
+			CorrectURL: result.FinalURL.String(),
+			Original:   NewURLDetails(originalURL),
+			Modified:   NewURLDetails(result.FinalURL),
+		},
+	}
+}
+
+// AttachBody inclui o corpo do resultado na resposta conforme payload.IncludeBody,
+// limitado a payload.MaxBodySize bytes. No modo texto, o corpo é convertido
+// de result.Charset para UTF-8; no base64, segue como recebido.
+func (r *ResponseDTO) AttachBody(result *CrawlResult, payload Payload) {
+	if payload.IncludeBody == nil || *payload.IncludeBody == BodyNone {
+		return
+	}
+
+	body := result.Body
+	truncated := result.BodyTruncated
+	if payload.MaxBodySize != nil && len(body) > *payload.MaxBodySize {
+		body = body[:*payload.MaxBodySize]
+		truncated = true
+	}
+
+	switch *payload.IncludeBody {
+	case BodyText:
+		r.Body = string(ToUTF8(body, result.Charset))
+	case BodyBase64:
+		r.Body = base64.StdEncoding.EncodeToString(body)
+	default:
+		return
+	}
+	r.BodyEncoding = *payload.IncludeBody
+	r.BodyTruncated = truncated
+}
+
+// DecodedBody retorna os bytes do corpo incluído na resposta.
+func (r *ResponseDTO) DecodedBody() ([]byte, error) {
+	switch r.BodyEncoding {
+	case "":
+		return nil, nil
+	case BodyText:
+		return []byte(r.Body), nil
+	case BodyBase64:
+		return base64.StdEncoding.DecodeString(r.Body)
+	default:
+		return nil, fmt.Errorf("unknown body encoding %q", r.BodyEncoding)
+	}
+}
+
+// NewURLDetails converte uma url.URL para a struct de detalhes do DTO.
+func NewURLDetails(u *url.URL) URLDetails {
+	if u == nil {
+		return URLDetails{}
+	}
+
+	details := URLDetails{
+		Scheme:      u.Scheme,
+		Opaque:      u.Opaque,
+		Host:        u.Host,
+		Path:        u.Path,
+		RawPath:     u.RawPath,
+		OmitHost:    u.OmitHost,
+		ForceQuery:  u.ForceQuery,
+		RawQuery:    u.RawQuery,
+		Fragment:    u.Fragment,
+		RawFragment: u.RawFragment,
+	}
+
+	if u.User != nil {
+		password, isSet := u.User.Password()
+		details.User = &UserURLDetails{
+			Username:    u.User.Username(),
+			Password:    password,
+			PasswordSet: isSet,
+		}
+	}
+
+	return details
+}
@@ -0,0 +1,54 @@
+package crawler
+
+import (
+	"testing"
+)
+
+func TestAttachBody(t *testing.T) {
+	body := []byte("<html>olá</html>")
+
+	testCases := []struct {
+		name              string
+		mode              string
+		maxSize           int
+		expectedBody      string
+		expectedTruncated bool
+	}{
+		{name: "none", mode: BodyNone, maxSize: 100, expectedBody: ""},
+		{name: "text", mode: BodyText, maxSize: 100, expectedBody: "<html>olá</html>"},
+		{name: "base64", mode: BodyBase64, maxSize: 100, expectedBody: "PGh0bWw+b2zDoTwvaHRtbD4="},
+		{name: "text truncated", mode: BodyText, maxSize: 6, expectedBody: "<html>", expectedTruncated: true},
+		{name: "text truncated inside a rune", mode: BodyText, maxSize: 9, expectedBody: "<html>ol�", expectedTruncated: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var dto ResponseDTO
+			payload := Payload{IncludeBody: &tc.mode, MaxBodySize: &tc.maxSize}
+
+			dto.AttachBody(&CrawlResult{Body: body}, payload)
+
+			if dto.Body != tc.expectedBody || dto.BodyTruncated != tc.expectedTruncated {
+				t.Errorf("expected body %q (truncated %v), got %q (truncated %v)", tc.expectedBody, tc.expectedTruncated, dto.Body, dto.BodyTruncated)
+			}
+			if tc.mode != BodyNone {
+				decoded, err := dto.DecodedBody()
+				if err != nil || (tc.mode == BodyBase64 && string(decoded) != string(body)) {
+					t.Errorf("DecodedBody() = %q, %v", decoded, err)
+				}
+			}
+		})
+	}
+}
+
+func TestAttachBodyConvertsCharset(t *testing.T) {
+	mode, maxSize := BodyText, 100
+	result := &CrawlResult{Body: []byte("<html>ol\xe1</html>"), Charset: "windows-1252"}
+
+	var dto ResponseDTO
+	dto.AttachBody(result, Payload{IncludeBody: &mode, MaxBodySize: &maxSize})
+
+	if dto.Body != "<html>olá</html>" {
+		t.Errorf("expected the text body in utf-8, got %q", dto.Body)
+	}
+}
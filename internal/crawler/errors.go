@@ -0,0 +1,80 @@
+package crawler
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"syscall"
+)
+
+// ErrorClass classifica a causa de uma falha ao buscar uma página.
+type ErrorClass string
+
+const (
+	ErrorClassTimeout           ErrorClass = "timeout"
+	ErrorClassCanceled          ErrorClass = "canceled"
+	ErrorClassDNS               ErrorClass = "dns"
+	ErrorClassConnectionRefused ErrorClass = "connection_refused"
+	ErrorClassConnectionReset   ErrorClass = "connection_reset"
+	ErrorClassTLS               ErrorClass = "tls"
+	ErrorClassTooManyRedirects  ErrorClass = "too_many_redirects"
+	ErrorClassHTTPStatus        ErrorClass = "http_status"
+	ErrorClassInvalidResponse   ErrorClass = "invalid_response"
+	ErrorClassUnknown           ErrorClass = "unknown"
+
+	// ErrorClassBlocked indica um destino recusado pelo AddressGuard.
+	ErrorClassBlocked ErrorClass = "blocked"
+	// ErrorClassProxy indica uma falha de conexão com o proxy de saída.
+	ErrorClassProxy ErrorClass = "proxy"
+	// ErrorClassSession indica uma sessão desconhecida ou cujo login falhou.
+	ErrorClassSession ErrorClass = "session"
+)
+
+// ErrTooManyRedirects é retornado quando o limite de redirecionamentos é atingido.
+var ErrTooManyRedirects = errors.New("too many redirects")
+
+// ClassifyError identifica a classe de uma falha de rede ou de protocolo.
+func ClassifyError(err error) ErrorClass {
+	if err == nil {
+		return ""
+	}
+
+	var (
+		dnsErr       *net.DNSError
+		netErr       net.Error
+		recordErr    tls.RecordHeaderError
+		verifyErr    *tls.CertificateVerificationError
+		authorityErr x509.UnknownAuthorityError
+		hostnameErr  x509.HostnameError
+		invalidErr   x509.CertificateInvalidError
+	)
+
+	switch {
+	case errors.Is(err, ErrBlockedAddress):
+		return ErrorClassBlocked
+	case IsProxyError(err):
+		return ErrorClassProxy
+	case errors.Is(err, ErrUnknownSession), errors.Is(err, ErrLoginFailed):
+		return ErrorClassSession
+	case errors.Is(err, ErrTooManyRedirects):
+		return ErrorClassTooManyRedirects
+	case errors.Is(err, context.Canceled):
+		return ErrorClassCanceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorClassTimeout
+	case errors.As(err, &dnsErr):
+		return ErrorClassDNS
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return ErrorClassConnectionRefused
+	case errors.Is(err, syscall.ECONNRESET):
+		return ErrorClassConnectionReset
+	case errors.As(err, &recordErr), errors.As(err, &verifyErr), errors.As(err, &authorityErr),
+		errors.As(err, &hostnameErr), errors.As(err, &invalidErr):
+		return ErrorClassTLS
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return ErrorClassTimeout
+	}
+	return ErrorClassUnknown
+}
@@ -0,0 +1,39 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"testing"
+)
+
+func TestClassifyError(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected ErrorClass
+	}{
+		{name: "nil error", err: nil, expected: ""},
+		{name: "deadline exceeded", err: &url.Error{Op: "Get", URL: "http://example.com", Err: context.DeadlineExceeded}, expected: ErrorClassTimeout},
+		{name: "canceled", err: fmt.Errorf("request: %w", context.Canceled), expected: ErrorClassCanceled},
+		{name: "dns failure", err: &url.Error{Op: "Get", Err: &net.DNSError{Err: "no such host", Name: "invalid.test"}}, expected: ErrorClassDNS},
+		{name: "connection refused", err: &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}, expected: ErrorClassConnectionRefused},
+		{name: "too many redirects", err: &url.Error{Op: "Get", Err: ErrTooManyRedirects}, expected: ErrorClassTooManyRedirects},
+		{name: "blocked address", err: &url.Error{Op: "Get", Err: &net.OpError{Op: "dial", Err: &BlockedAddressError{Host: "metadata", IP: netip.MustParseAddr("169.254.169.254")}}}, expected: ErrorClassBlocked},
+		{name: "proxy connection", err: &url.Error{Op: "Get", Err: &net.OpError{Op: "proxyconnect", Net: "tcp", Err: syscall.ECONNREFUSED}}, expected: ErrorClassProxy},
+		{name: "no proxy available", err: &url.Error{Op: "Get", Err: ErrNoProxyAvailable}, expected: ErrorClassProxy},
+		{name: "unknown", err: errors.New("boom"), expected: ErrorClassUnknown},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := ClassifyError(tc.err); got != tc.expected {
+				t.Errorf("expected class %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
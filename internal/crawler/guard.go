@@ -0,0 +1,220 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// ErrBlockedAddress é retornado quando o destino de uma busca resolve apenas
+// para endereços bloqueados pelo AddressGuard.
+var ErrBlockedAddress = errors.New("destination address is not allowed")
+
+// BlockedAddressError identifica o host e o endereço recusados.
+type BlockedAddressError struct {
+	Host string
+	IP   netip.Addr
+}
+
+func (e *BlockedAddressError) Error() string {
+	if e.Host == e.IP.String() {
+		return fmt.Sprintf("%s: %s", ErrBlockedAddress, e.IP)
+	}
+	return fmt.Sprintf("%s: %s resolves to %s", ErrBlockedAddress, e.Host, e.IP)
+}
+
+func (e *BlockedAddressError) Is(target error) bool {
+	return target == ErrBlockedAddress
+}
+
+// blockedPrefixes completa as verificações de netip.Addr com as faixas que
+// também não devem ser alcançadas a partir de URLs enviadas por clientes.
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),      // "esta" rede
+	netip.MustParsePrefix("100.64.0.0/10"),  // CGNAT
+	netip.MustParsePrefix("192.0.0.0/24"),   // atribuições de protocolo do IETF
+	netip.MustParsePrefix("198.18.0.0/15"),  // testes de desempenho
+	netip.MustParsePrefix("240.0.0.0/4"),    // reservada, inclui o broadcast
+	netip.MustParsePrefix("64:ff9b:1::/48"), // tradução local
+	netip.MustParsePrefix("100::/64"),       // descarte
+	netip.MustParsePrefix("2001::/23"),      // atribuições de protocolo do IETF
+	netip.MustParsePrefix("fec0::/10"),      // site-local, obsoleta
+}
+
+// AddressGuard impede que o cliente HTTP se conecte a endereços de loopback,
+// privados, link-local (como os serviços de metadados das nuvens) e outras
+// faixas reservadas. O host é resolvido antes da conexão e apenas os endereços
+// verificados são discados, o que vale também para cada redirecionamento e
+// impede que uma nova resolução DNS troque o destino.
+type AddressGuard struct {
+	allowPrefixes []netip.Prefix
+	allowHosts    map[string]bool
+	resolver      *net.Resolver
+}
+
+// NewAddressGuard cria um AddressGuard. allow lista os destinos internos
+// permitidos: endereços IP, faixas CIDR ou nomes de host.
+func NewAddressGuard(allow []string) (*AddressGuard, error) {
+	g := &AddressGuard{allowHosts: make(map[string]bool), resolver: net.DefaultResolver}
+	for _, entry := range allow {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if prefix, err := netip.ParsePrefix(entry); err == nil {
+			g.allowPrefixes = append(g.allowPrefixes, prefix.Masked())
+			continue
+		}
+		if ip, err := netip.ParseAddr(entry); err == nil {
+			g.allowPrefixes = append(g.allowPrefixes, netip.PrefixFrom(ip.Unmap(), ip.Unmap().BitLen()))
+			continue
+		}
+		if strings.ContainsAny(entry, "/: ") {
+			return nil, fmt.Errorf("invalid allowlist entry %q", entry)
+		}
+		g.allowHosts[strings.ToLower(strings.TrimSuffix(entry, "."))] = true
+	}
+	return g, nil
+}
+
+// Allowed informa se ip pode ser contactado.
+func (g *AddressGuard) Allowed(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	for _, prefix := range g.allowPrefixes {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+	if ip.Is6() {
+		// Endereços NAT64 e 6to4 carregam um IPv4, verificado como tal.
+		if embedded, ok := embeddedIPv4(ip); ok {
+			return g.Allowed(embedded)
+		}
+	}
+	if !ip.IsValid() || ip.IsUnspecified() || ip.IsLoopback() || ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+var (
+	nat64Prefix = netip.MustParsePrefix("64:ff9b::/96")
+	sixToFour   = netip.MustParsePrefix("2002::/16")
+)
+
+func embeddedIPv4(ip netip.Addr) (netip.Addr, bool) {
+	b := ip.As16()
+	switch {
+	case nat64Prefix.Contains(ip):
+		return netip.AddrFrom4([4]byte(b[12:16])), true
+	case sixToFour.Contains(ip):
+		return netip.AddrFrom4([4]byte(b[2:6])), true
+	}
+	return netip.Addr{}, false
+}
+
+// CheckHost resolve host e retorna um *BlockedAddressError se algum dos
+// endereços for bloqueado. É usado quando a conexão vai para um proxy, que
+// resolve o destino por conta própria.
+func (g *AddressGuard) CheckHost(ctx context.Context, host string) error {
+	if g.allowHosts[strings.ToLower(strings.TrimSuffix(host, "."))] {
+		return nil
+	}
+	ips, err := g.resolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return err
+	}
+	for _, ip := range ips {
+		if !g.Allowed(ip) {
+			return &BlockedAddressError{Host: host, IP: ip.Unmap()}
+		}
+	}
+	return nil
+}
+
+// DialContext envolve dial: resolve o host, descarta os endereços bloqueados e
+// disca os demais em ordem. Se todos forem bloqueados, retorna um
+// *BlockedAddressError.
+func (g *AddressGuard) DialContext(dial func(ctx context.Context, network, address string) (net.Conn, error)) func(ctx context.Context, network, address string) (net.Conn, error) {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+		if g.allowHosts[strings.ToLower(strings.TrimSuffix(host, "."))] {
+			return dial(ctx, network, address)
+		}
+
+		ips, err := g.resolver.LookupNetIP(ctx, "ip", host)
+		if err != nil {
+			return nil, err
+		}
+		var blocked, dialErr error
+		for _, ip := range ips {
+			if !g.Allowed(ip) {
+				if blocked == nil {
+					blocked = &BlockedAddressError{Host: host, IP: ip.Unmap()}
+				}
+				continue
+			}
+			conn, err := dial(ctx, network, net.JoinHostPort(ip.Unmap().String(), port))
+			if err == nil {
+				return conn, nil
+			}
+			dialErr = err
+		}
+		if dialErr != nil {
+			return nil, dialErr
+		}
+		if blocked != nil {
+			return nil, blocked
+		}
+		return nil, &net.DNSError{Err: "no addresses found", Name: host, IsNotFound: true}
+	}
+}
+
+// NewGuardedTransport cria um http.Transport que recusa destinos internos,
+// para os clientes HTTP de fora do crawler, como o dos webhooks. Os proxies de
+// HTTP_PROXY e HTTPS_PROXY podem estar em redes internas; o destino é
+// verificado antes de a requisição seguir para eles.
+func NewGuardedTransport(guard *AddressGuard) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	envProxy := httpproxy.FromEnvironment().ProxyFunc()
+	var trusted sync.Map
+	transport.Proxy = func(req *http.Request) (*url.URL, error) {
+		proxy, err := envProxy(req.URL)
+		if err != nil || proxy == nil {
+			return proxy, err
+		}
+		if err := guard.CheckHost(req.Context(), req.URL.Hostname()); err != nil {
+			return nil, err
+		}
+		trusted.Store(proxyAddr(proxy), true)
+		return proxy, nil
+	}
+
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	guarded := guard.DialContext(dialer.DialContext)
+	transport.DialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
+		if _, ok := trusted.Load(address); ok {
+			return dialer.DialContext(ctx, network, address)
+		}
+		return guarded(ctx, network, address)
+	}
+	return transport
+}
@@ -0,0 +1,133 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAddressGuardAllowed(t *testing.T) {
+	guard, err := NewAddressGuard(nil)
+	if err != nil {
+		t.Fatalf("NewAddressGuard returned an error: %v", err)
+	}
+
+	testCases := map[string]bool{
+		"8.8.8.8":              true,
+		"200.17.137.40":        true,
+		"2606:4700:4700::1111": true,
+		"127.0.0.1":            false,
+		"10.0.0.1":             false,
+		"172.16.5.4":           false,
+		"192.168.1.1":          false,
+		"169.254.169.254":      false,
+		"100.64.0.1":           false,
+		"0.0.0.0":              false,
+		"255.255.255.255":      false,
+		"224.0.0.1":            false,
+		"::1":                  false,
+		"::":                   false,
+		"fd00::1":              false,
+		"fe80::1":              false,
+		"::ffff:127.0.0.1":     false,
+		"64:ff9b::a9fe:a9fe":   false,
+		"64:ff9b::808:808":     true,
+		"2002:7f00:1::1":       false,
+		"fec0::1":              false,
+	}
+	for addr, expected := range testCases {
+		t.Run(addr, func(t *testing.T) {
+			if got := guard.Allowed(netip.MustParseAddr(addr)); got != expected {
+				t.Errorf("expected Allowed(%s) to be %v, got %v", addr, expected, got)
+			}
+		})
+	}
+}
+
+func TestAddressGuardAllowlist(t *testing.T) {
+	guard, err := NewAddressGuard([]string{"10.1.0.0/16", " 192.168.0.10 ", "intranet.ufape.edu.br", ""})
+	if err != nil {
+		t.Fatalf("NewAddressGuard returned an error: %v", err)
+	}
+	if !guard.Allowed(netip.MustParseAddr("10.1.2.3")) || guard.Allowed(netip.MustParseAddr("10.2.0.1")) {
+		t.Error("expected only the allowed CIDR to be reachable")
+	}
+	if !guard.Allowed(netip.MustParseAddr("192.168.0.10")) || guard.Allowed(netip.MustParseAddr("192.168.0.11")) {
+		t.Error("expected only the allowed IP to be reachable")
+	}
+	if !guard.allowHosts["intranet.ufape.edu.br"] {
+		t.Error("expected the hostname to be allowed")
+	}
+
+	if _, err := NewAddressGuard([]string{"10.0.0.0/33"}); err == nil {
+		t.Error("expected an error for an invalid entry")
+	}
+}
+
+func TestHTTPClientAddressGuard(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("internal"))
+	}))
+	defer target.Close()
+
+	t.Run("should block loopback targets", func(t *testing.T) {
+		guard, _ := NewAddressGuard(nil)
+		client := NewHTTPClient(5*time.Second, WithAddressGuard(guard))
+
+		_, err := client.Get(context.Background(), target.URL, RequestOptions{})
+		if !errors.Is(err, ErrBlockedAddress) || ClassifyError(err) != ErrorClassBlocked {
+			t.Fatalf("expected a blocked error, got %v", err)
+		}
+	})
+
+	t.Run("should allow targets in the allowlist", func(t *testing.T) {
+		guard, _ := NewAddressGuard([]string{"127.0.0.1"})
+		client := NewHTTPClient(5*time.Second, WithAddressGuard(guard))
+
+		resp, err := client.Get(context.Background(), target.URL, RequestOptions{})
+		if err != nil {
+			t.Fatalf("expected the allowed target to be fetched, got %v", err)
+		}
+		resp.Body.Close()
+	})
+
+	t.Run("should check every redirect hop", func(t *testing.T) {
+		redirector := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusFound))
+		defer redirector.Close()
+
+		guard, _ := NewAddressGuard([]string{"localhost"})
+		client := NewHTTPClient(5*time.Second, WithAddressGuard(guard))
+		first := strings.Replace(redirector.URL, "127.0.0.1", "localhost", 1)
+
+		_, err := client.Get(context.Background(), first, RequestOptions{})
+		var blocked *BlockedAddressError
+		if !errors.As(err, &blocked) || blocked.IP.String() != "127.0.0.1" {
+			t.Fatalf("expected the redirect to the loopback address to be blocked, got %v", err)
+		}
+	})
+}
+
+func TestServiceDoesNotRetryBlockedTargets(t *testing.T) {
+	guard, _ := NewAddressGuard(nil)
+	service := NewService(NewHTTPClient(5*time.Second, WithAddressGuard(guard)))
+
+	target, _ := url.Parse("http://169.254.169.254/latest/meta-data/")
+	attempts := 3
+	start := time.Now()
+	result, err := CrawlWithRetry(context.Background(), service, Payload{MaxAttempts: &attempts}, target, target, time.Second)
+	if err != nil {
+		t.Fatalf("CrawlWithRetry returned an error: %v", err)
+	}
+	if result.ErrorClass != ErrorClassBlocked {
+		t.Errorf("expected the blocked error class, got %q (%s)", result.ErrorClass, result.Title)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("expected no retries for a blocked target, took %v", elapsed)
+	}
+}
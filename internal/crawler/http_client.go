@@ -3,38 +3,393 @@ package crawler
 import (
 	"context"
 	"fmt"
+	"io"
+	"log/slog"
+	"net"
 	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"sort"
+	"strings"
+	"sync/atomic"
 	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/httptrace/otelhttptrace"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/net/http/httpproxy"
+
+	"github.com/nettojulio/ufape-crawler-golang/internal/logging"
+	"github.com/nettojulio/ufape-crawler-golang/internal/metrics"
+)
+
+const (
+	// DefaultUserAgent é o User-Agent enviado quando WithUserAgent não é usado.
+	DefaultUserAgent = "Mozilla/5.0 (X11; Linux x86_64; rv:145.0) Gecko/20100101 Firefox/145.0"
+	// DefaultMaxRedirects é o tamanho máximo da cadeia de redirecionamentos.
+	DefaultMaxRedirects = 10
 )
 
 type HTTPClient struct {
-	client    *http.Client
-	userAgent string
+	client       *http.Client
+	timeout      time.Duration
+	base         *http.Transport
+	userAgent    string
+	maxRedirects int
+	guard        *AddressGuard
+	proxies      *ProxyPool
+	requestProxy bool
+	envProxy     func(*url.URL) (*url.URL, error)
+	sessions     *SessionStore
+	logger       *slog.Logger
+}
+
+// HTTPClientOption configura opcionalmente o HTTPClient.
+type HTTPClientOption func(*HTTPClient)
+
+// WithRecorder entrega ao recorder cada troca feita pelo cliente, inclusive
+// as respostas de redirecionamento.
+func WithRecorder(recorder ExchangeRecorder) HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.client.Transport = &recordingTransport{next: c.transport(), recorder: recorder}
+	}
+}
+
+// WithAddressGuard faz cada conexão, inclusive as de redirecionamentos,
+// passar por guard, que recusa destinos internos. Quando a busca usa um
+// proxy, o host de destino é verificado antes de a requisição seguir para o
+// proxy; as conexões com os proxies do ProxyPool e com o proxy das variáveis
+// de ambiente não são verificadas.
+func WithAddressGuard(guard *AddressGuard) HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.guard = guard
+	}
+}
+
+// WithProxyPool faz as buscas passarem pelos proxies de pool. Sem ele, valem
+// as variáveis HTTP_PROXY, HTTPS_PROXY e NO_PROXY.
+func WithProxyPool(pool *ProxyPool) HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.proxies = pool
+	}
+}
+
+// WithRequestProxies permite que cada busca escolha seu proxy em
+// RequestOptions.Proxy.
+func WithRequestProxies() HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.requestProxy = true
+	}
+}
+
+// WithCookieJar guarda em jar os cookies recebidos e os reenvia nas buscas
+// seguintes, como um navegador. As buscas com RequestOptions.Session usam o
+// jar da sessão no lugar deste.
+func WithCookieJar(jar http.CookieJar) HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.client.Jar = jar
+	}
+}
+
+// WithSessions permite que as buscas usem as sessões de store em
+// RequestOptions.Session.
+func WithSessions(store *SessionStore) HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.sessions = store
+	}
+}
+
+// WithUserAgent define o User-Agent enviado em cada busca.
+func WithUserAgent(userAgent string) HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithMaxRedirects define quantos redirecionamentos são seguidos antes de a
+// busca falhar com ErrTooManyRedirects. Zero não segue nenhum.
+func WithMaxRedirects(n int) HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.maxRedirects = n
+	}
+}
+
+// WithLogger define o logger usado quando o contexto da busca não carrega um
+// logger próprio (veja logging.WithContext).
+func WithLogger(logger *slog.Logger) HTTPClientOption {
+	return func(c *HTTPClient) {
+		c.logger = logger
+	}
 }
 
-func NewHTTPClient(timeout time.Duration) *HTTPClient {
-	return &HTTPClient{
+// NewHTTPClient cria o cliente do crawler. timeout é o limite de cada busca;
+// um prazo menor no contexto, como o timeout do payload, prevalece sobre ele.
+func NewHTTPClient(timeout time.Duration, opts ...HTTPClientOption) *HTTPClient {
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	c := &HTTPClient{
 		client: &http.Client{
-			Timeout: timeout,
-			CheckRedirect: func(req *http.Request, via []*http.Request) error {
-				if len(via) >= 10 {
-					return fmt.Errorf("stopped after 10 redirects")
-				}
-				return nil
-			},
+			Transport: base,
 		},
-		userAgent: "Mozilla/5.0 (X11; Linux x86_64; rv:145.0) Gecko/20100101 Firefox/145.0",
+		timeout:      timeout,
+		base:         base,
+		userAgent:    DefaultUserAgent,
+		maxRedirects: DefaultMaxRedirects,
+		envProxy:     httpproxy.FromEnvironment().ProxyFunc(),
+		logger:       logging.Discard(),
+	}
+	c.client.CheckRedirect = c.checkRedirect
+	base.Proxy = c.proxyFor
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.guard != nil {
+		dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+		guarded := c.guard.DialContext(dialer.DialContext)
+		base.DialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
+			if c.proxies.trusts(address) || proxyUseFrom(ctx).trustsEnv(address) {
+				return dialer.DialContext(ctx, network, address)
+			}
+			return guarded(ctx, network, address)
+		}
+	}
+	return c
+}
+
+// proxyUse leva, pelo contexto da busca, o proxy pedido em RequestOptions e
+// registra o proxy do pool usado, para que Get informe o resultado ao pool,
+// e o das variáveis de ambiente, em que a conexão pode confiar.
+type proxyUse struct {
+	requested *url.URL
+	pooled    atomic.Pointer[url.URL]
+	env       atomic.Pointer[url.URL]
+}
+
+type proxyUseKey struct{}
+
+func proxyUseFrom(ctx context.Context) *proxyUse {
+	use, _ := ctx.Value(proxyUseKey{}).(*proxyUse)
+	return use
+}
+
+// trustsEnv informa se address é o do proxy das variáveis de ambiente
+// escolhido para a busca.
+func (u *proxyUse) trustsEnv(address string) bool {
+	if u == nil {
+		return false
+	}
+	proxy := u.env.Load()
+	return proxy != nil && proxyAddr(proxy) == address
+}
+
+// newProxyUse valida o proxy pedido em opts.
+func (c *HTTPClient) newProxyUse(opts RequestOptions) (*proxyUse, error) {
+	use := &proxyUse{}
+	if opts.Proxy == "" {
+		return use, nil
+	}
+	if !c.requestProxy {
+		return nil, ErrProxyNotAllowed
+	}
+	proxy, err := ParseProxyURL(opts.Proxy)
+	if err != nil {
+		return nil, err
+	}
+	use.requested = proxy
+	return use, nil
+}
+
+// proxyFor é o Transport.Proxy do cliente. É chamado a cada ida e volta,
+// inclusive nos redirecionamentos.
+func (c *HTTPClient) proxyFor(req *http.Request) (*url.URL, error) {
+	ctx := req.Context()
+	use := proxyUseFrom(ctx)
+
+	var proxy *url.URL
+	var err error
+	switch {
+	case use != nil && use.requested != nil:
+		proxy = use.requested
+	case c.proxies != nil:
+		proxy, err = c.proxies.Select(req.URL.Hostname())
+		if err == nil && use != nil {
+			use.pooled.Store(proxy)
+		}
+	default:
+		proxy, err = c.envProxy(req.URL)
+		if err == nil && proxy != nil && use != nil {
+			use.env.Store(proxy)
+		}
+	}
+	if err != nil || proxy == nil {
+		return proxy, err
+	}
+	if c.guard != nil {
+		if err := c.guard.CheckHost(ctx, req.URL.Hostname()); err != nil {
+			return nil, err
+		}
+	}
+	return proxy, nil
+}
+
+// checkRedirect limita a cadeia de redirecionamentos e registra cada um nas
+// métricas, no log e no span da busca.
+func (c *HTTPClient) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= c.maxRedirects {
+		return fmt.Errorf("stopped after %d redirects: %w", c.maxRedirects, ErrTooManyRedirects)
+	}
+	metrics.FetchRedirects.Inc()
+	ctx := req.Context()
+	logging.FromContext(ctx, c.logger).DebugContext(ctx, "redirect followed",
+		"from", via[len(via)-1].URL.String(), "to", req.URL.String(), "status", req.Response.StatusCode)
+	trace.SpanFromContext(ctx).AddEvent("redirect", trace.WithAttributes(
+		semconv.HTTPResponseStatusCode(req.Response.StatusCode),
+		semconv.URLFull(req.URL.String()),
+	))
+	return nil
+}
+
+// apply define em req os cabeçalhos, os cookies e as credenciais de o.
+func (o RequestOptions) apply(req *http.Request) {
+	for name, value := range o.Headers {
+		req.Header.Set(name, value)
+	}
+	if o.UserAgent != "" {
+		req.Header.Set("User-Agent", o.UserAgent)
+	}
+	if o.AcceptLanguage != "" {
+		req.Header.Set("Accept-Language", o.AcceptLanguage)
+	}
+	names := make([]string, 0, len(o.Cookies))
+	for name := range o.Cookies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		req.AddCookie(&http.Cookie{Name: name, Value: o.Cookies[name]})
+	}
+	if o.BasicAuth != nil {
+		req.SetBasicAuth(o.BasicAuth.Username, o.BasicAuth.Password)
+	}
+}
+
+// transport retorna o RoundTripper atual do cliente, para ser envolvido por opções.
+func (c *HTTPClient) transport() http.RoundTripper {
+	return c.client.Transport
+}
+
+// Get busca url seguindo os redirecionamentos, com os cabeçalhos, os cookies
+// e as credenciais de opts. Com opts.Session, a busca usa os cookies da
+// sessão, fazendo antes o login dela se ainda não foi feito; se a busca é
+// levada à página de login, a sessão expirou e o login é refeito antes de uma
+// nova tentativa. O span
+// crawler.fetch termina ao receber os cabeçalhos da resposta final e registra
+// cada redirecionamento como um evento; as etapas de conexão (DNS, TCP, TLS,
+// envio e espera) de cada ida e volta viram spans filhos. O contexto do trace
+// não é enviado aos sites buscados.
+func (c *HTTPClient) Get(ctx context.Context, url string, opts RequestOptions) (*http.Response, error) {
+	if opts.Session == "" {
+		return c.do(ctx, http.MethodGet, url, nil, opts, nil)
+	}
+	session, err := c.sessions.Get(opts.Session)
+	if err != nil {
+		return nil, err
+	}
+	generation, err := session.ensureLogin(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.do(ctx, http.MethodGet, url, nil, opts, session.jar)
+	if err != nil || !session.expired(url, resp.Request.URL) {
+		return resp, err
 	}
+	resp.Body.Close()
+	logging.FromContext(ctx, c.logger).InfoContext(ctx, "session expired", "session", session.name, "url", url)
+	session.expire(generation)
+	if _, err := session.ensureLogin(ctx, c); err != nil {
+		return nil, err
+	}
+	return c.do(ctx, http.MethodGet, url, nil, opts, session.jar)
 }
 
-func (c *HTTPClient) Get(ctx context.Context, url string) (*http.Response, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+// do envia a requisição de Get e do login das sessões. form, quando não é
+// nil, vai no corpo como application/x-www-form-urlencoded; jar, quando não é
+// nil, substitui o cookie jar do cliente.
+func (c *HTTPClient) do(ctx context.Context, method, target string, form url.Values, opts RequestOptions, jar http.CookieJar) (*http.Response, error) {
+	cancel := context.CancelFunc(func() {})
+	if c.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+	}
+
+	ctx, span := tracer().Start(ctx, "crawler.fetch", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+	ctx = httptrace.WithClientTrace(ctx, otelhttptrace.NewClientTrace(ctx, otelhttptrace.WithoutHeaders()))
+
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+	use, err := c.newProxyUse(opts)
+	var req *http.Request
+	if err == nil {
+		req, err = http.NewRequestWithContext(context.WithValue(ctx, proxyUseKey{}, use), method, target, body)
+	}
 	if err != nil {
+		cancel()
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return nil, err
 	}
 	req.Header.Set("Accept", "*/*")
 	req.Header.Set("Connection", "keep-alive")
 	req.Header.Set("User-Agent", c.userAgent)
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	opts.apply(req)
+
+	client := c.client
+	if jar != nil {
+		withJar := *c.client
+		withJar.Jar = jar
+		client = &withJar
+	}
+
+	logger := logging.FromContext(ctx, c.logger)
+	start := time.Now()
+	resp, err := client.Do(req)
+	elapsed := time.Since(start)
+	statusClass := "error"
+	if err == nil {
+		statusClass = metrics.StatusClass(resp.StatusCode)
+		span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode), semconv.URLFull(resp.Request.URL.String()))
+		logger.DebugContext(ctx, "fetch completed", "method", method, "url", target, "final_url", resp.Request.URL.String(),
+			"status", resp.StatusCode, "elapsed", elapsed)
+	} else {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		logger.DebugContext(ctx, "fetch failed", "method", method, "url", target, "elapsed", elapsed, "error", err)
+	}
+	metrics.FetchDuration.WithLabelValues(req.URL.Hostname(), statusClass).Observe(elapsed.Seconds())
+	if proxy := use.pooled.Load(); proxy != nil {
+		c.proxies.Report(proxy, err)
+	}
+	if err != nil {
+		cancel()
+		return resp, err
+	}
+	// O prazo continua valendo para a leitura do corpo.
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelOnClose libera o prazo da busca quando o corpo é fechado.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
 
-	return c.client.Do(req)
+func (b *cancelOnClose) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
 }
@@ -3,11 +3,17 @@ package crawler
 import (
 	"context"
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/nettojulio/ufape-crawler-golang/internal/metrics"
 )
 
 func TestNewHTTPClient(t *testing.T) {
@@ -22,8 +28,8 @@ func TestNewHTTPClient(t *testing.T) {
 		t.Fatal("internal http.Client is nil")
 	}
 
-	if client.client.Timeout != testTimeout {
-		t.Errorf("expected timeout %v, got %v", testTimeout, client.client.Timeout)
+	if client.timeout != testTimeout {
+		t.Errorf("expected timeout %v, got %v", testTimeout, client.timeout)
 	}
 
 	if client.userAgent == "" {
@@ -31,6 +37,44 @@ func TestNewHTTPClient(t *testing.T) {
 	}
 }
 
+func TestHTTPClientTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+	client := NewHTTPClient(50 * time.Millisecond)
+
+	t.Run("should apply the client timeout without a context deadline", func(t *testing.T) {
+		_, err := client.Get(context.Background(), server.URL, RequestOptions{})
+		if ClassifyError(err) != ErrorClassTimeout {
+			t.Errorf("expected a timeout, got %v", err)
+		}
+	})
+
+	t.Run("should keep the client timeout when the context deadline is longer", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, err := client.Get(ctx, server.URL, RequestOptions{})
+		if ClassifyError(err) != ErrorClassTimeout {
+			t.Errorf("expected the client timeout to cap the context deadline, got %v", err)
+		}
+	})
+
+	t.Run("should read the full body within the client timeout", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		resp, err := NewHTTPClient(5*time.Second).Get(ctx, server.URL, RequestOptions{})
+		if err != nil {
+			t.Fatalf("Get() returned an unexpected error: %v", err)
+		}
+		defer resp.Body.Close()
+		if body, err := io.ReadAll(resp.Body); err != nil || string(body) != "ok" {
+			t.Errorf("expected the full body, got %q (%v)", body, err)
+		}
+	})
+}
+
 func TestHTTPClient_Get(t *testing.T) {
 	expectedUserAgent := "Mozilla/5.0 (X11; Linux x86_64; rv:145.0) Gecko/20100101 Firefox/145.0"
 
@@ -44,7 +88,7 @@ func TestHTTPClient_Get(t *testing.T) {
 		defer server.Close()
 
 		client := NewHTTPClient(5 * time.Second)
-		resp, err := client.Get(context.Background(), server.URL)
+		resp, err := client.Get(context.Background(), server.URL, RequestOptions{})
 
 		if err != nil {
 			t.Fatalf("Get() returned an unexpected error: %v", err)
@@ -61,7 +105,7 @@ func TestHTTPClient_Get(t *testing.T) {
 		defer server.Close()
 
 		client := NewHTTPClient(5 * time.Second)
-		resp, err := client.Get(context.Background(), server.URL)
+		resp, err := client.Get(context.Background(), server.URL, RequestOptions{})
 
 		if err != nil {
 			t.Fatalf("Get() returned an unexpected error: %v", err)
@@ -79,7 +123,7 @@ func TestHTTPClient_Get(t *testing.T) {
 		defer server.Close()
 
 		client := NewHTTPClient(50 * time.Millisecond)
-		_, err := client.Get(context.Background(), server.URL)
+		_, err := client.Get(context.Background(), server.URL, RequestOptions{})
 
 		if err == nil {
 			t.Fatal("expected a timeout error, but got nil")
@@ -99,7 +143,7 @@ func TestHTTPClient_Get(t *testing.T) {
 
 		cancel()
 
-		_, err := client.Get(ctx, server.URL)
+		_, err := client.Get(ctx, server.URL, RequestOptions{})
 
 		if err == nil {
 			t.Fatal("expected a context canceled error, but got nil")
@@ -110,3 +154,98 @@ func TestHTTPClient_Get(t *testing.T) {
 		}
 	})
 }
+
+func TestHTTPClientOptions(t *testing.T) {
+	var hops int
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ua := r.Header.Get("User-Agent"); ua != "ufape-bot/1.0" {
+			t.Errorf("expected the configured User-Agent, got %q", ua)
+		}
+		hops++
+		http.Redirect(w, r, server.URL+"/next", http.StatusFound)
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(5*time.Second, WithUserAgent("ufape-bot/1.0"), WithMaxRedirects(2))
+	_, err := client.Get(context.Background(), server.URL, RequestOptions{})
+
+	if !errors.Is(err, ErrTooManyRedirects) {
+		t.Fatalf("expected ErrTooManyRedirects, got %v", err)
+	}
+	if hops != 2 {
+		t.Errorf("expected 2 requests before giving up, got %d", hops)
+	}
+}
+
+func TestHTTPClientRequestOptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("User-Agent"); got != "override/2.0" {
+			t.Errorf("expected the User-Agent override, got %q", got)
+		}
+		if got := r.Header.Get("Accept-Language"); got != "en-US,en;q=0.9" {
+			t.Errorf("expected Accept-Language from the options, got %q", got)
+		}
+		if got := r.Header.Get("X-Section"); got != "news" {
+			t.Errorf("expected the custom header, got %q", got)
+		}
+		if cookie, err := r.Cookie("session"); err != nil || cookie.Value != "abc" {
+			t.Errorf("expected the session cookie, got %v (%v)", cookie, err)
+		}
+		if user, pass, ok := r.BasicAuth(); !ok || user != "user" || pass != "secret" {
+			t.Errorf("expected basic auth credentials, got %q/%q", user, pass)
+		}
+	}))
+	defer server.Close()
+
+	client := NewHTTPClient(5 * time.Second)
+	_, err := client.Get(context.Background(), server.URL, RequestOptions{
+		Headers:        map[string]string{"X-Section": "news", "User-Agent": "ignored"},
+		UserAgent:      "override/2.0",
+		AcceptLanguage: "en-US,en;q=0.9",
+		BasicAuth:      &BasicAuth{Username: "user", Password: "secret"},
+		Cookies:        map[string]string{"session": "abc"},
+	})
+	if err != nil {
+		t.Fatalf("Get() returned an unexpected error: %v", err)
+	}
+}
+
+func TestHTTPClientMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+			return
+		}
+		w.Write([]byte("<html><title>Nova</title></html>"))
+	}))
+	defer server.Close()
+
+	redirects := testutil.ToFloat64(metrics.FetchRedirects)
+	bytesRead := testutil.ToFloat64(metrics.BytesDownloaded)
+	pages := testutil.ToFloat64(metrics.Crawls.WithLabelValues("2xx"))
+
+	service := NewService(NewHTTPClient(5 * time.Second))
+	target, _ := url.Parse(server.URL + "/old")
+	payload := Payload{Url: target.String()}
+	modified := PrepareURLAndDefaults(&payload, target)
+	if _, err := service.Crawl(context.Background(), payload, target, modified); err != nil {
+		t.Fatalf("Crawl() returned an unexpected error: %v", err)
+	}
+
+	if got := testutil.ToFloat64(metrics.FetchRedirects) - redirects; got != 1 {
+		t.Errorf("expected 1 redirect, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.BytesDownloaded) - bytesRead; got != 32 {
+		t.Errorf("expected 32 bytes downloaded, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.Crawls.WithLabelValues("2xx")) - pages; got != 1 {
+		t.Errorf("expected 1 crawled page, got %v", got)
+	}
+	if testutil.ToFloat64(metrics.CrawlsInFlight) != 0 {
+		t.Error("expected no crawls in flight after Crawl returned")
+	}
+	if n := testutil.CollectAndCount(metrics.FetchDuration); n < 2 {
+		t.Errorf("expected fetch latency for the redirect and the page, got %d series", n)
+	}
+}
@@ -1,27 +1,69 @@
 package crawler
 
 import (
-	"io"
 	"net/url"
 	"time"
 )
 
 // Payload define a estrutura do corpo da requisição para o endpoint de crawling.
+// IncludeBody devolve o corpo da página como texto ou base64, limitado a
+// MaxBodySize bytes.
 type Payload struct {
-	Url               string    `json:"url" validate:"required,url" example:"http://ufape.edu.br"`
-	Timeout           *int      `json:"timeout,omitempty" example:"60"`
+	Url string `json:"url" validate:"required,url" example:"http://ufape.edu.br"`
+	// Timeout limita a busca, em segundos, incluindo os redirecionamentos e a
+	// leitura do corpo. CRAWLER_TIMEOUT continua sendo o limite máximo.
+	Timeout           *int      `json:"timeout,omitempty" validate:"omitempty,gt=0" example:"60"`
 	RemoveFragment    *bool     `json:"remove_fragment,omitempty" example:"false"`
 	AllowedDomains    *[]string `json:"allowed_domains,omitempty" example:"ufape.edu.br"`
 	CollectSubdomains *bool     `json:"collect_subdomains,omitempty" example:"true"`
 	LowerCaseURLs     *bool     `json:"lower_case_urls,omitempty" example:"false"`
 	CanRetry          *bool     `json:"can_retry,omitempty" example:"false"`
-	MaxAttempts       *int      `json:"max_attempts,omitempty" example:"1"`
+	// MaxAttempts é o número de tentativas da busca, até 10. Cada tentativa
+	// conta como uma página na cota da chave de API.
+	MaxAttempts *int    `json:"max_attempts,omitempty" validate:"omitempty,gt=0,lte=10" example:"1"`
+	IncludeBody *string `json:"include_body,omitempty" validate:"omitempty,oneof=none text base64" enums:"none,text,base64" example:"none"`
+	MaxBodySize *int    `json:"max_body_size,omitempty" validate:"omitempty,gt=0,lte=33554432" example:"1048576"`
+	RequestOptions
 }
 
+// RequestOptions personaliza a requisição HTTP de uma busca. Headers é
+// aplicado antes dos demais campos, que têm prioridade sobre ele. Nos
+// redirecionamentos para outro domínio, o Go não reenvia os cookies nem as
+// credenciais.
+type RequestOptions struct {
+	Headers map[string]string `json:"headers,omitempty" validate:"omitempty,max=32,dive,keys,required,max=256,endkeys,max=8192"`
+	// UserAgent substitui o User-Agent configurado no cliente.
+	UserAgent      string            `json:"user_agent,omitempty" example:"ufape-crawler/1.0"`
+	AcceptLanguage string            `json:"accept_language,omitempty" example:"en-US,en;q=0.9"`
+	BasicAuth      *BasicAuth        `json:"basic_auth,omitempty"`
+	Cookies        map[string]string `json:"cookies,omitempty" validate:"omitempty,max=32,dive,keys,required,endkeys"`
+	// Proxy é a URL (http, https, socks5 ou socks5h) do proxy desta busca,
+	// aceita apenas quando PROXY_ALLOW_PER_REQUEST está habilitado.
+	Proxy string `json:"proxy,omitempty" validate:"omitempty,url" example:"socks5://proxy.ufape.edu.br:1080"`
+	// Session é o nome de uma sessão configurada em CRAWLER_SESSIONS_FILE,
+	// cujos cookies, obtidos no login, são usados na busca.
+	Session string `json:"session,omitempty" example:"intranet"`
+}
+
+// BasicAuth são as credenciais enviadas com a autenticação HTTP básica.
+type BasicAuth struct {
+	Username string `json:"username" validate:"required" example:"usuario"`
+	Password string `json:"password" example:"senha"`
+}
+
+// Modos aceitos em Payload.IncludeBody.
+const (
+	BodyNone   = "none"
+	BodyText   = "text"
+	BodyBase64 = "base64"
+)
+
 // LinksResponse agrupa os links encontrados.
 type LinksResponse struct {
 	Available   []string `json:"available" example:"http://ufape.edu.br/link-valido"`
 	Unavailable []string `json:"unavailable" example:"http://ufape.edu.br/link-quebrado"`
+	// Occurrences conta quantas âncoras da página apontam para cada link normalizado.
+	Occurrences map[string]int `json:"occurrences,omitempty"`
 }
 
 // URLDetails fornece uma representação detalhada de uma URL.
@@ -55,12 +97,22 @@ type DetailsResponseDTO struct {
 
 // ResponseDTO é a resposta principal da API.
 type ResponseDTO struct {
-	StatusCode  int                `json:"statusCode" example:"200"`
-	ContentType string             `json:"contentType" example:"text/html; charset=utf-8"`
-	ElapsedTime int64              `json:"elapsedTime" example:"150"`
-	Links       LinksResponse      `json:"links"`
-	Title       string             `json:"title" example:"Universidade Federal do Agreste de Pernambuco"`
-	Details     DetailsResponseDTO `json:"details"`
+	StatusCode  int    `json:"statusCode" example:"200"`
+	ContentType string `json:"contentType" example:"text/html; charset=utf-8"`
+	// Charset é a codificação detectada da página, convertida para UTF-8 antes
+	// da extração do título e dos links.
+	Charset     string        `json:"charset,omitempty" example:"windows-1252"`
+	ElapsedTime int64         `json:"elapsedTime" example:"150"`
+	Links       LinksResponse `json:"links"`
+	Title       string        `json:"title" example:"Universidade Federal do Agreste de Pernambuco"`
+	ErrorClass  ErrorClass    `json:"errorClass,omitempty" example:"timeout"`
+	ContentHash string        `json:"contentHash,omitempty" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	// Body é o corpo da página, presente apenas quando solicitado em include_body,
+	// codificado conforme BodyEncoding.
+	Body          string             `json:"body,omitempty" example:"<!DOCTYPE html>..."`
+	BodyEncoding  string             `json:"bodyEncoding,omitempty" enums:"text,base64" example:"text"`
+	BodyTruncated bool               `json:"bodyTruncated,omitempty" example:"false"`
+	Details       DetailsResponseDTO `json:"details"`
 }
 
 // CrawlResult é um modelo interno para transportar o resultado do crawling.
@@ -70,8 +122,43 @@ type CrawlResult struct {
 	ElapsedTime time.Duration
 	Links       LinksResponse
 	Title       string
-	Body        io.ReadCloser
-	FinalURL    *url.URL
+	ErrorClass  ErrorClass
+	// ContentHash é o SHA-256, em hexadecimal, do corpo de respostas 200.
+	ContentHash string
+	// Body é o corpo da resposta, lido até MaxBodySize bytes.
+	Body []byte
+	// BodyTruncated indica que o corpo excedeu MaxBodySize e não foi lido por inteiro.
+	BodyTruncated bool
+	// Charset é o nome canônico da codificação detectada em respostas 200 de
+	// texto, como "utf-8" ou "windows-1252".
+	Charset  string
+	FinalURL *url.URL
+}
+
+// BatchPayload define o corpo da requisição para o endpoint de crawling em lote.
+// Os campos omitidos em cada item são preenchidos com os de Defaults.
+type BatchPayload struct {
+	Items    []Payload `json:"items" validate:"required,min=1"`
+	Defaults *Payload  `json:"defaults,omitempty" validate:"-"`
+	// Stream devolve os resultados em NDJSON, um por linha, à medida que terminam.
+	Stream bool `json:"stream,omitempty" example:"false"`
+}
+
+// BatchItemResult é o resultado de um item do lote. Index é a posição do item
+// na requisição; Error é preenchido quando o item falha.
+type BatchItemResult struct {
+	Index  int          `json:"index" example:"0"`
+	URL    string       `json:"url" example:"http://ufape.edu.br"`
+	Result *ResponseDTO `json:"result,omitempty"`
+	Error  string       `json:"error,omitempty" example:"invalid url"`
+}
+
+// BatchResponse é a resposta do endpoint de crawling em lote, com os
+// resultados na ordem dos itens da requisição.
+type BatchResponse struct {
+	Results   []BatchItemResult `json:"results"`
+	Succeeded int               `json:"succeeded" example:"9"`
+	Failed    int               `json:"failed" example:"1"`
 }
 
 // APIHealth define a estrutura da resposta do endpoint de verificação de saúde.
@@ -18,27 +18,42 @@ type ParseOptions struct {
 }
 
 func GetTitle(doc *html.Node) string {
-	var title string
-	if n := getTitleNode(doc); n != nil && n.FirstChild != nil {
-		title = strings.TrimSpace(n.FirstChild.Data)
-	}
-	if title == "" {
-		return "[Empty title]"
+	if title := htmlTitle(doc); title != "" {
+		return title
 	}
-	return title
+	return emptyTitle
 }
 
+// ExtractLinks classifica os links das âncoras de doc e dos feeds que ele
+// anuncia em <link rel="alternate">.
 func ExtractLinks(doc *html.Node, opts ParseOptions) LinksResponse {
-	links := LinksResponse{Available: []string{}, Unavailable: []string{}}
+	return CollectLinks(htmlLinks(doc), opts)
+}
+
+// CollectLinks resolve hrefs contra opts.BaseURL, normaliza cada link e o
+// classifica como disponível, quando está nos domínios permitidos, ou
+// indisponível. Links repetidos são contados em Occurrences.
+func CollectLinks(hrefs []string, opts ParseOptions) LinksResponse {
+	links := LinksResponse{Available: []string{}, Unavailable: []string{}, Occurrences: map[string]int{}}
 	unique := make(map[string]struct{})
 
 	currentNormalizedURL := NormalizeURL(opts.BaseURL.String(), opts.RemoveFragment, opts.LowerCaseURLs)
 	unique[currentNormalizedURL] = struct{}{}
 
-	curateNodes(doc, opts, unique, &links)
+	for _, href := range hrefs {
+		processHref(href, opts, unique, &links)
+	}
 	return links
 }
 
+// htmlTitle retorna o texto do primeiro <title> de doc, ou vazio.
+func htmlTitle(doc *html.Node) string {
+	if n := getTitleNode(doc); n != nil && n.FirstChild != nil {
+		return strings.TrimSpace(n.FirstChild.Data)
+	}
+	return ""
+}
+
 func getTitleNode(n *html.Node) *html.Node {
 	if n.Type == html.ElementNode && n.Data == "title" {
 		return n
@@ -51,17 +66,38 @@ func getTitleNode(n *html.Node) *html.Node {
 	return nil
 }
 
-func curateNodes(n *html.Node, opts ParseOptions, unique map[string]struct{}, links *LinksResponse) {
-	if n.Type == html.ElementNode && n.Data == "a" {
-		for _, attr := range n.Attr {
-			if attr.Key == "href" {
-				processHref(attr.Val, opts, unique, links)
+// htmlLinks retorna, na ordem do documento, os href das âncoras e dos feeds
+// RSS e Atom anunciados em <link rel="alternate">.
+func htmlLinks(n *html.Node) []string {
+	var hrefs []string
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch {
+			case n.Data == "a":
+				for _, attr := range n.Attr {
+					if attr.Key == "href" {
+						hrefs = append(hrefs, attr.Val)
+					}
+				}
+			case n.Data == "link" && isFeedLink(n):
+				hrefs = append(hrefs, attr(n, "href"))
 			}
 		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
 	}
-	for c := n.FirstChild; c != nil; c = c.NextSibling {
-		curateNodes(c, opts, unique, links)
-	}
+	walk(n)
+	return hrefs
+}
+
+// isFeedLink informa se o elemento <link> n anuncia um feed RSS ou Atom.
+func isFeedLink(n *html.Node) bool {
+	rels := strings.Fields(strings.ToLower(attr(n, "rel")))
+	kind := strings.ToLower(attr(n, "type"))
+	return slices.Contains(rels, "alternate") && hasAttr(n, "href") &&
+		(kind == "application/rss+xml" || kind == "application/atom+xml")
 }
 
 func processHref(href string, opts ParseOptions, unique map[string]struct{}, links *LinksResponse) {
@@ -72,6 +108,10 @@ func processHref(href string, opts ParseOptions, unique map[string]struct{}, lin
 
 	normalized := NormalizeURL(u.String(), opts.RemoveFragment, opts.LowerCaseURLs)
 
+	if count, seen := links.Occurrences[normalized]; seen {
+		links.Occurrences[normalized] = count + 1
+		return
+	}
 	if _, exists := unique[normalized]; exists {
 		return
 	}
@@ -82,6 +122,7 @@ func processHref(href string, opts ParseOptions, unique map[string]struct{}, lin
 		return
 	}
 	host := parsedNormalized.Host
+	links.Occurrences[normalized] = 1
 
 	if slices.Contains(opts.AllowedDomains, host) || (opts.CollectSubdomains && IsSubdomainHost(host, opts.AllowedDomains)) {
 		links.Available = append(links.Available, normalized)
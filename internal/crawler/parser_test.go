@@ -170,3 +170,27 @@ func TestExtractLinks(t *testing.T) {
 		})
 	}
 }
+
+func TestExtractLinksOccurrences(t *testing.T) {
+	baseURL, _ := url.Parse("https://example.com")
+	doc := parseHTML(t, `
+        <a href="/page1">Page 1</a>
+        <a href="/page1#top">Page 1 again</a>
+        <a href="https://example.com/page1">Page 1 absolute</a>
+        <a href="https://otherdomain.com">External</a>
+    `)
+
+	links := ExtractLinks(doc, ParseOptions{
+		BaseURL:        baseURL,
+		AllowedDomains: []string{"example.com"},
+		RemoveFragment: true,
+	})
+
+	expected := map[string]int{
+		"https://example.com/page1": 3,
+		"https://otherdomain.com":   1,
+	}
+	if !reflect.DeepEqual(links.Occurrences, expected) {
+		t.Errorf("mismatch in occurrences\ngot:  %v\nwant: %v", links.Occurrences, expected)
+	}
+}
@@ -0,0 +1,241 @@
+package crawler
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// emptyTitle é o título das páginas que não têm um.
+const emptyTitle = "[Empty title]"
+
+// Document é o que um Parser extrai de um corpo: o título, quando o formato
+// tem um, e os links na ordem em que aparecem, ainda sem resolver.
+type Document struct {
+	Title string
+	Links []string
+}
+
+// Parser extrai o título e os links de um corpo. Os corpos de texto chegam
+// convertidos para UTF-8.
+type Parser interface {
+	Parse(body []byte) (Document, error)
+}
+
+// ParserFunc adapta uma função ao Parser.
+type ParserFunc func(body []byte) (Document, error)
+
+func (f ParserFunc) Parse(body []byte) (Document, error) {
+	return f(body)
+}
+
+// ParserRegistry escolhe o Parser de uma resposta pelo media type do
+// Content-Type.
+type ParserRegistry struct {
+	parsers map[string]Parser
+}
+
+// NewParserRegistry cria um ParserRegistry vazio.
+func NewParserRegistry() *ParserRegistry {
+	return &ParserRegistry{parsers: make(map[string]Parser)}
+}
+
+// DefaultParsers cria um ParserRegistry com os parsers de HTML, feeds RSS e
+// Atom, sitemaps, PDF, texto e CSS.
+func DefaultParsers() *ParserRegistry {
+	r := NewParserRegistry()
+	r.Register(ParserFunc(ParseHTML), "text/html", "application/xhtml+xml")
+	r.Register(ParserFunc(ParseFeed), "application/rss+xml", "application/atom+xml", "application/rdf+xml",
+		"application/xml", "text/xml")
+	r.Register(ParserFunc(ParsePDF), "application/pdf")
+	r.Register(ParserFunc(ParseText), "text/plain")
+	r.Register(ParserFunc(ParseCSS), "text/css")
+	return r
+}
+
+// Register associa parser aos media types, substituindo o parser anterior.
+func (r *ParserRegistry) Register(parser Parser, mediaTypes ...string) {
+	for _, mediaType := range mediaTypes {
+		r.parsers[strings.ToLower(mediaType)] = parser
+	}
+}
+
+// Lookup retorna o parser e o media type de uma resposta. Sem Content-Type,
+// ou com application/octet-stream, o tipo é identificado pelo conteúdo de
+// body. Os tipos terminados em +xml sem parser próprio usam o de
+// application/xml. O parser é nil quando o tipo não é suportado.
+func (r *ParserRegistry) Lookup(contentType string, body []byte) (Parser, string) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType == "application/octet-stream" {
+		mediaType, _, _ = mime.ParseMediaType(http.DetectContentType(body))
+	}
+	if parser, ok := r.parsers[mediaType]; ok {
+		return parser, mediaType
+	}
+	if strings.HasSuffix(mediaType, "+xml") {
+		return r.parsers["application/xml"], mediaType
+	}
+	return nil, mediaType
+}
+
+// ParseHTML extrai o título e os links das âncoras de uma página HTML.
+func ParseHTML(body []byte) (Document, error) {
+	doc, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		return Document{}, err
+	}
+	return Document{Title: htmlTitle(doc), Links: htmlLinks(doc)}, nil
+}
+
+// ParseFeed extrai o título e os links dos itens de feeds RSS 2.0, RSS 1.0
+// (RDF) e Atom, e as URLs de sitemaps. Outros documentos XML não têm links.
+func ParseFeed(body []byte) (Document, error) {
+	dec := xml.NewDecoder(bytes.NewReader(body))
+	dec.Strict = false
+	dec.Entity = xml.HTMLEntity
+	// O corpo já foi convertido para UTF-8, qualquer que seja a declaração.
+	dec.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) { return input, nil }
+
+	var doc Document
+	var root string
+	var path []string
+	var text strings.Builder
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			if root == "" {
+				return Document{}, fmt.Errorf("failed to parse xml: %w", err)
+			}
+			// Um feed cortado ainda tem os itens lidos até o erro.
+			break
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			name := t.Name.Local
+			if root == "" {
+				root = name
+			}
+			path = append(path, name)
+			text.Reset()
+			if root == "feed" && name == "link" {
+				if rel := xmlAttr(t, "rel"); rel == "" || rel == "alternate" {
+					if href := xmlAttr(t, "href"); href != "" {
+						doc.Links = append(doc.Links, href)
+					}
+				}
+			}
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			if len(path) == 0 {
+				continue
+			}
+			name, parent := path[len(path)-1], ""
+			if len(path) > 1 {
+				parent = path[len(path)-2]
+			}
+			value := strings.TrimSpace(text.String())
+			switch {
+			case name == "title" && doc.Title == "" && (parent == "channel" || (root == "feed" && parent == "feed")):
+				doc.Title = value
+			case name == "link" && root != "feed" && (parent == "item" || parent == "channel") && value != "":
+				doc.Links = append(doc.Links, value)
+			case name == "loc" && (root == "urlset" || root == "sitemapindex") && value != "":
+				doc.Links = append(doc.Links, value)
+			}
+			path = path[:len(path)-1]
+		}
+	}
+	if root == "" {
+		return Document{}, errors.New("failed to parse xml: no root element")
+	}
+	return doc, nil
+}
+
+func xmlAttr(el xml.StartElement, name string) string {
+	for _, a := range el.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+var textURL = regexp.MustCompile(`(?i)\bhttps?://[^\s<>"'{}|\\^` + "`" + `\[\]]+`)
+
+// ParseText encontra as URLs http e https de um texto simples. A pontuação
+// no fim de uma URL, como o ponto final de uma frase, não faz parte dela.
+func ParseText(body []byte) (Document, error) {
+	var doc Document
+	for _, match := range textURL.FindAll(body, -1) {
+		doc.Links = append(doc.Links, trimURLPunctuation(string(match)))
+	}
+	return doc, nil
+}
+
+// trimURLPunctuation remove a pontuação final de link, mantendo os
+// parênteses que fecham um aberto dentro da URL.
+func trimURLPunctuation(link string) string {
+	for link != "" {
+		last := link[len(link)-1]
+		switch {
+		case strings.IndexByte(".,;:!?*", last) >= 0:
+		case last == ')' && strings.Count(link, "(") < strings.Count(link, ")"):
+		default:
+			return link
+		}
+		link = link[:len(link)-1]
+	}
+	return link
+}
+
+var (
+	cssComment = regexp.MustCompile(`(?s)/\*.*?\*/`)
+	cssURL     = regexp.MustCompile(`(?i)url\(\s*(?:"([^"]*)"|'([^']*)'|([^)"'\s]*))\s*\)`)
+	cssImport  = regexp.MustCompile(`(?i)@import\s+(?:"([^"]*)"|'([^']*)')`)
+)
+
+// ParseCSS extrai as URLs de url() e de @import de uma folha de estilos, como
+// imagens, fontes e outras folhas.
+func ParseCSS(body []byte) (Document, error) {
+	body = cssComment.ReplaceAll(body, nil)
+
+	type match struct {
+		pos  int
+		link string
+	}
+	var matches []match
+	for _, re := range []*regexp.Regexp{cssURL, cssImport} {
+		for _, m := range re.FindAllSubmatchIndex(body, -1) {
+			for group := 2; group < len(m); group += 2 {
+				if m[group] >= 0 {
+					matches = append(matches, match{pos: m[0], link: string(body[m[group]:m[group+1]])})
+					break
+				}
+			}
+		}
+	}
+	// Mantém a ordem do documento entre as duas expressões.
+	slices.SortStableFunc(matches, func(a, b match) int { return a.pos - b.pos })
+
+	var doc Document
+	for _, m := range matches {
+		if link := strings.TrimSpace(m.link); link != "" {
+			doc.Links = append(doc.Links, link)
+		}
+	}
+	return doc, nil
+}
@@ -0,0 +1,210 @@
+package crawler
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestParserRegistryLookup(t *testing.T) {
+	registry := DefaultParsers()
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		mediaType   string
+		found       bool
+	}{
+		{"should match the media type ignoring parameters", "text/css; charset=utf-8", "", "text/css", true},
+		{"should fall back to the xml parser for +xml types", "application/vnd.custom+xml", "", "application/vnd.custom+xml", true},
+		{"should sniff a missing content type", "", "<!DOCTYPE html><title>x</title>", "text/html", true},
+		{"should sniff octet-stream bodies", "application/octet-stream", "%PDF-1.7\n", "application/pdf", true},
+		{"should not parse unsupported types", "image/png", "", "image/png", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser, mediaType := registry.Lookup(tt.contentType, []byte(tt.body))
+			if mediaType != tt.mediaType || (parser != nil) != tt.found {
+				t.Errorf("expected %s (found %v), got %s (found %v)", tt.mediaType, tt.found, mediaType, parser != nil)
+			}
+		})
+	}
+
+	t.Run("should let registered parsers replace the defaults", func(t *testing.T) {
+		registry := DefaultParsers()
+		registry.Register(ParserFunc(func([]byte) (Document, error) {
+			return Document{Title: "custom"}, nil
+		}), "Text/CSS")
+		parser, _ := registry.Lookup("text/css", nil)
+		if doc, _ := parser.Parse(nil); doc.Title != "custom" {
+			t.Errorf("expected the custom parser, got %q", doc.Title)
+		}
+	})
+}
+
+func TestParseHTML(t *testing.T) {
+	doc, err := ParseHTML([]byte(`<html><head><title>Notícias</title>
+		<link rel="alternate" type="application/rss+xml" href="/feed.xml">
+		<link rel="stylesheet" href="/style.css"></head>
+		<body><a href="/a">A</a></body></html>`))
+	if err != nil {
+		t.Fatalf("ParseHTML returned an error: %v", err)
+	}
+	expected := Document{Title: "Notícias", Links: []string{"/feed.xml", "/a"}}
+	if !reflect.DeepEqual(doc, expected) {
+		t.Errorf("expected %+v, got %+v", expected, doc)
+	}
+}
+
+func TestParseFeed(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected Document
+	}{
+		{
+			name: "should read rss 2.0 channel and items",
+			body: `<?xml version="1.0" encoding="ISO-8859-1"?><rss version="2.0"><channel>
+				<title>UFAPE</title><link>https://ufape.edu.br/</link>
+				<image><title>Logo</title><link>https://ufape.edu.br/logo</link></image>
+				<item><title>Edital &amp; resultado</title><link>https://ufape.edu.br/edital?a=1&amp;b=2</link></item>
+				</channel></rss>`,
+			expected: Document{Title: "UFAPE", Links: []string{"https://ufape.edu.br/", "https://ufape.edu.br/edital?a=1&b=2"}},
+		},
+		{
+			name: "should read rss 1.0 items",
+			body: `<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/">
+				<channel><title>RDF</title><link>https://ufape.edu.br/</link></channel>
+				<item><link>https://ufape.edu.br/item</link></item></rdf:RDF>`,
+			expected: Document{Title: "RDF", Links: []string{"https://ufape.edu.br/", "https://ufape.edu.br/item"}},
+		},
+		{
+			name: "should read atom alternate links",
+			body: `<feed xmlns="http://www.w3.org/2005/Atom"><title>Atom</title>
+				<link rel="self" href="https://ufape.edu.br/atom.xml"/>
+				<link href="https://ufape.edu.br/"/>
+				<entry><title>Post</title><link rel="alternate" href="/post"/><link rel="edit" href="/edit"/></entry>
+				</feed>`,
+			expected: Document{Title: "Atom", Links: []string{"https://ufape.edu.br/", "/post"}},
+		},
+		{
+			name: "should read sitemap locations",
+			body: `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+				<url><loc> https://ufape.edu.br/a </loc></url><url><loc>https://ufape.edu.br/b</loc></url></urlset>`,
+			expected: Document{Links: []string{"https://ufape.edu.br/a", "https://ufape.edu.br/b"}},
+		},
+		{
+			name:     "should keep the items read before a truncated end",
+			body:     `<rss><channel><title>Cortado</title><item><link>https://ufape.edu.br/1</link></item><item><link>https://ufa`,
+			expected: Document{Title: "Cortado", Links: []string{"https://ufape.edu.br/1"}},
+		},
+		{
+			name:     "should ignore other xml documents",
+			body:     `<config><link>https://ufape.edu.br/</link></config>`,
+			expected: Document{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := ParseFeed([]byte(tt.body))
+			if err != nil {
+				t.Fatalf("ParseFeed returned an error: %v", err)
+			}
+			if !reflect.DeepEqual(doc, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, doc)
+			}
+		})
+	}
+
+	t.Run("should fail on documents that are not xml", func(t *testing.T) {
+		if _, err := ParseFeed([]byte("not xml")); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
+
+// buildPDF monta um PDF mínimo com uma anotação de link solta, outra num
+// stream comprimido e o título no dicionário de informações.
+func buildPDF(t *testing.T, title string) []byte {
+	t.Helper()
+	var compressed bytes.Buffer
+	w := zlib.NewWriter(&compressed)
+	fmt.Fprint(w, `<< /Type /Annot /Subtype /Link /A << /S /URI /URI <68747470733a2f2f756661706521> >> >>`)
+	w.Close()
+
+	var pdf bytes.Buffer
+	pdf.WriteString("%PDF-1.5\n%\xe2\xe3\xcf\xd3\n")
+	pdf.WriteString("1 0 obj\n<< /Type /Outlines /First 2 0 R >>\nendobj\n")
+	pdf.WriteString("2 0 obj\n<< /Title (Cap\\355tulo 1) /Parent 1 0 R /Dest [3 0 R /Fit] >>\nendobj\n")
+	pdf.WriteString("3 0 obj\n<< /Type /Annot /A << /S /URI /URI (https://ufape.edu.br/edital\\(1\\).pdf) >> >>\nendobj\n")
+	fmt.Fprintf(&pdf, "4 0 obj\n<< /Length %d /Filter /FlateDecode >>\nstream\n", compressed.Len())
+	pdf.Write(compressed.Bytes())
+	pdf.WriteString("\nendstream\nendobj\n")
+	fmt.Fprintf(&pdf, "5 0 obj\n<< /Title %s /Producer (teste) >>\nendobj\n", title)
+	pdf.WriteString("trailer\n<< /Root 1 0 R /Info 5 0 R >>\n%%EOF\n")
+	return pdf.Bytes()
+}
+
+func TestParsePDF(t *testing.T) {
+	t.Run("should read uri annotations and the info title", func(t *testing.T) {
+		doc, err := ParsePDF(buildPDF(t, "(Calend\\341rio Acad\\352mico)"))
+		if err != nil {
+			t.Fatalf("ParsePDF returned an error: %v", err)
+		}
+		expected := Document{Title: "Calendário Acadêmico", Links: []string{"https://ufape.edu.br/edital(1).pdf", "https://ufape!"}}
+		if !reflect.DeepEqual(doc, expected) {
+			t.Errorf("expected %+v, got %+v", expected, doc)
+		}
+	})
+
+	t.Run("should decode utf-16 titles", func(t *testing.T) {
+		doc, err := ParsePDF(buildPDF(t, "<FEFF0045006400690074006100 6C>"))
+		if err != nil {
+			t.Fatalf("ParsePDF returned an error: %v", err)
+		}
+		if doc.Title != "Edital" {
+			t.Errorf("expected the decoded title, got %q", doc.Title)
+		}
+	})
+
+	t.Run("should skip encrypted documents", func(t *testing.T) {
+		doc, err := ParsePDF([]byte("%PDF-1.4\ntrailer << /Encrypt 9 0 R >>\n/URI (https://ufape.edu.br/)"))
+		if err != nil || len(doc.Links) != 0 {
+			t.Errorf("expected no links and no error, got %v %v", doc.Links, err)
+		}
+	})
+
+	t.Run("should reject bodies that are not pdf", func(t *testing.T) {
+		if _, err := ParsePDF([]byte("<html></html>")); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
+
+func TestParseText(t *testing.T) {
+	doc, _ := ParseText([]byte(`Veja https://ufape.edu.br/editais. Ou (http://ufape.edu.br/wiki/Pá_(desambiguação)),
+		e "https://ufape.edu.br/a?b=1&c=2"; ftp://ufape.edu.br não conta.`))
+	expected := []string{
+		"https://ufape.edu.br/editais",
+		"http://ufape.edu.br/wiki/Pá_(desambiguação)",
+		"https://ufape.edu.br/a?b=1&c=2",
+	}
+	if !reflect.DeepEqual(doc.Links, expected) {
+		t.Errorf("expected %v, got %v", expected, doc.Links)
+	}
+}
+
+func TestParseCSS(t *testing.T) {
+	doc, _ := ParseCSS([]byte(`@import "base.css";
+		@import url('print.css') print;
+		/* background: url(comentario.png); */
+		body { background: url( "img/fundo.png" ) }
+		@font-face { src: url(fonts/a.woff2) format("woff2"), url() }
+		.logo { background-image: URL(data:image/png;base64,AAAA) }`))
+	expected := []string{"base.css", "print.css", "img/fundo.png", "fonts/a.woff2", "data:image/png;base64,AAAA"}
+	if !reflect.DeepEqual(doc.Links, expected) {
+		t.Errorf("expected %v, got %v", expected, doc.Links)
+	}
+}
@@ -0,0 +1,214 @@
+package crawler
+
+import (
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/nettojulio/ufape-crawler-golang/internal/config"
+)
+
+// Defaults são os valores aplicados aos campos omitidos do Payload e o
+// intervalo entre as tentativas de CrawlWithRetry usado pelos handlers.
+type Defaults struct {
+	Timeout           int
+	RemoveFragment    bool
+	CollectSubdomains bool
+	LowerCaseURLs     bool
+	CanRetry          bool
+	// MaxAttempts vale quando CanRetry é false e RetryMaxAttempts quando é true.
+	MaxAttempts      int
+	RetryMaxAttempts int
+	IncludeBody      string
+	MaxBodySize      int
+	RetryDelay       time.Duration
+}
+
+// DefaultDefaults retorna os valores padrão originais do crawler.
+func DefaultDefaults() Defaults {
+	return Defaults{
+		Timeout:           60,
+		RemoveFragment:    true,
+		CollectSubdomains: true,
+		LowerCaseURLs:     false,
+		CanRetry:          false,
+		MaxAttempts:       1,
+		RetryMaxAttempts:  10,
+		IncludeBody:       BodyNone,
+		MaxBodySize:       1 << 20,
+		RetryDelay:        time.Second,
+	}
+}
+
+// NewDefaults converte a configuração do crawler em Defaults.
+func NewDefaults(cfg config.CrawlerConfig) Defaults {
+	return Defaults{
+		Timeout:           cfg.Payload.Timeout,
+		RemoveFragment:    cfg.Payload.RemoveFragment,
+		CollectSubdomains: cfg.Payload.CollectSubdomains,
+		LowerCaseURLs:     cfg.Payload.LowerCaseURLs,
+		CanRetry:          cfg.Payload.CanRetry,
+		MaxAttempts:       cfg.Payload.MaxAttempts,
+		RetryMaxAttempts:  cfg.Payload.RetryMaxAttempts,
+		IncludeBody:       cfg.Payload.IncludeBody,
+		MaxBodySize:       cfg.Payload.MaxBodySize,
+		RetryDelay:        cfg.RetryDelay,
+	}
+}
+
+var defaults atomic.Pointer[Defaults]
+
+// SetDefaults troca os valores usados por PrepareURLAndDefaults. Deve ser
+// chamada na inicialização, antes de as buscas começarem.
+func SetDefaults(d Defaults) {
+	defaults.Store(&d)
+}
+
+// CurrentDefaults retorna os valores definidos por SetDefaults ou, na
+// ausência deles, DefaultDefaults.
+func CurrentDefaults() Defaults {
+	if d := defaults.Load(); d != nil {
+		return *d
+	}
+	return DefaultDefaults()
+}
+
+// PrepareURLAndDefaults preenche os campos opcionais do payload com os valores
+// de CurrentDefaults e retorna a URL normalizada que deve ser buscada.
+func PrepareURLAndDefaults(payload *Payload, originalURL *url.URL) *url.URL {
+	d := CurrentDefaults()
+	if payload.Timeout == nil {
+		def := d.Timeout
+		payload.Timeout = &def
+	}
+	if payload.RemoveFragment == nil {
+		def := d.RemoveFragment
+		payload.RemoveFragment = &def
+	}
+	if payload.AllowedDomains == nil {
+		def := []string{originalURL.Host}
+		payload.AllowedDomains = &def
+	}
+	if payload.CollectSubdomains == nil {
+		def := d.CollectSubdomains
+		payload.CollectSubdomains = &def
+	}
+	if payload.LowerCaseURLs == nil {
+		def := d.LowerCaseURLs
+		payload.LowerCaseURLs = &def
+	}
+	if payload.CanRetry == nil {
+		def := d.CanRetry
+		payload.CanRetry = &def
+	}
+	if payload.MaxAttempts == nil {
+		def := d.MaxAttempts
+		if *payload.CanRetry {
+			def = d.RetryMaxAttempts
+		}
+		payload.MaxAttempts = &def
+	}
+
+	if payload.IncludeBody == nil {
+		def := d.IncludeBody
+		payload.IncludeBody = &def
+	}
+	if payload.MaxBodySize == nil {
+		def := d.MaxBodySize
+		payload.MaxBodySize = &def
+	}
+
+	for i, domain := range *payload.AllowedDomains {
+		(*payload.AllowedDomains)[i] = strings.TrimPrefix(domain, "www.")
+	}
+
+	normalizedStr := NormalizeURL(originalURL.String(), *payload.RemoveFragment, *payload.LowerCaseURLs)
+	modifiedURL, _ := url.Parse(normalizedStr)
+	return modifiedURL
+}
+
+// Attempts retorna quantas buscas payload pode fazer: MaxAttempts ou, quando
+// omitido, o valor que PrepareURLAndDefaults aplicaria.
+func Attempts(payload Payload) int {
+	if payload.MaxAttempts != nil {
+		return max(*payload.MaxAttempts, 1)
+	}
+	d := CurrentDefaults()
+	if payload.CanRetry != nil && *payload.CanRetry || payload.CanRetry == nil && d.CanRetry {
+		return d.RetryMaxAttempts
+	}
+	return d.MaxAttempts
+}
+
+// MergePayload preenche os campos opcionais ausentes em item com os de defaults.
+// A URL de item é mantida.
+func MergePayload(item, defaults Payload) Payload {
+	if item.Timeout == nil {
+		item.Timeout = defaults.Timeout
+	}
+	if item.RemoveFragment == nil {
+		item.RemoveFragment = defaults.RemoveFragment
+	}
+	if item.AllowedDomains == nil && defaults.AllowedDomains != nil {
+		domains := append([]string(nil), *defaults.AllowedDomains...)
+		item.AllowedDomains = &domains
+	}
+	if item.CollectSubdomains == nil {
+		item.CollectSubdomains = defaults.CollectSubdomains
+	}
+	if item.LowerCaseURLs == nil {
+		item.LowerCaseURLs = defaults.LowerCaseURLs
+	}
+	if item.CanRetry == nil {
+		item.CanRetry = defaults.CanRetry
+	}
+	if item.MaxAttempts == nil {
+		item.MaxAttempts = defaults.MaxAttempts
+	}
+	if item.IncludeBody == nil {
+		item.IncludeBody = defaults.IncludeBody
+	}
+	if item.MaxBodySize == nil {
+		item.MaxBodySize = defaults.MaxBodySize
+	}
+	item.RequestOptions = mergeRequestOptions(item.RequestOptions, defaults.RequestOptions)
+	return item
+}
+
+// mergeRequestOptions preenche os campos vazios de item com os de defaults.
+// Os cabeçalhos e os cookies são combinados, prevalecendo os de item.
+func mergeRequestOptions(item, defaults RequestOptions) RequestOptions {
+	item.Headers = mergeMaps(defaults.Headers, item.Headers)
+	item.Cookies = mergeMaps(defaults.Cookies, item.Cookies)
+	if item.UserAgent == "" {
+		item.UserAgent = defaults.UserAgent
+	}
+	if item.AcceptLanguage == "" {
+		item.AcceptLanguage = defaults.AcceptLanguage
+	}
+	if item.BasicAuth == nil {
+		item.BasicAuth = defaults.BasicAuth
+	}
+	if item.Proxy == "" {
+		item.Proxy = defaults.Proxy
+	}
+	if item.Session == "" {
+		item.Session = defaults.Session
+	}
+	return item
+}
+
+func mergeMaps(base, override map[string]string) map[string]string {
+	if len(base) == 0 {
+		return override
+	}
+	merged := make(map[string]string, len(base)+len(override))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range override {
+		merged[k] = v
+	}
+	return merged
+}
@@ -0,0 +1,232 @@
+package crawler
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/hex"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+	"unicode/utf16"
+)
+
+// maxPDFInflate é o total de bytes descomprimidos dos streams de um PDF,
+// que limita o custo de arquivos grandes ou de bombas de compressão.
+const maxPDFInflate = 64 << 20
+
+var (
+	errNotPDF = errors.New("not a pdf document")
+
+	pdfURI    = regexp.MustCompile(`/URI\s*[(<]`)
+	pdfTitle  = regexp.MustCompile(`/Title\s*[(<]`)
+	pdfInfo   = regexp.MustCompile(`/Info\s+(\d+)\s+(\d+)\s+R`)
+	pdfStream = regexp.MustCompile(`stream\r?\n`)
+)
+
+// ParsePDF extrai de um PDF os links das anotações /URI e o título do
+// dicionário de informações. O texto das páginas não é interpretado, então
+// URLs escritas sem anotação ficam de fora. PDFs criptografados não têm links.
+func ParsePDF(body []byte) (Document, error) {
+	start := bytes.Index(body[:min(len(body), 1024)], []byte("%PDF-"))
+	if start < 0 {
+		return Document{}, errNotPDF
+	}
+	body = body[start:]
+	if bytes.Contains(body, []byte("/Encrypt")) {
+		return Document{}, nil
+	}
+
+	sources := pdfSources(body)
+
+	var doc Document
+	for _, src := range sources {
+		for _, loc := range pdfURI.FindAllIndex(src, -1) {
+			if uri := strings.TrimSpace(pdfString(src[loc[1]-1:])); uri != "" {
+				doc.Links = append(doc.Links, uri)
+			}
+		}
+	}
+	doc.Title = pdfDocumentTitle(sources)
+	return doc, nil
+}
+
+// pdfSources retorna o texto de body onde procurar links: o próprio body,
+// sem os streams comprimidos, seguido dos streams descomprimidos, incluindo
+// os streams de objetos, onde ficam as anotações de PDFs mais novos. Streams
+// que não são zlib ficam no body.
+func pdfSources(body []byte) [][]byte {
+	var plain []byte
+	var inflated [][]byte
+	budget := int64(maxPDFInflate)
+	last := 0
+	for _, loc := range pdfStream.FindAllIndex(body, -1) {
+		if budget <= 0 {
+			break
+		}
+		if loc[0] < last {
+			continue
+		}
+		data := body[loc[1]:]
+		if end := bytes.Index(data, []byte("endstream")); end >= 0 {
+			data = data[:end]
+		}
+		r, err := zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			continue
+		}
+		out, _ := io.ReadAll(io.LimitReader(r, budget))
+		r.Close()
+		budget -= int64(len(out))
+		if len(out) > 0 {
+			inflated = append(inflated, out)
+		}
+		plain = append(plain, body[last:loc[1]]...)
+		last = loc[1] + len(data)
+	}
+	plain = append(plain, body[last:]...)
+	return append([][]byte{plain}, inflated...)
+}
+
+// pdfDocumentTitle lê o /Title do objeto apontado por /Info no trailer.
+// Sem ele, usa o primeiro /Title fora dos marcadores do sumário, que também
+// têm títulos.
+func pdfDocumentTitle(sources [][]byte) string {
+	body := sources[0]
+	for _, src := range sources {
+		m := pdfInfo.FindSubmatch(src)
+		if m == nil {
+			continue
+		}
+		header := regexp.MustCompile(`(?:^|\s)` + string(m[1]) + `\s+` + string(m[2]) + `\s+obj\b`)
+		loc := header.FindIndex(body)
+		if loc == nil {
+			break
+		}
+		object := body[loc[1]:]
+		if end := bytes.Index(object, []byte("endobj")); end >= 0 {
+			object = object[:end]
+		}
+		if t := pdfTitle.FindIndex(object); t != nil {
+			return strings.TrimSpace(pdfString(object[t[1]-1:]))
+		}
+		break
+	}
+	for _, src := range sources {
+		for _, loc := range pdfTitle.FindAllIndex(src, -1) {
+			dict := src[max(0, loc[0]-256):loc[0]]
+			if bytes.Contains(dict, []byte("/Parent")) || bytes.Contains(dict, []byte("/Dest")) {
+				continue
+			}
+			if title := strings.TrimSpace(pdfString(src[loc[1]-1:])); title != "" {
+				return title
+			}
+		}
+	}
+	return ""
+}
+
+// pdfString decodifica a string literal ou hexadecimal no início de data
+// como texto UTF-8.
+func pdfString(data []byte) string {
+	if len(data) == 0 {
+		return ""
+	}
+	var raw []byte
+	switch data[0] {
+	case '(':
+		raw = pdfLiteral(data[1:])
+	case '<':
+		end := bytes.IndexByte(data, '>')
+		if end < 0 {
+			return ""
+		}
+		digits := bytes.Map(func(r rune) rune {
+			if strings.ContainsRune("0123456789abcdefABCDEF", r) {
+				return r
+			}
+			return -1
+		}, data[1:end])
+		if len(digits)%2 == 1 {
+			digits = append(digits, '0')
+		}
+		raw, _ = hex.DecodeString(string(digits))
+	default:
+		return ""
+	}
+	return pdfText(raw)
+}
+
+// pdfLiteral lê o conteúdo de uma string literal, que começa logo depois do
+// "(", tratando os escapes e os parênteses balanceados.
+func pdfLiteral(data []byte) []byte {
+	var out []byte
+	depth := 0
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case c == '(':
+			depth++
+		case c == ')':
+			if depth == 0 {
+				return out
+			}
+			depth--
+		case c == '\\' && i+1 < len(data):
+			i++
+			switch e := data[i]; e {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r', '\n':
+				// Quebra de linha escapada continua a string.
+				if e == '\r' && i+1 < len(data) && data[i+1] == '\n' {
+					i++
+				}
+				continue
+			default:
+				if e >= '0' && e <= '7' {
+					n := 0
+					for j := 0; j < 3 && i < len(data) && data[i] >= '0' && data[i] <= '7'; j++ {
+						n = n*8 + int(data[i]-'0')
+						i++
+					}
+					i--
+					c = byte(n)
+				} else {
+					c = e
+				}
+			}
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+// pdfText converte uma string de texto do PDF, em UTF-16BE com BOM, UTF-8
+// com BOM ou PDFDocEncoding, para UTF-8. O PDFDocEncoding é tratado como
+// Latin-1, que coincide com ele nos caracteres acentuados.
+func pdfText(raw []byte) string {
+	switch {
+	case bytes.HasPrefix(raw, []byte{0xFE, 0xFF}):
+		units := make([]uint16, 0, len(raw)/2)
+		for i := 2; i+1 < len(raw); i += 2 {
+			units = append(units, uint16(raw[i])<<8|uint16(raw[i+1]))
+		}
+		return string(utf16.Decode(units))
+	case bytes.HasPrefix(raw, utf8BOM):
+		return strings.ToValidUTF8(string(raw[len(utf8BOM):]), "\uFFFD")
+	}
+	runes := make([]rune, len(raw))
+	for i, b := range raw {
+		runes[i] = rune(b)
+	}
+	return string(runes)
+}
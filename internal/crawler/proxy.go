@@ -0,0 +1,295 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nettojulio/ufape-crawler-golang/internal/logging"
+	"github.com/nettojulio/ufape-crawler-golang/internal/metrics"
+)
+
+var (
+	// ErrNoProxyAvailable é retornado quando todos os proxies do pool foram
+	// afastados após falhas.
+	ErrNoProxyAvailable = errors.New("no proxy available")
+	// ErrProxyNotAllowed é retornado quando uma busca pede um proxy próprio e
+	// o cliente não foi criado com WithRequestProxies.
+	ErrProxyNotAllowed = errors.New("per-request proxies are disabled")
+)
+
+// ProxyStrategy define como o ProxyPool escolhe o proxy de cada busca.
+type ProxyStrategy string
+
+const (
+	// ProxyRoundRobin alterna entre os proxies disponíveis.
+	ProxyRoundRobin ProxyStrategy = "round-robin"
+	// ProxySticky usa sempre o mesmo proxy para o mesmo host enquanto ele
+	// estiver disponível.
+	ProxySticky ProxyStrategy = "sticky"
+)
+
+// ProxyPoolOptions configura o ProxyPool. Os campos zerados usam os padrões.
+type ProxyPoolOptions struct {
+	// Strategy é ProxyRoundRobin por padrão.
+	Strategy ProxyStrategy
+	// MaxFailures é quantas falhas de conexão seguidas afastam um proxy. O
+	// padrão é 3.
+	MaxFailures int
+	// HealthTimeout limita cada verificação feita por Run. O padrão é 5s.
+	HealthTimeout time.Duration
+	Logger        *slog.Logger
+}
+
+// ProxyPool distribui as buscas entre proxies HTTP, HTTPS ou SOCKS5 e afasta
+// os que falham. Os afastados voltam ao pool quando a verificação de Run
+// consegue se conectar a eles.
+type ProxyPool struct {
+	proxies  []*proxyState
+	strategy ProxyStrategy
+	opts     ProxyPoolOptions
+	dial     func(ctx context.Context, network, address string) (net.Conn, error)
+
+	mu   sync.Mutex
+	next int
+}
+
+type proxyState struct {
+	url      *url.URL
+	addr     string
+	failures int
+	evicted  bool
+}
+
+// NewProxyPool cria um ProxyPool com os proxies de rawURLs, nos esquemas
+// http, https, socks5 ou socks5h.
+func NewProxyPool(rawURLs []string, opts ProxyPoolOptions) (*ProxyPool, error) {
+	if opts.Strategy == "" {
+		opts.Strategy = ProxyRoundRobin
+	}
+	if opts.Strategy != ProxyRoundRobin && opts.Strategy != ProxySticky {
+		return nil, fmt.Errorf("unknown proxy strategy %q", opts.Strategy)
+	}
+	if opts.MaxFailures <= 0 {
+		opts.MaxFailures = 3
+	}
+	if opts.HealthTimeout <= 0 {
+		opts.HealthTimeout = 5 * time.Second
+	}
+	if opts.Logger == nil {
+		opts.Logger = logging.Discard()
+	}
+
+	p := &ProxyPool{strategy: opts.Strategy, opts: opts, dial: (&net.Dialer{}).DialContext}
+	for _, raw := range rawURLs {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		u, err := ParseProxyURL(raw)
+		if err != nil {
+			return nil, err
+		}
+		state := &proxyState{url: u, addr: proxyAddr(u)}
+		p.proxies = append(p.proxies, state)
+		metrics.ProxyUp.WithLabelValues(state.addr).Set(1)
+	}
+	if len(p.proxies) == 0 {
+		return nil, errors.New("proxy pool is empty")
+	}
+	return p, nil
+}
+
+// ParseProxyURL valida a URL de um proxy.
+func ParseProxyURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy url: %w", err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("invalid proxy url %q: scheme must be http, https, socks5 or socks5h", u.Redacted())
+	}
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid proxy url %q: missing host", u.Redacted())
+	}
+	return u, nil
+}
+
+// proxyAddr retorna o host:porta discado pelo http.Transport para o proxy u.
+func proxyAddr(u *url.URL) string {
+	if port := u.Port(); port != "" {
+		return net.JoinHostPort(u.Hostname(), port)
+	}
+	port := map[string]string{"http": "80", "https": "443", "socks5": "1080", "socks5h": "1080"}[u.Scheme]
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+// Select escolhe o proxy de uma busca para host.
+func (p *ProxyPool) Select(host string) (*url.URL, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.strategy == ProxySticky {
+		return p.stickyLocked(host)
+	}
+	for range p.proxies {
+		state := p.proxies[p.next%len(p.proxies)]
+		p.next++
+		if !state.evicted {
+			return state.url, nil
+		}
+	}
+	return nil, ErrNoProxyAvailable
+}
+
+// stickyLocked escolhe o proxy por rendezvous hashing: cada host fica com o
+// proxy disponível de maior peso, e afastar um proxy só move os hosts dele.
+func (p *ProxyPool) stickyLocked(host string) (*url.URL, error) {
+	var best *proxyState
+	var bestWeight uint64
+	for _, state := range p.proxies {
+		if state.evicted {
+			continue
+		}
+		h := fnv.New64a()
+		h.Write([]byte(host))
+		h.Write([]byte{0})
+		h.Write([]byte(state.addr))
+		if weight := h.Sum64(); best == nil || weight > bestWeight {
+			best, bestWeight = state, weight
+		}
+	}
+	if best == nil {
+		return nil, ErrNoProxyAvailable
+	}
+	return best.url, nil
+}
+
+// Report registra o resultado de uma busca feita por proxy. Uma busca sem erro
+// zera as falhas do proxy; falhas de conexão com o próprio proxy o afastam
+// após MaxFailures seguidas. Os demais erros não mudam o estado.
+func (p *ProxyPool) Report(proxy *url.URL, err error) {
+	if err != nil && !IsProxyError(err) {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, state := range p.proxies {
+		if state.url != proxy {
+			continue
+		}
+		if err == nil {
+			state.failures = 0
+			return
+		}
+		metrics.ProxyFailures.WithLabelValues(state.addr).Inc()
+		state.failures++
+		if state.failures >= p.opts.MaxFailures && !state.evicted {
+			p.evictLocked(state, err)
+		}
+		return
+	}
+}
+
+func (p *ProxyPool) evictLocked(state *proxyState, err error) {
+	state.evicted = true
+	metrics.ProxyUp.WithLabelValues(state.addr).Set(0)
+	p.opts.Logger.Warn("proxy evicted", "proxy", state.url.Redacted(), "failures", state.failures, "error", err)
+}
+
+// Available retorna quantos proxies estão em uso e o total do pool.
+func (p *ProxyPool) Available() (available, total int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, state := range p.proxies {
+		if !state.evicted {
+			available++
+		}
+	}
+	return available, len(p.proxies)
+}
+
+// Run verifica os proxies a cada interval, até ctx terminar, tentando abrir
+// uma conexão TCP com cada um. Os que respondem voltam ao pool; os que não
+// respondem são afastados.
+func (p *ProxyPool) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.CheckHealth(ctx)
+		}
+	}
+}
+
+// CheckHealth verifica todos os proxies uma vez.
+func (p *ProxyPool) CheckHealth(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, state := range p.proxies {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, p.opts.HealthTimeout)
+			defer cancel()
+			conn, err := p.dial(checkCtx, "tcp", state.addr)
+			if err == nil {
+				conn.Close()
+			}
+			p.setHealth(state, err)
+		}()
+	}
+	wg.Wait()
+}
+
+func (p *ProxyPool) setHealth(state *proxyState, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch {
+	case err == nil && state.evicted:
+		state.evicted, state.failures = false, 0
+		metrics.ProxyUp.WithLabelValues(state.addr).Set(1)
+		p.opts.Logger.Info("proxy restored", "proxy", state.url.Redacted())
+	case err != nil && !state.evicted:
+		state.failures = p.opts.MaxFailures
+		p.evictLocked(state, err)
+	}
+}
+
+// trusts informa se address é o endereço de um proxy do pool. Um pool nil não
+// confia em nenhum endereço.
+func (p *ProxyPool) trusts(address string) bool {
+	if p == nil {
+		return false
+	}
+	for _, state := range p.proxies {
+		if state.addr == address {
+			return true
+		}
+	}
+	return false
+}
+
+// IsProxyError informa se err é uma falha de conexão com o proxy, e não com o
+// destino.
+func IsProxyError(err error) bool {
+	if errors.Is(err, ErrNoProxyAvailable) || errors.Is(err, ErrProxyNotAllowed) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && (opErr.Op == "proxyconnect" || strings.HasPrefix(opErr.Op, "socks"))
+}
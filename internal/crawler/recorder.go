@@ -0,0 +1,91 @@
+package crawler
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// maxRecordedBody limita quantos bytes do corpo de cada resposta são entregues
+// ao ExchangeRecorder. O restante é marcado como truncado.
+const maxRecordedBody = 32 << 20
+
+// Exchange é um par requisição/resposta feito pelo HTTPClient, incluindo os
+// redirecionamentos intermediários.
+type Exchange struct {
+	Request  *http.Request
+	Response *http.Response
+	// Body é o corpo da resposta como entregue pelo transporte.
+	Body      []byte
+	Truncated bool
+	FetchedAt time.Time
+}
+
+// ExchangeRecorder recebe cada troca concluída pelo HTTPClient, como o
+// arquivador WARC.
+type ExchangeRecorder interface {
+	RecordExchange(ex Exchange) error
+}
+
+// recordingTransport entrega ao recorder cada resposta assim que seu corpo é fechado.
+type recordingTransport struct {
+	next     http.RoundTripper
+	recorder ExchangeRecorder
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	fetchedAt := time.Now().UTC()
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	resp.Body = &recordingBody{
+		ReadCloser: resp.Body,
+		exchange:   Exchange{Request: req, Response: resp, FetchedAt: fetchedAt},
+		recorder:   t.recorder,
+	}
+	return resp, nil
+}
+
+// recordingBody copia o corpo lido e, ao ser fechado, lê o restante e grava a troca.
+type recordingBody struct {
+	io.ReadCloser
+	buf      bytes.Buffer
+	exchange Exchange
+	recorder ExchangeRecorder
+	closed   bool
+}
+
+func (b *recordingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.capture(p[:n])
+	return n, err
+}
+
+func (b *recordingBody) capture(p []byte) {
+	room := maxRecordedBody - b.buf.Len()
+	if len(p) > room {
+		b.exchange.Truncated = true
+		p = p[:room]
+	}
+	b.buf.Write(p)
+}
+
+func (b *recordingBody) Close() error {
+	if b.closed {
+		return nil
+	}
+	b.closed = true
+
+	remaining := io.LimitReader(b.ReadCloser, int64(maxRecordedBody-b.buf.Len())+1)
+	chunk, _ := io.ReadAll(remaining)
+	b.capture(chunk)
+
+	b.exchange.Body = b.buf.Bytes()
+	if err := b.recorder.RecordExchange(b.exchange); err != nil {
+		slog.Warn("failed to record exchange", "url", b.exchange.Request.URL.String(), "error", err)
+	}
+	return b.ReadCloser.Close()
+}
@@ -0,0 +1,71 @@
+package crawler
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/nettojulio/ufape-crawler-golang/internal/metrics"
+)
+
+// Crawler é implementado por quem executa o crawling de uma única página.
+type Crawler interface {
+	Crawl(ctx context.Context, payload Payload, originalURL, modifiedURL *url.URL) (*CrawlResult, error)
+}
+
+// CrawlWithRetry executa o crawling até payload.MaxAttempts vezes, aguardando
+// delay entre as tentativas, enquanto o resultado não for 200 ou 404. Destinos
+// bloqueados e falhas de sessão não são tentados de novo.
+func CrawlWithRetry(ctx context.Context, c Crawler, payload Payload, originalURL, modifiedURL *url.URL, delay time.Duration) (*CrawlResult, error) {
+	maxAttempts := 1
+	if payload.MaxAttempts != nil {
+		maxAttempts = *payload.MaxAttempts
+	}
+
+	var result *CrawlResult
+	var err error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		result, err = crawlAttempt(ctx, c, payload, originalURL, modifiedURL, attempt)
+
+		if err != nil || (result.StatusCode != http.StatusOK && result.StatusCode != http.StatusNotFound && !noRetry(result.ErrorClass)) {
+			if attempt < maxAttempts {
+				select {
+				case <-time.After(delay):
+					metrics.RetryAttempts.Inc()
+					continue
+				case <-ctx.Done():
+					return result, err
+				}
+			}
+		}
+		break
+	}
+	return result, err
+}
+
+// crawlAttempt executa uma tentativa de crawling dentro de um span próprio.
+func crawlAttempt(ctx context.Context, c Crawler, payload Payload, originalURL, modifiedURL *url.URL, attempt int) (*CrawlResult, error) {
+	ctx, span := tracer().Start(ctx, "crawler.attempt", trace.WithAttributes(attribute.Int("crawler.attempt", attempt)))
+	defer span.End()
+
+	result, err := c.Crawl(ctx, payload, originalURL, modifiedURL)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	} else {
+		span.SetAttributes(semconv.HTTPResponseStatusCode(result.StatusCode))
+	}
+	return result, err
+}
+
+// noRetry informa se uma falha da classe class se repetiria em uma nova
+// tentativa.
+func noRetry(class ErrorClass) bool {
+	return class == ErrorClassBlocked || class == ErrorClassSession
+}
@@ -0,0 +1,123 @@
+package crawler
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+type sequenceCrawler struct {
+	statuses []int
+	calls    int
+}
+
+func (s *sequenceCrawler) Crawl(ctx context.Context, payload Payload, originalURL, modifiedURL *url.URL) (*CrawlResult, error) {
+	status := s.statuses[min(s.calls, len(s.statuses)-1)]
+	s.calls++
+	return &CrawlResult{StatusCode: status, FinalURL: modifiedURL}, nil
+}
+
+func TestCrawlWithRetry(t *testing.T) {
+	u, _ := url.Parse("http://example.com")
+
+	testCases := []struct {
+		name          string
+		statuses      []int
+		maxAttempts   int
+		expectedCalls int
+		expected      int
+	}{
+		{name: "success on first attempt", statuses: []int{http.StatusOK}, maxAttempts: 3, expectedCalls: 1, expected: http.StatusOK},
+		{name: "not found is not retried", statuses: []int{http.StatusNotFound}, maxAttempts: 3, expectedCalls: 1, expected: http.StatusNotFound},
+		{name: "retries until success", statuses: []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusOK}, maxAttempts: 5, expectedCalls: 3, expected: http.StatusOK},
+		{name: "stops at max attempts", statuses: []int{http.StatusBadGateway}, maxAttempts: 2, expectedCalls: 2, expected: http.StatusBadGateway},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &sequenceCrawler{statuses: tc.statuses}
+			payload := Payload{MaxAttempts: &tc.maxAttempts}
+
+			result, err := CrawlWithRetry(context.Background(), c, payload, u, u, 0)
+
+			if err != nil {
+				t.Fatalf("CrawlWithRetry() returned an unexpected error: %v", err)
+			}
+			if c.calls != tc.expectedCalls {
+				t.Errorf("expected %d attempts, got %d", tc.expectedCalls, c.calls)
+			}
+			if result.StatusCode != tc.expected {
+				t.Errorf("expected status code %d, got %d", tc.expected, result.StatusCode)
+			}
+		})
+	}
+}
+
+func TestPrepareURLAndDefaults(t *testing.T) {
+	originalURL, _ := url.Parse("http://www.Example.com/Path/#frag")
+	payload := Payload{AllowedDomains: &[]string{"www.example.com"}, CanRetry: boolPtr(true)}
+
+	modifiedURL := PrepareURLAndDefaults(&payload, originalURL)
+
+	if *payload.Timeout != 60 || !*payload.RemoveFragment || !*payload.CollectSubdomains || *payload.LowerCaseURLs {
+		t.Errorf("unexpected defaults: %+v", payload)
+	}
+	if *payload.MaxAttempts != 10 {
+		t.Errorf("expected 10 attempts when retry is enabled, got %d", *payload.MaxAttempts)
+	}
+	if (*payload.AllowedDomains)[0] != "example.com" {
+		t.Errorf("expected www. prefix to be trimmed, got %v", *payload.AllowedDomains)
+	}
+	if modifiedURL.String() != "http://Example.com/Path" {
+		t.Errorf("unexpected normalized url: %s", modifiedURL)
+	}
+}
+
+func TestPrepareURLAndDefaultsUsesConfiguredDefaults(t *testing.T) {
+	d := DefaultDefaults()
+	d.Timeout = 15
+	d.RetryMaxAttempts = 3
+	d.IncludeBody = BodyText
+	SetDefaults(d)
+	t.Cleanup(func() { SetDefaults(DefaultDefaults()) })
+
+	originalURL, _ := url.Parse("https://example.com/")
+	timeout := 5
+	payload := Payload{CanRetry: boolPtr(true), Timeout: &timeout}
+	PrepareURLAndDefaults(&payload, originalURL)
+
+	if *payload.Timeout != 5 {
+		t.Errorf("expected the explicit timeout to be kept, got %d", *payload.Timeout)
+	}
+	if *payload.MaxAttempts != 3 {
+		t.Errorf("expected the configured retry attempts, got %d", *payload.MaxAttempts)
+	}
+	if *payload.IncludeBody != BodyText {
+		t.Errorf("expected the configured body mode, got %q", *payload.IncludeBody)
+	}
+}
+
+func TestMergePayloadRequestOptions(t *testing.T) {
+	defaults := Payload{RequestOptions: RequestOptions{
+		Headers:        map[string]string{"X-Section": "news", "X-Team": "crawler"},
+		AcceptLanguage: "pt-BR",
+		BasicAuth:      &BasicAuth{Username: "user"},
+	}}
+	item := Payload{Url: "https://example.com", RequestOptions: RequestOptions{
+		Headers:        map[string]string{"X-Section": "sports"},
+		AcceptLanguage: "en-US",
+	}}
+
+	merged := MergePayload(item, defaults)
+
+	if merged.Headers["X-Section"] != "sports" || merged.Headers["X-Team"] != "crawler" {
+		t.Errorf("expected headers to be combined with the item's taking precedence, got %v", merged.Headers)
+	}
+	if merged.AcceptLanguage != "en-US" || merged.BasicAuth == nil {
+		t.Errorf("expected empty options to be filled from the defaults, got %+v", merged.RequestOptions)
+	}
+	if defaults.Headers["X-Section"] != "news" {
+		t.Error("expected the defaults not to be modified")
+	}
+}
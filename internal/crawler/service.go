@@ -2,32 +2,121 @@ package crawler
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strings"
 	"time"
 
-	"golang.org/x/net/html"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/nettojulio/ufape-crawler-golang/internal/logging"
+	"github.com/nettojulio/ufape-crawler-golang/internal/metrics"
 )
 
+// MaxBodySize é o maior corpo de resposta lido pelo Service. Corpos maiores
+// são cortados e o resultado é marcado como truncado.
+const MaxBodySize = 32 << 20
+
+// HTTPGetter busca uma URL aplicando as opções da requisição.
 type HTTPGetter interface {
-	Get(ctx context.Context, url string) (*http.Response, error)
+	Get(ctx context.Context, url string, opts RequestOptions) (*http.Response, error)
 }
 
 type Service struct {
 	httpClient HTTPGetter
+	logger     *slog.Logger
+	parsers    *ParserRegistry
+}
+
+// ServiceOption configura opcionalmente o Service.
+type ServiceOption func(*Service)
+
+// WithServiceLogger define o logger usado quando o contexto do crawling não
+// carrega um logger próprio (veja logging.WithContext).
+func WithServiceLogger(logger *slog.Logger) ServiceOption {
+	return func(s *Service) {
+		s.logger = logger
+	}
 }
 
-func NewService(httpClient HTTPGetter) *Service {
-	return &Service{
+// WithParsers define os parsers usados para extrair o título e os links de
+// cada tipo de conteúdo. O padrão é DefaultParsers.
+func WithParsers(parsers *ParserRegistry) ServiceOption {
+	return func(s *Service) {
+		s.parsers = parsers
+	}
+}
+
+func NewService(httpClient HTTPGetter, opts ...ServiceOption) *Service {
+	s := &Service{
 		httpClient: httpClient,
+		logger:     logging.Discard(),
+		parsers:    DefaultParsers(),
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
 func (s *Service) Crawl(ctx context.Context, payload Payload, originalURL, modifiedURL *url.URL) (*CrawlResult, error) {
+	metrics.CrawlsInFlight.Inc()
+	defer metrics.CrawlsInFlight.Dec()
+
+	ctx, span := tracer().Start(ctx, "crawler.Service.Crawl", trace.WithAttributes(semconv.URLFull(modifiedURL.String())))
+	defer span.End()
+
+	logger := logging.FromContext(ctx, s.logger)
+	result, err := s.crawl(ctx, payload, modifiedURL)
+	if err != nil {
+		metrics.Crawls.WithLabelValues("error").Inc()
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		logger.WarnContext(ctx, "crawl failed", "url", modifiedURL.String(), "error", err)
+		return result, err
+	}
+
+	metrics.Crawls.WithLabelValues(metrics.StatusClass(result.StatusCode)).Inc()
+	span.SetAttributes(
+		semconv.HTTPResponseStatusCode(result.StatusCode),
+		attribute.Int("crawler.body.size", len(result.Body)),
+		attribute.Int("crawler.links.available", len(result.Links.Available)),
+		attribute.Int("crawler.links.unavailable", len(result.Links.Unavailable)),
+	)
+	if result.ErrorClass != "" {
+		span.SetAttributes(attribute.String("crawler.error_class", string(result.ErrorClass)))
+		span.SetStatus(codes.Error, result.Title)
+		logger.WarnContext(ctx, "page fetch failed",
+			"url", modifiedURL.String(), "error_class", result.ErrorClass, "error", result.Title)
+		return result, nil
+	}
+	logger.DebugContext(ctx, "page crawled",
+		"url", modifiedURL.String(),
+		"status", result.StatusCode,
+		"elapsed", result.ElapsedTime,
+		"bytes", len(result.Body),
+		"links", len(result.Links.Available),
+	)
+	return result, nil
+}
+
+func (s *Service) crawl(ctx context.Context, payload Payload, modifiedURL *url.URL) (*CrawlResult, error) {
+	if payload.Timeout != nil && *payload.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(*payload.Timeout)*time.Second)
+		defer cancel()
+	}
+
 	start := time.Now()
-	resp, err := s.httpClient.Get(ctx, modifiedURL.String())
+	resp, err := s.httpClient.Get(ctx, modifiedURL.String(), payload.RequestOptions)
 	elapsed := time.Since(start)
 
 	if err != nil {
@@ -35,6 +124,7 @@ func (s *Service) Crawl(ctx context.Context, payload Payload, originalURL, modif
 			StatusCode:  http.StatusServiceUnavailable,
 			ElapsedTime: elapsed,
 			Title:       err.Error(),
+			ErrorClass:  ClassifyError(err),
 			FinalURL:    modifiedURL,
 			Links: LinksResponse{
 				Available:   []string{},
@@ -43,22 +133,55 @@ func (s *Service) Crawl(ctx context.Context, payload Payload, originalURL, modif
 		}, nil
 	}
 
+	body, truncated, readErr := readBody(resp.Body)
+	resp.Body.Close()
+	metrics.BytesDownloaded.Add(float64(len(body)))
+
 	result := &CrawlResult{
-		StatusCode:  resp.StatusCode,
-		ContentType: resp.Header.Get("Content-Type"),
-		ElapsedTime: elapsed,
-		Body:        resp.Body,
-		FinalURL:    resp.Request.URL,
+		StatusCode:    resp.StatusCode,
+		ContentType:   resp.Header.Get("Content-Type"),
+		ElapsedTime:   elapsed,
+		Body:          body,
+		BodyTruncated: truncated,
+		FinalURL:      resp.Request.URL,
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		resp.Body.Close()
+		return result, nil
+	}
+	if readErr != nil {
+		result.StatusCode = http.StatusServiceUnavailable
+		result.Title = readErr.Error()
+		result.ErrorClass = ClassifyError(readErr)
+		result.Links.Available = []string{}
+		result.Links.Unavailable = []string{}
 		return result, nil
 	}
 
-	doc, err := html.Parse(resp.Body)
-	resp.Body.Close()
+	sum := sha256.Sum256(body)
+	result.ContentHash = hex.EncodeToString(sum[:])
+
+	// Os documentos de texto são interpretados em UTF-8; o corpo do resultado
+	// fica como recebido.
+	parser, mediaType := s.parsers.Lookup(result.ContentType, body)
+	document := body
+	if isTextMediaType(mediaType) {
+		_, result.Charset = DetectCharset(body, result.ContentType)
+		document = ToUTF8(body, result.Charset)
+	}
+
+	var parsed Document
+	if parser != nil {
+		_, parseSpan := tracer().Start(ctx, "crawler.parse", trace.WithAttributes(attribute.String("crawler.parser", mediaType)))
+		parsed, err = parser.Parse(document)
+		if err != nil {
+			parseSpan.RecordError(err)
+			parseSpan.SetStatus(codes.Error, err.Error())
+		}
+		parseSpan.End()
+	}
 	if err != nil {
+		metrics.ParseFailures.Inc()
 		if strings.Contains(resp.Header.Get("Content-Type"), "text/html") {
 			return nil, fmt.Errorf("failed to parse html: %w", err)
 		}
@@ -69,8 +192,14 @@ func (s *Service) Crawl(ctx context.Context, payload Payload, originalURL, modif
 
 	}
 
-	result.Title = GetTitle(doc)
-	result.Links = ExtractLinks(doc, ParseOptions{
+	result.Title = parsed.Title
+	if result.Title == "" {
+		result.Title = emptyTitle
+	}
+
+	_, extractSpan := tracer().Start(ctx, "crawler.extract_links")
+	defer extractSpan.End()
+	result.Links = CollectLinks(parsed.Links, ParseOptions{
 		BaseURL:           result.FinalURL,
 		AllowedDomains:    *payload.AllowedDomains,
 		CollectSubdomains: *payload.CollectSubdomains,
@@ -80,3 +209,12 @@ func (s *Service) Crawl(ctx context.Context, payload Payload, originalURL, modif
 
 	return result, nil
 }
+
+// readBody lê até MaxBodySize bytes de r, informando se havia mais conteúdo.
+func readBody(r io.Reader) ([]byte, bool, error) {
+	body, err := io.ReadAll(io.LimitReader(r, MaxBodySize+1))
+	if len(body) > MaxBodySize {
+		return body[:MaxBodySize], true, err
+	}
+	return body, false, err
+}
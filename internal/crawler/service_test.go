@@ -3,12 +3,16 @@ package crawler
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 )
 
 type errorReader struct{}
@@ -22,7 +26,7 @@ type mockHTTPClient struct {
 	Err      error
 }
 
-func (m *mockHTTPClient) Get(ctx context.Context, url string) (*http.Response, error) {
+func (m *mockHTTPClient) Get(ctx context.Context, url string, opts RequestOptions) (*http.Response, error) {
 	if m.Response != nil && m.Response.Request == nil {
 		req, _ := http.NewRequestWithContext(ctx, "GET", url, nil)
 		m.Response.Request = req
@@ -30,6 +34,59 @@ func (m *mockHTTPClient) Get(ctx context.Context, url string) (*http.Response, e
 	return m.Response, m.Err
 }
 
+// blockingHTTPClient só responde quando o contexto da busca termina.
+type blockingHTTPClient struct{}
+
+func (blockingHTTPClient) Get(ctx context.Context, url string, opts RequestOptions) (*http.Response, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestServiceHonorsPayloadTimeout(t *testing.T) {
+	target, _ := url.Parse("http://example.com")
+	timeout := 1
+	payload := Payload{Timeout: &timeout}
+
+	start := time.Now()
+	result, err := NewService(blockingHTTPClient{}).Crawl(context.Background(), payload, target, target)
+
+	if err != nil {
+		t.Fatalf("Crawl() returned an unexpected error: %v", err)
+	}
+	if result.ErrorClass != ErrorClassTimeout {
+		t.Errorf("expected a timeout, got %q", result.ErrorClass)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("expected the crawl to stop after about 1s, took %s", elapsed)
+	}
+}
+
+func TestServiceCapsPayloadTimeoutAtClientTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(5 * time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	target, _ := url.Parse(server.URL)
+	timeout := 3600
+	payload := Payload{Timeout: &timeout}
+
+	start := time.Now()
+	result, err := NewService(NewHTTPClient(100*time.Millisecond)).Crawl(context.Background(), payload, target, target)
+
+	if err != nil {
+		t.Fatalf("Crawl() returned an unexpected error: %v", err)
+	}
+	if result.ErrorClass != ErrorClassTimeout {
+		t.Errorf("expected a timeout, got %q", result.ErrorClass)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("expected CRAWLER_TIMEOUT to cap the payload timeout, took %s", elapsed)
+	}
+}
+
 func TestService_Crawl(t *testing.T) {
 	ctx := context.Background()
 	originalURL, _ := url.Parse("http://example.com")
@@ -67,6 +124,95 @@ func TestService_Crawl(t *testing.T) {
 		if len(result.Links.Available) != 1 || result.Links.Available[0] != "http://example.com/page1" {
 			t.Errorf("unexpected available links: got %v", result.Links.Available)
 		}
+		expectedHash := sha256.Sum256([]byte(htmlBody))
+		if result.ContentHash != hex.EncodeToString(expectedHash[:]) {
+			t.Errorf("expected content hash of the full body, got %q", result.ContentHash)
+		}
+		if string(result.Body) != htmlBody || result.BodyTruncated {
+			t.Errorf("expected the full body to be kept, got %q", result.Body)
+		}
+	})
+
+	t.Run("windows-1252 page is converted to utf-8 before parsing", func(t *testing.T) {
+		htmlBody := "<html><head><meta charset=\"iso-8859-1\"><title>Gradua\xe7\xe3o e P\xf3s</title></head></html>"
+		mockResp := &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"text/html"}},
+			Body:       io.NopCloser(strings.NewReader(htmlBody)),
+		}
+		service := NewService(&mockHTTPClient{Response: mockResp})
+
+		result, err := service.Crawl(ctx, defaultPayload, originalURL, modifiedURL)
+
+		if err != nil {
+			t.Fatalf("Crawl() returned an unexpected error: %v", err)
+		}
+		if result.Title != "Graduação e Pós" || result.Charset != "windows-1252" {
+			t.Errorf("expected the decoded title and charset, got %q (%s)", result.Title, result.Charset)
+		}
+		if string(result.Body) != htmlBody {
+			t.Errorf("expected the body to be kept as received, got %q", result.Body)
+		}
+	})
+
+	t.Run("rss feed items become links", func(t *testing.T) {
+		feed := `<?xml version="1.0"?><rss version="2.0"><channel><title>Notícias</title>
+			<item><title>Edital</title><link>http://example.com/edital</link></item>
+			<item><title>Fora</title><link>http://outro.com/post</link></item>
+		</channel></rss>`
+		mockResp := &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/rss+xml; charset=utf-8"}},
+			Body:       io.NopCloser(strings.NewReader(feed)),
+		}
+		service := NewService(&mockHTTPClient{Response: mockResp})
+
+		result, err := service.Crawl(ctx, defaultPayload, originalURL, modifiedURL)
+
+		if err != nil {
+			t.Fatalf("Crawl() returned an unexpected error: %v", err)
+		}
+		if result.Title != "Notícias" {
+			t.Errorf("expected the channel title, got %q", result.Title)
+		}
+		if len(result.Links.Available) != 1 || result.Links.Available[0] != "http://example.com/edital" {
+			t.Errorf("expected the item link to be available, got %v", result.Links.Available)
+		}
+		if len(result.Links.Unavailable) != 1 || result.Links.Unavailable[0] != "http://outro.com/post" {
+			t.Errorf("expected the external item link to be unavailable, got %v", result.Links.Unavailable)
+		}
+	})
+
+	t.Run("unsupported content type has no links", func(t *testing.T) {
+		mockResp := &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"image/png"}},
+			Body:       io.NopCloser(strings.NewReader(`<a href="/page1">not html</a>`)),
+		}
+		service := NewService(&mockHTTPClient{Response: mockResp})
+
+		result, err := service.Crawl(ctx, defaultPayload, originalURL, modifiedURL)
+
+		if err != nil {
+			t.Fatalf("Crawl() returned an unexpected error: %v", err)
+		}
+		if result.Title != "[Empty title]" || len(result.Links.Available) != 0 || result.Charset != "" {
+			t.Errorf("expected no title, links or charset, got %q %v %q", result.Title, result.Links.Available, result.Charset)
+		}
+	})
+
+	t.Run("body of a non-200 response is kept", func(t *testing.T) {
+		mockResp := &http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       io.NopCloser(strings.NewReader("not found")),
+		}
+		service := NewService(&mockHTTPClient{Response: mockResp})
+
+		result, _ := service.Crawl(ctx, defaultPayload, originalURL, modifiedURL)
+
+		if string(result.Body) != "not found" {
+			t.Errorf("expected the error page body, got %q", result.Body)
+		}
 	})
 
 	t.Run("server response is not 200 OK", func(t *testing.T) {
@@ -0,0 +1,451 @@
+package crawler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/publicsuffix"
+
+	"github.com/nettojulio/ufape-crawler-golang/internal/logging"
+)
+
+var (
+	// ErrUnknownSession é retornado quando uma busca pede uma sessão que não
+	// foi configurada.
+	ErrUnknownSession = errors.New("unknown session")
+	// ErrLoginFailed é retornado quando o login de uma sessão não é
+	// confirmado.
+	ErrLoginFailed = errors.New("login failed")
+)
+
+// loginRetryDelay é o intervalo mínimo entre duas tentativas de login de uma
+// sessão. Nesse intervalo, as buscas recebem o erro da última tentativa.
+const loginRetryDelay = 30 * time.Second
+
+// SessionConfig define uma sessão nomeada. Sem Login, a sessão apenas guarda
+// os cookies recebidos pelas buscas que a usam.
+type SessionConfig struct {
+	Name  string     `json:"name"`
+	Login *LoginFlow `json:"login,omitempty"`
+}
+
+// LoginFlow descreve um login por formulário HTML: a página URL é buscada, o
+// formulário é preenchido com seus campos, incluindo os ocultos, e com
+// Fields, e então enviado. O login é confirmado quando a URL final contém
+// SuccessURL e a página final contém SuccessText; ao menos um deles é
+// obrigatório.
+type LoginFlow struct {
+	URL string `json:"url"`
+	// Form é o id ou o name do formulário. Vazio usa o primeiro formulário com
+	// um campo de senha ou, sem ele, o primeiro da página.
+	Form        string            `json:"form,omitempty"`
+	Fields      map[string]string `json:"fields"`
+	SuccessURL  string            `json:"successUrl,omitempty"`
+	SuccessText string            `json:"successText,omitempty"`
+}
+
+// SessionStore guarda as sessões nomeadas usadas pelo HTTPClient.
+type SessionStore struct {
+	sessions map[string]*Session
+}
+
+// Session é um cookie jar compartilhado pelas buscas que usam a sessão. O
+// login é feito na primeira busca e os cookies recebidos valem para as
+// seguintes, até uma busca ser levada de volta à página de login, quando o
+// login é refeito.
+type Session struct {
+	name  string
+	login *LoginFlow
+	jar   http.CookieJar
+
+	mu         sync.Mutex
+	loggedIn   bool
+	generation int
+	lastErr    error
+	failedAt   time.Time
+}
+
+// NewCookieJar cria um cookie jar que respeita a lista de sufixos públicos,
+// para que um site não defina cookies para um domínio inteiro como .edu.br.
+func NewCookieJar() http.CookieJar {
+	jar, _ := cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
+	return jar
+}
+
+// NewSessionStore valida configs e cria um SessionStore.
+func NewSessionStore(configs []SessionConfig) (*SessionStore, error) {
+	s := &SessionStore{sessions: make(map[string]*Session, len(configs))}
+	for i, cfg := range configs {
+		if cfg.Name == "" {
+			return nil, fmt.Errorf("session %d: missing name", i)
+		}
+		if _, ok := s.sessions[cfg.Name]; ok {
+			return nil, fmt.Errorf("session %q: duplicate name", cfg.Name)
+		}
+		if flow := cfg.Login; flow != nil {
+			u, err := url.Parse(flow.URL)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return nil, fmt.Errorf("session %q: login url must be an http or https URL", cfg.Name)
+			}
+			if flow.SuccessURL == "" && flow.SuccessText == "" {
+				return nil, fmt.Errorf("session %q: set successUrl or successText to verify the login", cfg.Name)
+			}
+		}
+		s.sessions[cfg.Name] = &Session{name: cfg.Name, login: cfg.Login, jar: NewCookieJar()}
+	}
+	return s, nil
+}
+
+// LoadSessions lê as sessões de um arquivo JSON com uma lista de
+// SessionConfig. Os valores dos campos do login podem referenciar variáveis
+// de ambiente, como ${INTRANET_PASSWORD}, para não guardar senhas no arquivo.
+func LoadSessions(path string) (*SessionStore, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sessions: %w", err)
+	}
+	var configs []SessionConfig
+	if err := json.Unmarshal(data, &configs); err != nil {
+		return nil, fmt.Errorf("failed to decode sessions: %w", err)
+	}
+	for _, cfg := range configs {
+		if cfg.Login == nil {
+			continue
+		}
+		for name, value := range cfg.Login.Fields {
+			cfg.Login.Fields[name] = os.ExpandEnv(value)
+		}
+	}
+	return NewSessionStore(configs)
+}
+
+// Get retorna a sessão name. Um SessionStore nil não tem sessões.
+func (s *SessionStore) Get(name string) (*Session, error) {
+	if s != nil {
+		if session, ok := s.sessions[name]; ok {
+			return session, nil
+		}
+	}
+	return nil, fmt.Errorf("%w %q", ErrUnknownSession, name)
+}
+
+// Names retorna os nomes das sessões em ordem alfabética.
+func (s *SessionStore) Names() []string {
+	if s == nil {
+		return nil
+	}
+	names := make([]string, 0, len(s.sessions))
+	for name := range s.sessions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Name retorna o nome da sessão.
+func (s *Session) Name() string {
+	return s.name
+}
+
+// Jar retorna o cookie jar da sessão.
+func (s *Session) Jar() http.CookieJar {
+	return s.jar
+}
+
+// ensureLogin faz o login da sessão por c, se ainda não foi feito, e
+// retorna a geração do login em vigor. As buscas concorrentes aguardam o
+// login em andamento.
+func (s *Session) ensureLogin(ctx context.Context, c *HTTPClient) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.login == nil || s.loggedIn {
+		return s.generation, nil
+	}
+	if s.lastErr != nil && time.Since(s.failedAt) < loginRetryDelay {
+		return s.generation, s.lastErr
+	}
+	logger := logging.FromContext(ctx, c.logger)
+	if err := c.Login(ctx, s.jar, *s.login); err != nil {
+		// Um cancelamento não diz nada sobre as credenciais; a próxima busca
+		// tenta de novo.
+		if ctx.Err() == nil {
+			s.lastErr, s.failedAt = err, time.Now()
+		}
+		logger.WarnContext(ctx, "session login failed", "session", s.name, "error", err)
+		return s.generation, err
+	}
+	s.loggedIn, s.lastErr = true, nil
+	s.generation++
+	logger.InfoContext(ctx, "session logged in", "session", s.name)
+	return s.generation, nil
+}
+
+// expired informa se a busca de target, que terminou em final, foi levada à
+// página de login, sinal de que o servidor encerrou a sessão. Buscar a
+// própria página de login não conta.
+func (s *Session) expired(target string, final *url.URL) bool {
+	if s.login == nil || final == nil {
+		return false
+	}
+	requested, err := url.Parse(target)
+	if err != nil {
+		return false
+	}
+	return isLoginPage(final, s.login.URL) && !isLoginPage(requested, s.login.URL)
+}
+
+// expire descarta o login da geração generation, para que a próxima busca o
+// refaça. Um login mais novo, feito por uma busca concorrente, é mantido.
+func (s *Session) expire(generation int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.generation == generation {
+		s.loggedIn = false
+	}
+}
+
+// isLoginPage compara u com a URL de login, ignorando a query e o fragmento.
+func isLoginPage(u *url.URL, loginURL string) bool {
+	login, err := url.Parse(loginURL)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Scheme, login.Scheme) && strings.EqualFold(u.Host, login.Host) &&
+		strings.TrimSuffix(u.Path, "/") == strings.TrimSuffix(login.Path, "/")
+}
+
+// Login executa flow guardando os cookies em jar. O erro retornado envolve
+// ErrLoginFailed.
+func (c *HTTPClient) Login(ctx context.Context, jar http.CookieJar, flow LoginFlow) error {
+	page, pageURL, err := c.fetchLoginPage(ctx, jar, http.MethodGet, flow.URL, nil)
+	if err != nil {
+		return fmt.Errorf("%w: failed to fetch the login page: %w", ErrLoginFailed, err)
+	}
+	doc, err := html.Parse(bytes.NewReader(page))
+	if err != nil {
+		return fmt.Errorf("%w: failed to parse the login page: %w", ErrLoginFailed, err)
+	}
+	form := findForm(doc, flow.Form)
+	if form == nil {
+		return fmt.Errorf("%w: login form not found at %s", ErrLoginFailed, pageURL)
+	}
+
+	action, method, values, err := formSubmission(form, pageURL)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrLoginFailed, err)
+	}
+	for name, value := range flow.Fields {
+		values.Set(name, value)
+	}
+	if method == http.MethodGet {
+		action.RawQuery = values.Encode()
+		values = nil
+	}
+
+	page, finalURL, err := c.fetchLoginPage(ctx, jar, method, action.String(), values)
+	if err != nil {
+		return fmt.Errorf("%w: failed to submit the login form: %w", ErrLoginFailed, err)
+	}
+	if flow.SuccessURL != "" && !strings.Contains(finalURL.String(), flow.SuccessURL) {
+		return fmt.Errorf("%w: expected the final url to contain %q, got %s", ErrLoginFailed, flow.SuccessURL, finalURL)
+	}
+	if flow.SuccessText != "" && !bytes.Contains(page, []byte(flow.SuccessText)) {
+		return fmt.Errorf("%w: expected the final page to contain %q", ErrLoginFailed, flow.SuccessText)
+	}
+	return nil
+}
+
+// fetchLoginPage busca uma página do login e retorna o corpo, em UTF-8, e a
+// URL final. Respostas fora da faixa 2xx são tratadas como erro.
+func (c *HTTPClient) fetchLoginPage(ctx context.Context, jar http.CookieJar, method, target string, form url.Values) ([]byte, *url.URL, error) {
+	resp, err := c.do(ctx, method, target, form, RequestOptions{}, jar)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, nil, fmt.Errorf("unexpected status %s from %s", resp.Status, resp.Request.URL)
+	}
+	body, _, err := readBody(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	_, name := DetectCharset(body, resp.Header.Get("Content-Type"))
+	return ToUTF8(body, name), resp.Request.URL, nil
+}
+
+// findForm procura o formulário cujo id ou name é selector. Com selector
+// vazio, prefere o primeiro formulário com um campo de senha.
+func findForm(doc *html.Node, selector string) *html.Node {
+	selector = strings.TrimPrefix(selector, "#")
+	var first, withPassword, match *html.Node
+	var walk func(n *html.Node, form *html.Node)
+	walk = func(n *html.Node, form *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "form":
+				form = n
+				if first == nil {
+					first = n
+				}
+				if match == nil && selector != "" && (attr(n, "id") == selector || attr(n, "name") == selector) {
+					match = n
+				}
+			case "input":
+				if form != nil && withPassword == nil && strings.EqualFold(attr(n, "type"), "password") {
+					withPassword = form
+				}
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child, form)
+		}
+	}
+	walk(doc, nil)
+
+	switch {
+	case selector != "":
+		return match
+	case withPassword != nil:
+		return withPassword
+	default:
+		return first
+	}
+}
+
+// formSubmission retorna o destino, o método e os valores que um navegador
+// enviaria ao submeter form pelo primeiro botão de envio.
+func formSubmission(form *html.Node, base *url.URL) (*url.URL, string, url.Values, error) {
+	action, err := base.Parse(attr(form, "action"))
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("invalid form action: %w", err)
+	}
+	method := http.MethodGet
+	if strings.EqualFold(attr(form, "method"), "post") {
+		method = http.MethodPost
+	}
+
+	values := url.Values{}
+	submitted := false
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			name := attr(n, "name")
+			switch {
+			case name == "" || hasAttr(n, "disabled"):
+			case n.Data == "input":
+				switch kind := strings.ToLower(attr(n, "type")); kind {
+				case "submit", "image":
+					if !submitted {
+						values.Add(name, attr(n, "value"))
+						submitted = true
+					}
+				case "button", "reset", "file":
+				case "checkbox", "radio":
+					if hasAttr(n, "checked") {
+						value := attr(n, "value")
+						if value == "" {
+							value = "on"
+						}
+						values.Add(name, value)
+					}
+				default:
+					values.Add(name, attr(n, "value"))
+				}
+			case n.Data == "button":
+				if kind := strings.ToLower(attr(n, "type")); (kind == "" || kind == "submit") && !submitted {
+					values.Add(name, attr(n, "value"))
+					submitted = true
+				}
+			case n.Data == "textarea":
+				values.Add(name, textContent(n))
+			case n.Data == "select":
+				if value, ok := selectedOption(n); ok {
+					values.Add(name, value)
+				}
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(form)
+	return action, method, values, nil
+}
+
+// selectedOption retorna o valor da opção marcada de um select ou, sem
+// nenhuma marcada, o da primeira.
+func selectedOption(n *html.Node) (string, bool) {
+	var first, selected *html.Node
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "option" {
+			if first == nil {
+				first = n
+			}
+			if selected == nil && hasAttr(n, "selected") {
+				selected = n
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(n)
+	if selected == nil {
+		selected = first
+	}
+	if selected == nil {
+		return "", false
+	}
+	if hasAttr(selected, "value") {
+		return attr(selected, "value"), true
+	}
+	return strings.TrimSpace(textContent(selected)), true
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func hasAttr(n *html.Node, key string) bool {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func textContent(n *html.Node) string {
+	var b strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(n)
+	return b.String()
+}
@@ -0,0 +1,279 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+const loginPage = `<html><body>
+<form id="search" action="/search"><input name="q"></form>
+<form id="login" method="post" action="/login">
+  <input type="hidden" name="csrf" value="token-123">
+  <input type="text" name="username">
+  <input type="password" name="password">
+  <input type="checkbox" name="remember" checked>
+  <input type="submit" name="action" value="Entrar">
+</form>
+</body></html>`
+
+// newLoginSite cria um site que exige login para /private e /members e conta
+// os envios do formulário. POST /revoke encerra as sessões abertas, e
+// /members leva as buscas sem sessão válida de volta ao login.
+func newLoginSite(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var submits atomic.Int32
+	var valid atomic.Value
+	valid.Store("")
+	loggedIn := func(r *http.Request) bool {
+		cookie, err := r.Cookie("session")
+		return err == nil && cookie.Value != "" && cookie.Value == valid.Load()
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /login", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "csrf", Value: "token-123"})
+		fmt.Fprint(w, loginPage)
+	})
+	mux.HandleFunc("POST /login", func(w http.ResponseWriter, r *http.Request) {
+		submits.Add(1)
+		cookie, err := r.Cookie("csrf")
+		if err != nil || cookie.Value != r.FormValue("csrf") || r.FormValue("remember") != "on" || r.FormValue("action") != "Entrar" {
+			http.Error(w, "bad form", http.StatusBadRequest)
+			return
+		}
+		if r.FormValue("username") != "aluno" || r.FormValue("password") != "segredo" {
+			fmt.Fprint(w, loginPage)
+			return
+		}
+		token := fmt.Sprintf("ok-%d", submits.Load())
+		valid.Store(token)
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: token})
+		http.Redirect(w, r, "/painel", http.StatusFound)
+	})
+	mux.HandleFunc("GET /painel", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "<html><body><a href=/logout>Sair</a></body></html>")
+	})
+	mux.HandleFunc("GET /private", func(w http.ResponseWriter, r *http.Request) {
+		if !loggedIn(r) {
+			http.Error(w, "login required", http.StatusForbidden)
+			return
+		}
+		fmt.Fprint(w, "<html><title>Área restrita</title></html>")
+	})
+	mux.HandleFunc("GET /members", func(w http.ResponseWriter, r *http.Request) {
+		if !loggedIn(r) {
+			http.Redirect(w, r, "/login?next=/members", http.StatusFound)
+			return
+		}
+		fmt.Fprint(w, "<html><title>Membros</title></html>")
+	})
+	mux.HandleFunc("POST /revoke", func(w http.ResponseWriter, r *http.Request) {
+		valid.Store("")
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, &submits
+}
+
+func newTestSessions(t *testing.T, server *httptest.Server, password string) *SessionStore {
+	t.Helper()
+	store, err := NewSessionStore([]SessionConfig{{
+		Name: "intranet",
+		Login: &LoginFlow{
+			URL:         server.URL + "/login",
+			Fields:      map[string]string{"username": "aluno", "password": password},
+			SuccessURL:  "/painel",
+			SuccessText: "Sair",
+		},
+	}})
+	if err != nil {
+		t.Fatalf("NewSessionStore returned an error: %v", err)
+	}
+	return store
+}
+
+func TestHTTPClientSessions(t *testing.T) {
+	t.Run("should log in once and reuse the session cookies", func(t *testing.T) {
+		server, submits := newLoginSite(t)
+		client := NewHTTPClient(5*time.Second, WithSessions(newTestSessions(t, server, "segredo")))
+
+		for range 3 {
+			resp, err := client.Get(context.Background(), server.URL+"/private", RequestOptions{Session: "intranet"})
+			if err != nil {
+				t.Fatalf("Get() returned an unexpected error: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("expected 200 with the session, got %d", resp.StatusCode)
+			}
+		}
+		if submits.Load() != 1 {
+			t.Errorf("expected a single login, got %d", submits.Load())
+		}
+
+		resp, err := client.Get(context.Background(), server.URL+"/private", RequestOptions{})
+		if err != nil {
+			t.Fatalf("Get() returned an unexpected error: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("expected requests without the session not to send its cookies, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("should log in again when the server revokes the session", func(t *testing.T) {
+		server, submits := newLoginSite(t)
+		client := NewHTTPClient(5*time.Second, WithSessions(newTestSessions(t, server, "segredo")))
+		fetch := func() string {
+			t.Helper()
+			resp, err := client.Get(context.Background(), server.URL+"/members", RequestOptions{Session: "intranet"})
+			if err != nil {
+				t.Fatalf("Get() returned an unexpected error: %v", err)
+			}
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+			return string(body)
+		}
+
+		if body := fetch(); !strings.Contains(body, "Membros") {
+			t.Fatalf("expected the members page, got %q", body)
+		}
+		resp, err := http.Post(server.URL+"/revoke", "", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+
+		if body := fetch(); !strings.Contains(body, "Membros") {
+			t.Errorf("expected the members page after logging in again, got %q", body)
+		}
+		if submits.Load() != 2 {
+			t.Errorf("expected a second login after the revocation, got %d", submits.Load())
+		}
+	})
+
+	t.Run("should fail and wait before logging in again with wrong credentials", func(t *testing.T) {
+		server, submits := newLoginSite(t)
+		client := NewHTTPClient(5*time.Second, WithSessions(newTestSessions(t, server, "errada")))
+
+		for range 2 {
+			_, err := client.Get(context.Background(), server.URL+"/private", RequestOptions{Session: "intranet"})
+			if !errors.Is(err, ErrLoginFailed) || ClassifyError(err) != ErrorClassSession {
+				t.Fatalf("expected a session error, got %v", err)
+			}
+		}
+		if submits.Load() != 1 {
+			t.Errorf("expected the failed login not to be repeated right away, got %d attempts", submits.Load())
+		}
+	})
+
+	t.Run("should reject unknown sessions", func(t *testing.T) {
+		_, err := NewHTTPClient(5*time.Second).Get(context.Background(), "http://ufape.invalid/", RequestOptions{Session: "intranet"})
+		if !errors.Is(err, ErrUnknownSession) || ClassifyError(err) != ErrorClassSession {
+			t.Errorf("expected ErrUnknownSession, got %v", err)
+		}
+	})
+
+	t.Run("should keep cookies between requests with a cookie jar", func(t *testing.T) {
+		server, _ := newLoginSite(t)
+		client := NewHTTPClient(5*time.Second, WithCookieJar(NewCookieJar()))
+
+		err := client.Login(context.Background(), client.client.Jar, LoginFlow{
+			URL:         server.URL + "/login",
+			Form:        "#login",
+			Fields:      map[string]string{"username": "aluno", "password": "segredo"},
+			SuccessText: "Sair",
+		})
+		if err != nil {
+			t.Fatalf("Login() returned an unexpected error: %v", err)
+		}
+		resp, err := client.Get(context.Background(), server.URL+"/private", RequestOptions{})
+		if err != nil {
+			t.Fatalf("Get() returned an unexpected error: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("expected the jar to send the session cookie, got %d", resp.StatusCode)
+		}
+	})
+}
+
+func TestNewSessionStore(t *testing.T) {
+	invalid := map[string][]SessionConfig{
+		"missing name": {{}},
+		"duplicate":    {{Name: "a"}, {Name: "a"}},
+		"bad url":      {{Name: "a", Login: &LoginFlow{URL: "ftp://ufape.edu.br", SuccessText: "Sair"}}},
+		"no check":     {{Name: "a", Login: &LoginFlow{URL: "https://ufape.edu.br/login"}}},
+	}
+	for name, configs := range invalid {
+		if _, err := NewSessionStore(configs); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestLoadSessions(t *testing.T) {
+	t.Setenv("INTRANET_PASSWORD", "segredo")
+	path := filepath.Join(t.TempDir(), "sessions.json")
+	content := `[{"name": "intranet", "login": {"url": "https://ufape.edu.br/login",
+		"fields": {"username": "aluno", "password": "${INTRANET_PASSWORD}"}, "successText": "Sair"}},
+		{"name": "anonima"}]`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := LoadSessions(path)
+	if err != nil {
+		t.Fatalf("LoadSessions returned an error: %v", err)
+	}
+	if names := store.Names(); len(names) != 2 || names[0] != "anonima" {
+		t.Errorf("unexpected session names: %v", names)
+	}
+	session, _ := store.Get("intranet")
+	if got := session.login.Fields["password"]; got != "segredo" {
+		t.Errorf("expected the password to be read from the environment, got %q", got)
+	}
+}
+
+func TestFormSubmission(t *testing.T) {
+	doc, err := html.Parse(strings.NewReader(`<form name="busca" action="resultados">
+		<input name="q" value="vestibular">
+		<input type="checkbox" name="arquivo" value="sim">
+		<input name="desativado" value="x" disabled>
+		<select name="campus"><option value="garanhuns">Garanhuns</option><option selected>Recife</option></select>
+		<textarea name="obs">nada</textarea>
+		<button name="ir" value="1">Buscar</button>
+	</form>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	form := findForm(doc, "busca")
+	if form == nil {
+		t.Fatal("expected the form to be found by name")
+	}
+	base, _ := url.Parse("https://ufape.edu.br/busca/")
+
+	action, method, values, err := formSubmission(form, base)
+	if err != nil {
+		t.Fatalf("formSubmission returned an error: %v", err)
+	}
+	if action.String() != "https://ufape.edu.br/busca/resultados" || method != http.MethodGet {
+		t.Errorf("unexpected submission target: %s %s", method, action)
+	}
+	expected := url.Values{"q": {"vestibular"}, "campus": {"Recife"}, "obs": {"nada"}, "ir": {"1"}}
+	if values.Encode() != expected.Encode() {
+		t.Errorf("expected values %s, got %s", expected.Encode(), values.Encode())
+	}
+}
@@ -0,0 +1,14 @@
+package crawler
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/nettojulio/ufape-crawler-golang/internal/crawler"
+
+// tracer retorna o tracer do pacote a partir do TracerProvider global, que
+// pode ser configurado depois da inicialização do pacote.
+func tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
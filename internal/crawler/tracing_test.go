@@ -0,0 +1,82 @@
+package crawler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestCrawlSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("traceparent") != "" {
+			t.Error("trace context should not be sent to crawled sites")
+		}
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new", http.StatusFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><title>Nova</title><a href="/a">a</a></html>`))
+	}))
+	defer server.Close()
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	parent := trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID, TraceFlags: trace.FlagsSampled, Remote: true})
+	ctx := trace.ContextWithRemoteSpanContext(context.Background(), parent)
+
+	target, _ := url.Parse(server.URL + "/old")
+	payload := Payload{Url: target.String()}
+	modified := PrepareURLAndDefaults(&payload, target)
+	if _, err := CrawlWithRetry(ctx, NewService(NewHTTPClient(5*time.Second)), payload, target, modified, 0); err != nil {
+		t.Fatalf("CrawlWithRetry returned an error: %v", err)
+	}
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, s := range recorder.Ended() {
+		if s.SpanContext().TraceID() != traceID {
+			t.Errorf("span %s is not part of the incoming trace", s.Name())
+		}
+		spans[s.Name()] = s
+	}
+
+	parentOf := map[string]string{
+		"crawler.Service.Crawl": "crawler.attempt",
+		"crawler.fetch":         "crawler.Service.Crawl",
+		"crawler.parse":         "crawler.Service.Crawl",
+		"crawler.extract_links": "crawler.Service.Crawl",
+	}
+	if s, ok := spans["crawler.attempt"]; !ok || s.Parent().SpanID() != spanID {
+		t.Fatalf("expected crawler.attempt to be a child of the incoming span, got %v", spans)
+	}
+	for name, parentName := range parentOf {
+		s, ok := spans[name]
+		if !ok {
+			t.Errorf("missing span %s", name)
+			continue
+		}
+		if s.Parent().SpanID() != spans[parentName].SpanContext().SpanID() {
+			t.Errorf("expected %s to be a child of %s", name, parentName)
+		}
+	}
+	if events := spans["crawler.fetch"].Events(); len(events) != 1 || events[0].Name != "redirect" {
+		t.Errorf("expected one redirect event on crawler.fetch, got %+v", events)
+	}
+	if s, ok := spans["http.getconn"]; !ok || s.Parent().SpanID() != spans["crawler.fetch"].SpanContext().SpanID() {
+		t.Error("expected connection spans under crawler.fetch")
+	}
+}
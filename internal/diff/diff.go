@@ -0,0 +1,215 @@
+package diff
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/nettojulio/ufape-crawler-golang/internal/graph"
+)
+
+// PageRef identifica uma página adicionada ou removida entre dois crawlings.
+type PageRef struct {
+	URL        string `json:"url"`
+	StatusCode int    `json:"statusCode"`
+	Title      string `json:"title"`
+}
+
+// StatusChange registra a mudança de código de status de uma página.
+type StatusChange struct {
+	URL string `json:"url"`
+	Old int    `json:"old"`
+	New int    `json:"new"`
+}
+
+// TextChange registra a mudança de um atributo textual de uma página.
+type TextChange struct {
+	URL string `json:"url"`
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+// BrokenLink é uma aresta cujo destino passou a falhar.
+type BrokenLink struct {
+	Source     string `json:"source"`
+	Target     string `json:"target"`
+	StatusCode int    `json:"statusCode"`
+	ErrorClass string `json:"errorClass,omitempty"`
+}
+
+// Summary resume a quantidade de mudanças de cada tipo.
+type Summary struct {
+	AddedPages       int `json:"addedPages"`
+	RemovedPages     int `json:"removedPages"`
+	AddedLinks       int `json:"addedLinks"`
+	RemovedLinks     int `json:"removedLinks"`
+	StatusChanges    int `json:"statusChanges"`
+	TitleChanges     int `json:"titleChanges"`
+	ContentChanges   int `json:"contentChanges"`
+	NewlyBrokenLinks int `json:"newlyBrokenLinks"`
+}
+
+// Report é a diferença entre dois crawlings do mesmo site.
+type Report struct {
+	GeneratedAt      int64          `json:"generatedAt"`
+	OldGeneratedAt   int64          `json:"oldGeneratedAt"`
+	NewGeneratedAt   int64          `json:"newGeneratedAt"`
+	Summary          Summary        `json:"summary"`
+	AddedPages       []PageRef      `json:"addedPages"`
+	RemovedPages     []PageRef      `json:"removedPages"`
+	AddedLinks       []graph.Link   `json:"addedLinks"`
+	RemovedLinks     []graph.Link   `json:"removedLinks"`
+	StatusChanges    []StatusChange `json:"statusChanges"`
+	TitleChanges     []TextChange   `json:"titleChanges"`
+	ContentChanges   []TextChange   `json:"contentChanges"`
+	NewlyBrokenLinks []BrokenLink   `json:"newlyBrokenLinks"`
+}
+
+// HasChanges informa se o relatório contém alguma diferença.
+func (r *Report) HasChanges() bool {
+	return r.Summary != Summary{}
+}
+
+// Compare calcula as diferenças entre o crawling anterior e o atual.
+func Compare(previous, current *graph.FinalResponse) *Report {
+	report := &Report{
+		GeneratedAt:      time.Now().UTC().UnixMilli(),
+		OldGeneratedAt:   previous.GeneratedAt,
+		NewGeneratedAt:   current.GeneratedAt,
+		AddedPages:       []PageRef{},
+		RemovedPages:     []PageRef{},
+		AddedLinks:       []graph.Link{},
+		RemovedLinks:     []graph.Link{},
+		StatusChanges:    []StatusChange{},
+		TitleChanges:     []TextChange{},
+		ContentChanges:   []TextChange{},
+		NewlyBrokenLinks: []BrokenLink{},
+	}
+
+	oldNodes := nodesByID(previous)
+	newNodes := nodesByID(current)
+
+	for id, n := range newNodes {
+		old, existed := oldNodes[id]
+		if !existed {
+			report.AddedPages = append(report.AddedPages, pageRef(n))
+			continue
+		}
+		if n.HasTag(graph.TagExternal) || old.HasTag(graph.TagExternal) {
+			continue
+		}
+		if old.StatusCode != n.StatusCode {
+			report.StatusChanges = append(report.StatusChanges, StatusChange{URL: id, Old: old.StatusCode, New: n.StatusCode})
+		}
+		if old.Title != n.Title && old.StatusCode == http.StatusOK && n.StatusCode == http.StatusOK {
+			report.TitleChanges = append(report.TitleChanges, TextChange{URL: id, Old: old.Title, New: n.Title})
+		}
+		if old.ContentHash != "" && n.ContentHash != "" && old.ContentHash != n.ContentHash {
+			report.ContentChanges = append(report.ContentChanges, TextChange{URL: id, Old: old.ContentHash, New: n.ContentHash})
+		}
+	}
+	for id, n := range oldNodes {
+		if _, exists := newNodes[id]; !exists {
+			report.RemovedPages = append(report.RemovedPages, pageRef(n))
+		}
+	}
+
+	oldLinks := linkSet(previous)
+	newLinks := linkSet(current)
+	for key, l := range newLinks {
+		if _, existed := oldLinks[key]; !existed {
+			report.AddedLinks = append(report.AddedLinks, l)
+		}
+
+		target, ok := newNodes[l.Target]
+		if !ok || !isBroken(target) {
+			continue
+		}
+		_, linkExisted := oldLinks[key]
+		oldTarget, targetExisted := oldNodes[l.Target]
+		if !linkExisted || !targetExisted || !isBroken(oldTarget) {
+			report.NewlyBrokenLinks = append(report.NewlyBrokenLinks, BrokenLink{
+				Source:     l.Source,
+				Target:     l.Target,
+				StatusCode: target.StatusCode,
+				ErrorClass: target.ErrorClass,
+			})
+		}
+	}
+	for key, l := range oldLinks {
+		if _, exists := newLinks[key]; !exists {
+			report.RemovedLinks = append(report.RemovedLinks, l)
+		}
+	}
+
+	sortReport(report)
+	report.Summary = Summary{
+		AddedPages:       len(report.AddedPages),
+		RemovedPages:     len(report.RemovedPages),
+		AddedLinks:       len(report.AddedLinks),
+		RemovedLinks:     len(report.RemovedLinks),
+		StatusChanges:    len(report.StatusChanges),
+		TitleChanges:     len(report.TitleChanges),
+		ContentChanges:   len(report.ContentChanges),
+		NewlyBrokenLinks: len(report.NewlyBrokenLinks),
+	}
+	return report
+}
+
+// isBroken indica se a página falhou ao ser buscada. Destinos externos nunca
+// são buscados e, portanto, não são considerados quebrados.
+func isBroken(n graph.Node) bool {
+	if n.HasTag(graph.TagExternal) {
+		return false
+	}
+	return n.HasTag(graph.TagError) || n.StatusCode >= http.StatusBadRequest
+}
+
+func nodesByID(g *graph.FinalResponse) map[string]graph.Node {
+	nodes := make(map[string]graph.Node, len(g.Nodes))
+	for _, n := range g.Nodes {
+		nodes[n.ID] = n
+	}
+	return nodes
+}
+
+func linkSet(g *graph.FinalResponse) map[[2]string]graph.Link {
+	links := make(map[[2]string]graph.Link, len(g.Links))
+	for _, l := range g.Links {
+		links[[2]string{l.Source, l.Target}] = l
+	}
+	return links
+}
+
+func pageRef(n graph.Node) PageRef {
+	return PageRef{URL: n.ID, StatusCode: n.StatusCode, Title: n.Title}
+}
+
+func sortReport(r *Report) {
+	byURL := func(refs []PageRef) {
+		sort.Slice(refs, func(i, j int) bool { return refs[i].URL < refs[j].URL })
+	}
+	byEdge := func(links []graph.Link) {
+		sort.Slice(links, func(i, j int) bool {
+			if links[i].Source != links[j].Source {
+				return links[i].Source < links[j].Source
+			}
+			return links[i].Target < links[j].Target
+		})
+	}
+
+	byURL(r.AddedPages)
+	byURL(r.RemovedPages)
+	byEdge(r.AddedLinks)
+	byEdge(r.RemovedLinks)
+	sort.Slice(r.StatusChanges, func(i, j int) bool { return r.StatusChanges[i].URL < r.StatusChanges[j].URL })
+	sort.Slice(r.TitleChanges, func(i, j int) bool { return r.TitleChanges[i].URL < r.TitleChanges[j].URL })
+	sort.Slice(r.ContentChanges, func(i, j int) bool { return r.ContentChanges[i].URL < r.ContentChanges[j].URL })
+	sort.Slice(r.NewlyBrokenLinks, func(i, j int) bool {
+		a, b := r.NewlyBrokenLinks[i], r.NewlyBrokenLinks[j]
+		if a.Target != b.Target {
+			return a.Target < b.Target
+		}
+		return a.Source < b.Source
+	})
+}
@@ -0,0 +1,102 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nettojulio/ufape-crawler-golang/internal/graph"
+)
+
+func snapshots() (*graph.FinalResponse, *graph.FinalResponse) {
+	previous := &graph.FinalResponse{
+		GeneratedAt: 1000,
+		Nodes: []graph.Node{
+			{ID: "home", StatusCode: 200, Title: "Início", ContentHash: "aaa"},
+			{ID: "news", StatusCode: 200, Title: "Notícias", ContentHash: "bbb"},
+			{ID: "old", StatusCode: 200, Title: "Antiga"},
+			{ID: "contact", StatusCode: 200, Title: "Contato"},
+			{ID: "https://external.example", Tags: []string{graph.TagExternal}},
+		},
+		Links: []graph.Link{
+			graph.NewLink("home", "news"),
+			graph.NewLink("home", "old"),
+			graph.NewLink("home", "contact"),
+			graph.NewLink("home", "https://external.example"),
+		},
+	}
+	current := &graph.FinalResponse{
+		GeneratedAt: 2000,
+		Nodes: []graph.Node{
+			{ID: "home", StatusCode: 200, Title: "Início", ContentHash: "aaa"},
+			{ID: "news", StatusCode: 200, Title: "Notícias da UFAPE", ContentHash: "ccc"},
+			{ID: "contact", StatusCode: 404, Title: ""},
+			{ID: "new", StatusCode: 503, Tags: []string{graph.TagError}, ErrorClass: "timeout"},
+			{ID: "https://external.example", Tags: []string{graph.TagExternal}},
+		},
+		Links: []graph.Link{
+			graph.NewLink("home", "news"),
+			graph.NewLink("home", "contact"),
+			graph.NewLink("news", "new"),
+			graph.NewLink("home", "https://external.example"),
+		},
+	}
+	return previous, current
+}
+
+func TestCompare(t *testing.T) {
+	previous, current := snapshots()
+	report := Compare(previous, current)
+
+	expected := Summary{
+		AddedPages:       1,
+		RemovedPages:     1,
+		AddedLinks:       1,
+		RemovedLinks:     1,
+		StatusChanges:    1,
+		TitleChanges:     1,
+		ContentChanges:   1,
+		NewlyBrokenLinks: 2,
+	}
+	if report.Summary != expected {
+		t.Fatalf("unexpected summary\ngot:  %+v\nwant: %+v", report.Summary, expected)
+	}
+
+	if report.AddedPages[0].URL != "new" || report.RemovedPages[0].URL != "old" {
+		t.Errorf("unexpected added/removed pages: %+v / %+v", report.AddedPages, report.RemovedPages)
+	}
+	if c := report.StatusChanges[0]; c.URL != "contact" || c.Old != 200 || c.New != 404 {
+		t.Errorf("unexpected status change: %+v", c)
+	}
+	if c := report.TitleChanges[0]; c.URL != "news" || c.New != "Notícias da UFAPE" {
+		t.Errorf("unexpected title change: %+v", c)
+	}
+	if report.NewlyBrokenLinks[0].Target != "contact" || report.NewlyBrokenLinks[1].ErrorClass != "timeout" {
+		t.Errorf("unexpected broken links: %+v", report.NewlyBrokenLinks)
+	}
+	if !report.HasChanges() {
+		t.Error("expected HasChanges to be true")
+	}
+}
+
+func TestCompareIdentical(t *testing.T) {
+	previous, _ := snapshots()
+	report := Compare(previous, previous)
+
+	if report.HasChanges() {
+		t.Errorf("expected no changes, got %+v", report.Summary)
+	}
+	if !strings.Contains(report.Markdown(), "Nenhuma mudança encontrada.") {
+		t.Error("expected markdown to state that there are no changes")
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	previous, current := snapshots()
+	md := Compare(previous, current).Markdown()
+
+	for _, section := range []string{"## Resumo", "## Links quebrados novos", "## Mudanças de status", "## Páginas adicionadas", "| news | new | 503 | timeout |"} {
+		if !strings.Contains(md, section) {
+			t.Errorf("expected markdown to contain %q", section)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package diff
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Markdown formata o relatório como um documento Markdown para revisão.
+func (r *Report) Markdown() string {
+	var b strings.Builder
+	_ = r.WriteMarkdown(&b)
+	return b.String()
+}
+
+// WriteMarkdown escreve o relatório em Markdown em w.
+func (r *Report) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+
+	b.WriteString("# Diferenças entre crawlings\n\n")
+	fmt.Fprintf(&b, "- Anterior: %s\n", formatMillis(r.OldGeneratedAt))
+	fmt.Fprintf(&b, "- Atual: %s\n\n", formatMillis(r.NewGeneratedAt))
+
+	b.WriteString("## Resumo\n\n")
+	b.WriteString("| Mudança | Quantidade |\n|---|---:|\n")
+	fmt.Fprintf(&b, "| Páginas adicionadas | %d |\n", r.Summary.AddedPages)
+	fmt.Fprintf(&b, "| Páginas removidas | %d |\n", r.Summary.RemovedPages)
+	fmt.Fprintf(&b, "| Links adicionados | %d |\n", r.Summary.AddedLinks)
+	fmt.Fprintf(&b, "| Links removidos | %d |\n", r.Summary.RemovedLinks)
+	fmt.Fprintf(&b, "| Mudanças de status | %d |\n", r.Summary.StatusChanges)
+	fmt.Fprintf(&b, "| Mudanças de título | %d |\n", r.Summary.TitleChanges)
+	fmt.Fprintf(&b, "| Mudanças de conteúdo | %d |\n", r.Summary.ContentChanges)
+	fmt.Fprintf(&b, "| Links quebrados novos | %d |\n", r.Summary.NewlyBrokenLinks)
+
+	if !r.HasChanges() {
+		b.WriteString("\nNenhuma mudança encontrada.\n")
+	}
+
+	if len(r.NewlyBrokenLinks) > 0 {
+		b.WriteString("\n## Links quebrados novos\n\n| Origem | Destino | Status | Erro |\n|---|---|---:|---|\n")
+		for _, l := range r.NewlyBrokenLinks {
+			fmt.Fprintf(&b, "| %s | %s | %d | %s |\n", cell(l.Source), cell(l.Target), l.StatusCode, cell(l.ErrorClass))
+		}
+	}
+	if len(r.StatusChanges) > 0 {
+		b.WriteString("\n## Mudanças de status\n\n| Página | Anterior | Atual |\n|---|---:|---:|\n")
+		for _, c := range r.StatusChanges {
+			fmt.Fprintf(&b, "| %s | %d | %d |\n", cell(c.URL), c.Old, c.New)
+		}
+	}
+	if len(r.TitleChanges) > 0 {
+		b.WriteString("\n## Mudanças de título\n\n| Página | Anterior | Atual |\n|---|---|---|\n")
+		for _, c := range r.TitleChanges {
+			fmt.Fprintf(&b, "| %s | %s | %s |\n", cell(c.URL), cell(c.Old), cell(c.New))
+		}
+	}
+	if len(r.ContentChanges) > 0 {
+		b.WriteString("\n## Mudanças de conteúdo\n\n")
+		for _, c := range r.ContentChanges {
+			fmt.Fprintf(&b, "- %s (`%s` → `%s`)\n", c.URL, shortHash(c.Old), shortHash(c.New))
+		}
+	}
+	writePages(&b, "Páginas adicionadas", r.AddedPages)
+	writePages(&b, "Páginas removidas", r.RemovedPages)
+	if len(r.AddedLinks) > 0 {
+		b.WriteString("\n## Links adicionados\n\n")
+		for _, l := range r.AddedLinks {
+			fmt.Fprintf(&b, "- %s → %s\n", l.Source, l.Target)
+		}
+	}
+	if len(r.RemovedLinks) > 0 {
+		b.WriteString("\n## Links removidos\n\n")
+		for _, l := range r.RemovedLinks {
+			fmt.Fprintf(&b, "- %s → %s\n", l.Source, l.Target)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writePages(b *strings.Builder, title string, pages []PageRef) {
+	if len(pages) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n## %s\n\n| Página | Status | Título |\n|---|---:|---|\n", title)
+	for _, p := range pages {
+		fmt.Fprintf(b, "| %s | %d | %s |\n", cell(p.URL), p.StatusCode, cell(p.Title))
+	}
+}
+
+func formatMillis(ms int64) string {
+	if ms == 0 {
+		return "desconhecido"
+	}
+	return time.UnixMilli(ms).UTC().Format(time.RFC3339)
+}
+
+// cell escapa o conteúdo de uma célula de tabela Markdown.
+func cell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+func shortHash(h string) string {
+	if len(h) > 12 {
+		return h[:12]
+	}
+	return h
+}
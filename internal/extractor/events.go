@@ -0,0 +1,49 @@
+package extractor
+
+import "time"
+
+// EventType identifica um evento de progresso do crawling.
+type EventType string
+
+const (
+	EventStarted         EventType = "crawl_started"
+	EventPageStarted     EventType = "page_started"
+	EventPageFetched     EventType = "page_fetched"
+	EventLinksDiscovered EventType = "links_discovered"
+	EventPageFailed      EventType = "page_failed"
+	EventFinished        EventType = "crawl_finished"
+)
+
+// Counters resume o andamento do crawling no momento de um evento.
+type Counters struct {
+	// Discovered conta as páginas internas já enfileiradas, incluindo a inicial.
+	Discovered int `json:"discovered"`
+	Queued     int `json:"queued"`
+	Fetched    int `json:"fetched"`
+	Failed     int `json:"failed"`
+	External   int `json:"external"`
+	Links      int `json:"links"`
+}
+
+// LinkCounts detalha os links encontrados em uma página.
+type LinkCounts struct {
+	Internal int `json:"internal"`
+	// New conta os links internos que ainda não tinham sido vistos.
+	New      int `json:"new"`
+	External int `json:"external"`
+}
+
+// Event é um evento de progresso emitido pelo Crawler.
+type Event struct {
+	Type        EventType   `json:"type"`
+	Time        time.Time   `json:"time"`
+	URL         string      `json:"url,omitempty"`
+	Depth       int         `json:"depth,omitempty"`
+	StatusCode  int         `json:"statusCode,omitempty"`
+	ElapsedTime int64       `json:"elapsedTime,omitempty"`
+	Title       string      `json:"title,omitempty"`
+	Links       *LinkCounts `json:"links,omitempty"`
+	ErrorClass  string      `json:"errorClass,omitempty"`
+	Error       string      `json:"error,omitempty"`
+	Counters    Counters    `json:"counters"`
+}
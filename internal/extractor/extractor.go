@@ -0,0 +1,414 @@
+// Package extractor percorre um site em largura a partir de uma URL inicial e
+// monta o grafo de páginas e links encontrado.
+package extractor
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/nettojulio/ufape-crawler-golang/internal/crawler"
+	"github.com/nettojulio/ufape-crawler-golang/internal/graph"
+	"github.com/nettojulio/ufape-crawler-golang/internal/logging"
+	"github.com/nettojulio/ufape-crawler-golang/internal/snapshot"
+	"github.com/nettojulio/ufape-crawler-golang/internal/storage"
+)
+
+// Options controla o percurso do Crawler.
+type Options struct {
+	MaxDepth int
+	// FullGraph registra todas as arestas internas, inclusive para páginas já
+	// visitadas e os links de uma página para ela mesma. Quando falso, o grafo
+	// salvo é apenas a árvore de busca em largura.
+	FullGraph bool
+	// Source identifica a origem das execuções registradas por RecordTo.
+	// O padrão é storage.SourceExtractor.
+	Source string
+	// Snapshots, quando definido, guarda o HTML das páginas buscadas com
+	// sucesso. O Fetcher deve solicitar o corpo das respostas; veja SnapshotPayload.
+	Snapshots *snapshot.Store
+	// OnEvent, quando definido, recebe os eventos de progresso de forma
+	// síncrona, na goroutine do crawling.
+	OnEvent func(Event)
+	// Logger recebe o progresso e os avisos do crawling e é repassado ao
+	// Fetcher pelo contexto de cada busca, com a URL da página. Quando nil,
+	// nada é registrado.
+	Logger *slog.Logger
+}
+
+type CrawlItem struct {
+	URL   string
+	Depth int
+}
+
+type Crawler struct {
+	fetcher   Fetcher
+	logger    *slog.Logger
+	snapshots *snapshot.Store
+	onEvent   func(Event)
+	counters  Counters
+
+	queue    *list.List
+	visited  map[string]struct{}
+	external map[string]struct{}
+	result   *graph.FinalResponse
+
+	// sinks recebem nós e arestas assim que são produzidos. Com inMemory
+	// falso, o grafo não é acumulado em result.
+	sinks    []graphSink
+	inMemory bool
+
+	// repository, quando definido, recebe as buscas da execução runID.
+	repository storage.Repository
+	runID      string
+	source     string
+
+	maxDepth  int
+	fullGraph bool
+}
+
+// NewCrawler cria um Crawler que busca as páginas por meio de fetcher.
+func NewCrawler(fetcher Fetcher, opts Options) *Crawler {
+	logger := opts.Logger
+	if logger == nil {
+		logger = logging.Discard()
+	}
+	source := opts.Source
+	if source == "" {
+		source = storage.SourceExtractor
+	}
+
+	return &Crawler{
+		fetcher:   fetcher,
+		logger:    logger,
+		snapshots: opts.Snapshots,
+		onEvent:   opts.OnEvent,
+		queue:     list.New(),
+		visited:   make(map[string]struct{}),
+		external:  make(map[string]struct{}),
+		result: &graph.FinalResponse{
+			Nodes:       []graph.Node{},
+			Links:       []graph.Link{},
+			GeneratedAt: time.Now().UTC().UnixMilli(),
+		},
+		inMemory:  true,
+		source:    source,
+		maxDepth:  opts.MaxDepth,
+		fullGraph: opts.FullGraph,
+	}
+}
+
+// graphSink recebe nós e arestas do grafo à medida que são produzidos.
+type graphSink interface {
+	WriteNode(node graph.Node) error
+	WriteLink(link graph.Link) error
+}
+
+// Crawl percorre o site a partir de initialURL. Retorna o erro de ctx se o
+// crawling for interrompido antes de esvaziar a fila.
+func (c *Crawler) Crawl(ctx context.Context, initialURL string) error {
+	normalizedInitialURL := c.normalizeLink(initialURL)
+	c.enqueue(&CrawlItem{URL: normalizedInitialURL, Depth: 1})
+	c.markAsVisited(normalizedInitialURL)
+	c.counters.Discovered++
+	c.emit(Event{Type: EventStarted, URL: normalizedInitialURL})
+
+	for c.queue.Len() > 0 {
+		if err := ctx.Err(); err != nil {
+			c.emit(Event{Type: EventFinished, Error: context.Cause(ctx).Error()})
+			return err
+		}
+
+		item := c.dequeue()
+		if item == nil {
+			continue
+		}
+
+		if item.Depth > c.maxDepth {
+			continue
+		}
+
+		pageLogger := c.logger.With("url", item.URL, "depth", item.Depth)
+		pageLogger.InfoContext(ctx, "crawling page")
+		c.emit(Event{Type: EventPageStarted, URL: item.URL, Depth: item.Depth})
+
+		response, err := c.fetcher.Fetch(logging.WithContext(ctx, pageLogger), item.URL)
+		if err != nil && ctx.Err() != nil {
+			c.emit(Event{Type: EventFinished, Error: context.Cause(ctx).Error()})
+			return ctx.Err()
+		}
+		c.recordFetch(ctx, item.URL, response, err)
+		if err != nil {
+			pageLogger.WarnContext(ctx, "page fetch failed, continuing", "error", err)
+			node := NewFailedNode(item.URL, item.Depth, err)
+			c.addNode(node)
+			c.counters.Failed++
+			c.emit(Event{Type: EventPageFailed, URL: item.URL, Depth: item.Depth, ErrorClass: node.ErrorClass, Error: node.Error})
+			continue
+		}
+		c.countFetched(item, response)
+
+		c.saveSnapshot(item.URL, response)
+
+		discovered := LinkCounts{}
+		edges := newEdgeSet(item.URL)
+		for _, link := range response.Links.Available {
+			normalizedLink := c.normalizeLink(link)
+			count := max(response.Links.Occurrences[link], 1)
+			if edges.visit(normalizedLink, count) {
+				continue
+			}
+
+			discovered.Internal++
+			isNew := c.shouldVisit(normalizedLink)
+			if isNew {
+				c.enqueue(&CrawlItem{URL: normalizedLink, Depth: item.Depth + 1})
+				c.markAsVisited(normalizedLink)
+				c.counters.Discovered++
+				discovered.New++
+			}
+			if isNew || c.fullGraph {
+				edges.add(normalizedLink, count)
+			}
+		}
+		for _, link := range response.Links.Unavailable {
+			normalizedLink := c.normalizeLink(link)
+			count := max(response.Links.Occurrences[link], 1)
+			if edges.visit(normalizedLink, count) {
+				continue
+			}
+
+			discovered.External++
+			_, known := c.external[normalizedLink]
+			if !known {
+				c.external[normalizedLink] = struct{}{}
+				c.addNode(NewExternalNode(normalizedLink, item.Depth+1))
+				c.counters.External++
+			}
+			if !known || c.fullGraph {
+				edges.add(normalizedLink, count)
+			}
+		}
+		c.addResponseToGraph(response, item, edges.links)
+		c.counters.Links += len(edges.links)
+		c.emit(Event{Type: EventLinksDiscovered, URL: item.URL, Depth: item.Depth, Links: &discovered})
+	}
+	counters := c.Counters()
+	c.logger.InfoContext(ctx, "crawl finished",
+		"fetched", counters.Fetched, "failed", counters.Failed, "external", counters.External, "links", counters.Links)
+	c.emit(Event{Type: EventFinished})
+	return nil
+}
+
+// Counters retorna os contadores de progresso atuais.
+func (c *Crawler) Counters() Counters {
+	counters := c.counters
+	counters.Queued = c.queue.Len()
+	return counters
+}
+
+// countFetched atualiza os contadores e emite o evento de uma página buscada.
+// Respostas classificadas como erro pelo crawler contam como falhas.
+func (c *Crawler) countFetched(item *CrawlItem, response *crawler.ResponseDTO) {
+	event := Event{
+		Type:        EventPageFetched,
+		URL:         item.URL,
+		Depth:       item.Depth,
+		StatusCode:  response.StatusCode,
+		ElapsedTime: response.ElapsedTime,
+		Title:       response.Title,
+	}
+	if response.ErrorClass != "" {
+		c.counters.Failed++
+		event.Type = EventPageFailed
+		event.ErrorClass = string(response.ErrorClass)
+		event.Error = response.Title
+		event.Title = ""
+	} else {
+		c.counters.Fetched++
+	}
+	c.emit(event)
+}
+
+func (c *Crawler) emit(event Event) {
+	if c.onEvent == nil {
+		return
+	}
+	event.Time = time.Now().UTC()
+	event.Counters = c.counters
+	event.Counters.Queued = c.queue.Len()
+	c.onEvent(event)
+}
+
+// Result retorna o grafo acumulado. Fica vazio quando o crawling é gravado
+// apenas em fluxo por StreamTo.
+func (c *Crawler) Result() *graph.FinalResponse {
+	return c.result
+}
+
+// RunID retorna o ID da execução registrada por RecordTo.
+func (c *Crawler) RunID() string {
+	return c.runID
+}
+
+// StreamTo passa a gravar o grafo em NDJSON à medida que o crawling avança,
+// sem mantê-lo em memória.
+func (c *Crawler) StreamTo(stream *graph.StreamWriter) error {
+	c.inMemory = false
+	c.sinks = append(c.sinks, stream)
+	return stream.WriteHeader(c.result.GeneratedAt)
+}
+
+// RecordTo registra uma nova execução no repositório e passa a gravar nela
+// as páginas, arestas e buscas do crawling.
+func (c *Crawler) RecordTo(ctx context.Context, repo storage.Repository, seedURL string) error {
+	run := &storage.Run{SeedURL: seedURL, Source: c.source}
+	if err := repo.CreateRun(ctx, run); err != nil {
+		return err
+	}
+	c.repository = repo
+	c.runID = run.ID
+	c.sinks = append(c.sinks, storage.NewGraphWriter(ctx, repo, run.ID))
+	return nil
+}
+
+// FinishRecording encerra a execução registrada por RecordTo.
+func (c *Crawler) FinishRecording(ctx context.Context, status storage.RunStatus) error {
+	if c.repository == nil {
+		return nil
+	}
+	return c.repository.FinishRun(ctx, c.runID, status, time.Now().UTC())
+}
+
+func (c *Crawler) recordFetch(ctx context.Context, link string, response *crawler.ResponseDTO, fetchErr error) {
+	if c.repository == nil {
+		return
+	}
+
+	fetch := storage.Fetch{RunID: c.runID, URL: link, FetchedAt: time.Now().UTC()}
+	if fetchErr != nil {
+		fetch.Error = fetchErr.Error()
+		fetch.ErrorClass = string(ClassifyFetchError(fetchErr))
+	} else {
+		fetch.FinalURL = response.Details.CorrectURL
+		fetch.StatusCode = response.StatusCode
+		fetch.ContentType = response.ContentType
+		fetch.ElapsedTime = time.Duration(response.ElapsedTime)
+		fetch.ErrorClass = string(response.ErrorClass)
+		if response.ErrorClass != "" {
+			fetch.Error = response.Title
+		}
+	}
+	if err := c.repository.SaveFetch(ctx, fetch); err != nil {
+		c.logger.WarnContext(ctx, "failed to record fetch", "url", link, "error", err)
+	}
+}
+
+// SnapshotPayload ajusta o payload modelo para que as respostas tragam o corpo
+// completo das páginas, como exigido por Options.Snapshots.
+func SnapshotPayload(template crawler.Payload) crawler.Payload {
+	mode := crawler.BodyBase64
+	size := crawler.MaxBodySize
+	template.IncludeBody = &mode
+	template.MaxBodySize = &size
+	return template
+}
+
+// saveSnapshot guarda o HTML de uma resposta 200 completa.
+func (c *Crawler) saveSnapshot(link string, response *crawler.ResponseDTO) {
+	if c.snapshots == nil || response.StatusCode != http.StatusOK || response.BodyTruncated ||
+		!strings.Contains(response.ContentType, "html") {
+		return
+	}
+
+	body, err := response.DecodedBody()
+	if err != nil || body == nil {
+		c.logger.Warn("response without body for snapshot", "url", link, "error", err)
+		return
+	}
+	if _, err := c.snapshots.Put(body); err != nil {
+		c.logger.Warn("failed to store snapshot", "url", link, "error", err)
+	}
+}
+
+func (c *Crawler) addResponseToGraph(response *crawler.ResponseDTO, sourceItem *CrawlItem, edges []graph.Link) {
+	c.addNode(NewNode(sourceItem.URL, sourceItem.Depth, response))
+	for _, link := range edges {
+		c.addLink(link)
+	}
+}
+
+func (c *Crawler) addNode(node graph.Node) {
+	if c.inMemory {
+		c.result.Nodes = append(c.result.Nodes, node)
+	}
+	for _, sink := range c.sinks {
+		if err := sink.WriteNode(node); err != nil {
+			c.logger.Warn("failed to write node", "node", node.ID, "error", err)
+		}
+	}
+}
+
+func (c *Crawler) addLink(link graph.Link) {
+	if c.inMemory {
+		c.result.Links = append(c.result.Links, link)
+	}
+	for _, sink := range c.sinks {
+		if err := sink.WriteLink(link); err != nil {
+			c.logger.Warn("failed to write link", "source", link.Source, "target", link.Target, "error", err)
+		}
+	}
+}
+
+func (c *Crawler) normalizeLink(link string) string {
+	parsedURL, err := url.Parse(link)
+	if err != nil {
+		return link
+	}
+	parsedURL.Fragment = ""
+	return strings.TrimSuffix(parsedURL.String(), "/")
+}
+
+func (c *Crawler) shouldVisit(u string) bool {
+	_, exists := c.visited[u]
+	return !exists
+}
+
+func (c *Crawler) markAsVisited(u string) {
+	c.visited[u] = struct{}{}
+
+	if strings.HasPrefix(u, "https://") {
+		httpVersion := "http" + u[5:]
+		c.visited[httpVersion] = struct{}{}
+	} else if strings.HasPrefix(u, "http://") {
+		httpsVersion := "httpss" + u[4:]
+		c.visited[httpsVersion] = struct{}{}
+	}
+}
+
+func (c *Crawler) SaveResult(filename string) error {
+	if err := graph.Save(c.result, filename); err != nil {
+		return fmt.Errorf("falha ao salvar resultado: %w", err)
+	}
+
+	c.logger.Info("result saved", "file", filename)
+	return nil
+}
+
+func (c *Crawler) enqueue(item *CrawlItem) {
+	c.queue.PushBack(item)
+}
+
+func (c *Crawler) dequeue() *CrawlItem {
+	if c.queue.Len() == 0 {
+		return nil
+	}
+	element := c.queue.Front()
+	c.queue.Remove(element)
+	return element.Value.(*CrawlItem)
+}
@@ -0,0 +1,130 @@
+package extractor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/nettojulio/ufape-crawler-golang/internal/crawler"
+	"github.com/nettojulio/ufape-crawler-golang/internal/graph"
+	"github.com/nettojulio/ufape-crawler-golang/internal/snapshot"
+)
+
+type mapFetcher map[string]*crawler.ResponseDTO
+
+func (m mapFetcher) Fetch(ctx context.Context, link string) (*crawler.ResponseDTO, error) {
+	response, ok := m[link]
+	if !ok {
+		return nil, errors.New("connection refused")
+	}
+	return response, nil
+}
+
+func page(available, unavailable []string) *crawler.ResponseDTO {
+	return &crawler.ResponseDTO{
+		StatusCode: 200,
+		Links:      crawler.LinksResponse{Available: available, Unavailable: unavailable},
+	}
+}
+
+func site() mapFetcher {
+	return mapFetcher{
+		"https://example.com":   page([]string{"https://example.com/a", "https://example.com/b"}, []string{"https://other.example"}),
+		"https://example.com/a": page([]string{"https://example.com/b", "https://example.com/missing"}, nil),
+		"https://example.com/b": page([]string{"https://example.com/"}, nil),
+	}
+}
+
+func TestCrawl(t *testing.T) {
+	c := NewCrawler(site(), Options{MaxDepth: 10, FullGraph: true})
+	if err := c.Crawl(context.Background(), "https://example.com/"); err != nil {
+		t.Fatalf("Crawl() returned an unexpected error: %v", err)
+	}
+
+	result := c.Result()
+	nodes := make(map[string]graph.Node)
+	for _, n := range result.Nodes {
+		nodes[n.ID] = n
+	}
+	if len(nodes) != 5 {
+		t.Fatalf("expected 5 nodes, got %d: %+v", len(nodes), result.Nodes)
+	}
+	if !nodes["https://other.example"].HasTag(graph.TagExternal) {
+		t.Error("expected the external target to be tagged")
+	}
+	if n := nodes["https://example.com/missing"]; !n.HasTag(graph.TagError) || n.Depth != 3 {
+		t.Errorf("expected a failed node at depth 3, got %+v", n)
+	}
+	if len(result.Links) != 6 {
+		t.Errorf("expected 6 links in the full graph, got %d", len(result.Links))
+	}
+}
+
+func TestCrawlTree(t *testing.T) {
+	c := NewCrawler(site(), Options{MaxDepth: 10})
+	_ = c.Crawl(context.Background(), "https://example.com")
+
+	if len(c.Result().Links) != 4 {
+		t.Errorf("expected only the 4 tree links, got %+v", c.Result().Links)
+	}
+}
+
+func TestCrawlCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	c := NewCrawler(site(), Options{MaxDepth: 10})
+	if err := c.Crawl(ctx, "https://example.com"); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestCrawlSavesSnapshots(t *testing.T) {
+	store, err := snapshot.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStore() returned an unexpected error: %v", err)
+	}
+	html := "<html><title>Home</title></html>"
+	home := &crawler.ResponseDTO{StatusCode: 200, ContentType: "text/html", ContentHash: snapshot.Hash([]byte(html))}
+	mode, size := crawler.BodyText, 1024
+	home.AttachBody(&crawler.CrawlResult{Body: []byte(html)}, crawler.Payload{IncludeBody: &mode, MaxBodySize: &size})
+
+	c := NewCrawler(mapFetcher{"https://example.com": home}, Options{MaxDepth: 1, Snapshots: store})
+	_ = c.Crawl(context.Background(), "https://example.com")
+
+	node := c.Result().Nodes[0]
+	body, err := store.Get(node.ContentHash)
+	if err != nil || string(body) != html {
+		t.Errorf("expected the page to be stored under its content hash, got %q (%v)", body, err)
+	}
+}
+
+func TestCrawlCountsRepeatedLinksOnce(t *testing.T) {
+	pages := mapFetcher{
+		"https://example.com":   page([]string{"https://example.com/a"}, nil),
+		"https://example.com/a": page([]string{"https://example.com/", "https://example.com", "https://example.com/", "https://example.com/a"}, nil),
+	}
+
+	for _, fullGraph := range []bool{false, true} {
+		var internal int
+		c := NewCrawler(pages, Options{MaxDepth: 10, FullGraph: fullGraph, OnEvent: func(e Event) {
+			if e.Type == EventLinksDiscovered && e.URL == "https://example.com/a" {
+				internal = e.Links.Internal
+			}
+		}})
+		_ = c.Crawl(context.Background(), "https://example.com")
+
+		if internal != 2 {
+			t.Errorf("fullGraph=%v: expected 2 distinct internal links, got %d", fullGraph, internal)
+		}
+		selfLoops := 0
+		for _, link := range c.Result().Links {
+			if link.Source == link.Target {
+				selfLoops++
+			}
+		}
+		if want := map[bool]int{false: 0, true: 1}[fullGraph]; selfLoops != want {
+			t.Errorf("fullGraph=%v: expected %d self-links, got %d", fullGraph, want, selfLoops)
+		}
+	}
+}
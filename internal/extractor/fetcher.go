@@ -0,0 +1,125 @@
+package extractor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/nettojulio/ufape-crawler-golang/internal/crawler"
+)
+
+// Fetcher busca uma página e retorna a resposta no formato da API.
+type Fetcher interface {
+	Fetch(ctx context.Context, link string) (*crawler.ResponseDTO, error)
+}
+
+// payloadFor copia o payload modelo para a URL informada. Os campos apontados
+// são copiados porque o crawler pode alterá-los ao aplicar os valores padrão.
+func payloadFor(template crawler.Payload, link string) crawler.Payload {
+	payload := template
+	payload.Url = link
+	if template.AllowedDomains != nil {
+		domains := append([]string(nil), *template.AllowedDomains...)
+		payload.AllowedDomains = &domains
+	}
+	return payload
+}
+
+// APIFetcher busca as páginas pelo endpoint de crawling de uma API em execução.
+type APIFetcher struct {
+	httpClient *http.Client
+	baseURL    string
+	template   crawler.Payload
+}
+
+// NewAPIFetcher cria um APIFetcher que envia template, com a URL de cada
+// página, para o endpoint baseURL.
+func NewAPIFetcher(baseURL string, timeout time.Duration, template crawler.Payload) *APIFetcher {
+	transport := &http.Transport{
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     90 * time.Second,
+	}
+
+	return &APIFetcher{
+		httpClient: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+		},
+		baseURL:  baseURL,
+		template: template,
+	}
+}
+
+func (f *APIFetcher) Fetch(ctx context.Context, link string) (*crawler.ResponseDTO, error) {
+	payload := payloadFor(f.template, link)
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", f.baseURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("falha ao criar requisição: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := f.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("falha na requisição http: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, &fetchError{class: crawler.ErrorClassHTTPStatus, err: fmt.Errorf("status inesperado: %s", res.Status)}
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao ler corpo da resposta: %w", err)
+	}
+
+	var apiResponse crawler.ResponseDTO
+	if err := json.Unmarshal(body, &apiResponse); err != nil {
+		return nil, &fetchError{class: crawler.ErrorClassInvalidResponse, err: fmt.Errorf("falha ao decodificar JSON: %w", err)}
+	}
+
+	return &apiResponse, nil
+}
+
+// ServiceFetcher busca as páginas diretamente pelo serviço de crawling, sem
+// passar pela API HTTP. É usado pelos crawlings agendados no próprio servidor.
+type ServiceFetcher struct {
+	service    crawler.Crawler
+	template   crawler.Payload
+	retryDelay time.Duration
+}
+
+// NewServiceFetcher cria um ServiceFetcher que aplica template a cada página.
+func NewServiceFetcher(service crawler.Crawler, template crawler.Payload) *ServiceFetcher {
+	return &ServiceFetcher{service: service, template: template, retryDelay: crawler.CurrentDefaults().RetryDelay}
+}
+
+func (f *ServiceFetcher) Fetch(ctx context.Context, link string) (*crawler.ResponseDTO, error) {
+	originalURL, err := url.Parse(link)
+	if err != nil {
+		return nil, &fetchError{class: crawler.ErrorClassInvalidResponse, err: fmt.Errorf("url inválida: %w", err)}
+	}
+
+	payload := payloadFor(f.template, link)
+	modifiedURL := crawler.PrepareURLAndDefaults(&payload, originalURL)
+
+	result, err := crawler.CrawlWithRetry(ctx, f.service, payload, originalURL, modifiedURL, f.retryDelay)
+	if err != nil {
+		return nil, err
+	}
+
+	response := crawler.NewResponseDTO(result, originalURL)
+	response.AttachBody(result, payload)
+	return &response, nil
+}
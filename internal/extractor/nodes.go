@@ -0,0 +1,111 @@
+package extractor
+
+import (
+	"errors"
+	"net/url"
+
+	"github.com/nettojulio/ufape-crawler-golang/internal/crawler"
+	"github.com/nettojulio/ufape-crawler-golang/internal/graph"
+)
+
+// NewNode cria o nó de uma página buscada a partir da resposta do crawler.
+func NewNode(url string, depth int, response *crawler.ResponseDTO) graph.Node {
+	node := graph.Node{
+		ID:          url,
+		Depth:       depth,
+		StatusCode:  response.StatusCode,
+		ContentType: response.ContentType,
+		ElapsedTime: response.ElapsedTime,
+		Title:       response.Title,
+		Domain:      response.Details.Original.Host,
+		ContentHash: response.ContentHash,
+	}
+	if response.ErrorClass != "" {
+		node.Tags = []string{graph.TagError}
+		node.Error = response.Title
+		node.ErrorClass = string(response.ErrorClass)
+	}
+	return node
+}
+
+// NewExternalNode cria um nó folha para um destino fora dos domínios permitidos.
+func NewExternalNode(link string, depth int) graph.Node {
+	return graph.Node{
+		ID:     link,
+		Depth:  depth,
+		Domain: hostOf(link),
+		Tags:   []string{graph.TagExternal},
+	}
+}
+
+// NewFailedNode cria um nó para uma página cuja busca falhou.
+func NewFailedNode(link string, depth int, err error) graph.Node {
+	return graph.Node{
+		ID:         link,
+		Depth:      depth,
+		Domain:     hostOf(link),
+		Tags:       []string{graph.TagError},
+		Error:      err.Error(),
+		ErrorClass: string(ClassifyFetchError(err)),
+	}
+}
+
+// fetchError associa uma classe de erro às falhas detectadas pelo próprio extractor.
+type fetchError struct {
+	class crawler.ErrorClass
+	err   error
+}
+
+func (e *fetchError) Error() string { return e.err.Error() }
+
+func (e *fetchError) Unwrap() error { return e.err }
+
+// ClassifyFetchError classifica um erro retornado por um Fetcher.
+func ClassifyFetchError(err error) crawler.ErrorClass {
+	var fe *fetchError
+	if errors.As(err, &fe) {
+		return fe.class
+	}
+	return crawler.ClassifyError(err)
+}
+
+func hostOf(link string) string {
+	parsedURL, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+	return parsedURL.Host
+}
+
+// edgeSet agrega as arestas de uma página, somando a multiplicidade de destinos repetidos.
+type edgeSet struct {
+	source string
+	links  []graph.Link
+	index  map[string]int
+	// seen guarda os destinos já encontrados na página, inclusive os que não
+	// viraram aresta por estarem fora da árvore de busca.
+	seen map[string]struct{}
+}
+
+func newEdgeSet(source string) *edgeSet {
+	return &edgeSet{source: source, links: []graph.Link{}, index: make(map[string]int), seen: make(map[string]struct{})}
+}
+
+// visit registra uma ocorrência de target, retornando falso na primeira vez
+// em que ele aparece na página. Nas seguintes, soma count à aresta, se ela
+// foi registrada.
+func (s *edgeSet) visit(target string, count int) bool {
+	if _, ok := s.seen[target]; !ok {
+		s.seen[target] = struct{}{}
+		return false
+	}
+	if i, exists := s.index[target]; exists {
+		s.links[i].Count += count
+	}
+	return true
+}
+
+func (s *edgeSet) add(target string, count int) {
+	s.index[target] = len(s.links)
+	s.links = append(s.links, graph.NewCountedLink(s.source, target, count))
+}
@@ -0,0 +1,559 @@
+package graph
+
+import (
+	"math"
+	"slices"
+	"sort"
+	"time"
+)
+
+const (
+	defaultDamping            = 0.85
+	defaultMaxIterations      = 100
+	defaultTolerance          = 1e-9
+	defaultKeyPages           = 10
+	defaultBetweennessSamples = 500
+	exactBetweennessLimit     = 5000
+)
+
+// Options controla os parâmetros dos algoritmos de análise.
+type Options struct {
+	// Home é a página usada como raiz para a profundidade de cliques.
+	// Quando vazia, usa o nó de menor profundidade do grafo.
+	Home string
+	// Damping é o fator de amortecimento do PageRank.
+	Damping float64
+	// MaxIterations limita as iterações do PageRank e do HITS.
+	MaxIterations int
+	// Tolerance é o critério de convergência do PageRank e do HITS.
+	Tolerance float64
+	// KeyPages é a quantidade de páginas, ordenadas por PageRank, que recebem betweenness.
+	KeyPages int
+	// BetweennessSamples limita as origens usadas no cálculo de betweenness
+	// em grafos grandes. Zero usa o valor padrão.
+	BetweennessSamples int
+}
+
+// NodeMetrics agrupa as métricas calculadas para um nó.
+type NodeMetrics struct {
+	ID          string   `json:"id,omitempty"`
+	InDegree    int      `json:"inDegree"`
+	OutDegree   int      `json:"outDegree"`
+	PageRank    float64  `json:"pageRank"`
+	Hub         float64  `json:"hub"`
+	Authority   float64  `json:"authority"`
+	Component   int      `json:"component"`
+	ClickDepth  int      `json:"clickDepth"`
+	Orphan      bool     `json:"orphan"`
+	DeadEnd     bool     `json:"deadEnd"`
+	Betweenness *float64 `json:"betweenness,omitempty"`
+}
+
+// Summary resume os números gerais do grafo analisado.
+type Summary struct {
+	Nodes            int `json:"nodes"`
+	Links            int `json:"links"`
+	Components       int `json:"components"`
+	LargestComponent int `json:"largestComponent"`
+	MaxClickDepth    int `json:"maxClickDepth"`
+	Unreachable      int `json:"unreachable"`
+	Orphans          int `json:"orphans"`
+	DeadEnds         int `json:"deadEnds"`
+	External         int `json:"external"`
+	Failed           int `json:"failed"`
+}
+
+// Report é o resultado completo da análise de um grafo.
+type Report struct {
+	GeneratedAt      int64         `json:"generatedAt"`
+	GraphGeneratedAt int64         `json:"graphGeneratedAt"`
+	Home             string        `json:"home"`
+	Summary          Summary       `json:"summary"`
+	Nodes            []NodeMetrics `json:"nodes"`
+	Components       [][]string    `json:"components"`
+	Orphans          []string      `json:"orphans"`
+	DeadEnds         []string      `json:"deadEnds"`
+	KeyPages         []string      `json:"keyPages"`
+}
+
+// index é a representação interna do grafo com nós numerados e listas de adjacência.
+// leaf marca nós externos ou com falha, que por definição não têm links de saída.
+type index struct {
+	ids  []string
+	pos  map[string]int
+	out  [][]int
+	in   [][]int
+	leaf []bool
+}
+
+func buildIndex(g *FinalResponse) *index {
+	idx := &index{pos: make(map[string]int)}
+	add := func(id string) int {
+		if i, ok := idx.pos[id]; ok {
+			return i
+		}
+		idx.pos[id] = len(idx.ids)
+		idx.ids = append(idx.ids, id)
+		idx.out = append(idx.out, nil)
+		idx.in = append(idx.in, nil)
+		idx.leaf = append(idx.leaf, false)
+		return len(idx.ids) - 1
+	}
+
+	for _, n := range g.Nodes {
+		i := add(n.ID)
+		idx.leaf[i] = n.HasTag(TagExternal) || n.HasTag(TagError)
+	}
+
+	seen := make(map[[2]int]struct{})
+	for _, l := range g.Links {
+		s, t := add(l.Source), add(l.Target)
+		if s == t {
+			continue
+		}
+		key := [2]int{s, t}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		idx.out[s] = append(idx.out[s], t)
+		idx.in[t] = append(idx.in[t], s)
+	}
+	return idx
+}
+
+// Analyze calcula as métricas de grafo para o resultado de um crawling.
+func Analyze(g *FinalResponse, opts Options) *Report {
+	opts = withDefaults(opts)
+	idx := buildIndex(g)
+	n := len(idx.ids)
+
+	report := &Report{
+		GeneratedAt:      time.Now().UTC().UnixMilli(),
+		GraphGeneratedAt: g.GeneratedAt,
+		Home:             findHome(g, opts.Home),
+		Nodes:            make([]NodeMetrics, n),
+		Components:       [][]string{},
+		Orphans:          []string{},
+		DeadEnds:         []string{},
+		KeyPages:         []string{},
+	}
+	report.Summary.Nodes = n
+	for _, node := range g.Nodes {
+		if node.HasTag(TagExternal) {
+			report.Summary.External++
+		}
+		if node.HasTag(TagError) {
+			report.Summary.Failed++
+		}
+	}
+	for i := range idx.out {
+		report.Summary.Links += len(idx.out[i])
+	}
+	if n == 0 {
+		return report
+	}
+
+	ranks := pageRank(idx, opts)
+	hubs, authorities := hits(idx, opts)
+	components := stronglyConnectedComponents(idx)
+	depths := clickDepths(idx, report.Home)
+
+	componentOf := make([]int, n)
+	for c, members := range components {
+		ids := make([]string, len(members))
+		for i, m := range members {
+			componentOf[m] = c
+			ids[i] = idx.ids[m]
+		}
+		sort.Strings(ids)
+		report.Components = append(report.Components, ids)
+	}
+	report.Summary.Components = len(components)
+	report.Summary.LargestComponent = len(components[0])
+
+	for i, id := range idx.ids {
+		m := NodeMetrics{
+			ID:         id,
+			InDegree:   len(idx.in[i]),
+			OutDegree:  len(idx.out[i]),
+			PageRank:   ranks[i],
+			Hub:        hubs[i],
+			Authority:  authorities[i],
+			Component:  componentOf[i],
+			ClickDepth: depths[i],
+		}
+		m.Orphan = m.InDegree == 0 && id != report.Home
+		m.DeadEnd = m.OutDegree == 0 && !idx.leaf[i]
+
+		if m.Orphan {
+			report.Orphans = append(report.Orphans, id)
+		}
+		if m.DeadEnd {
+			report.DeadEnds = append(report.DeadEnds, id)
+		}
+		if m.ClickDepth < 0 {
+			report.Summary.Unreachable++
+		} else if m.ClickDepth > report.Summary.MaxClickDepth {
+			report.Summary.MaxClickDepth = m.ClickDepth
+		}
+		report.Nodes[i] = m
+	}
+	report.Summary.Orphans = len(report.Orphans)
+	report.Summary.DeadEnds = len(report.DeadEnds)
+
+	keyPages := topByPageRank(ranks, opts.KeyPages)
+	centrality := betweenness(idx, opts.BetweennessSamples)
+	for _, k := range keyPages {
+		value := centrality[k]
+		report.Nodes[k].Betweenness = &value
+		report.KeyPages = append(report.KeyPages, idx.ids[k])
+	}
+
+	sort.SliceStable(report.Nodes, func(a, b int) bool {
+		return report.Nodes[a].PageRank > report.Nodes[b].PageRank
+	})
+	sort.Strings(report.Orphans)
+	sort.Strings(report.DeadEnds)
+
+	return report
+}
+
+// Annotate copia as métricas do relatório para os nós do grafo.
+func Annotate(g *FinalResponse, r *Report) {
+	metrics := make(map[string]NodeMetrics, len(r.Nodes))
+	for _, m := range r.Nodes {
+		metrics[m.ID] = m
+	}
+	for i := range g.Nodes {
+		m, ok := metrics[g.Nodes[i].ID]
+		if !ok {
+			continue
+		}
+		m.ID = ""
+		g.Nodes[i].Metrics = &m
+	}
+}
+
+// SaveReport grava o relatório de análise em formato JSON.
+func SaveReport(r *Report, filename string) error {
+	return writeJSON(r, filename)
+}
+
+func withDefaults(opts Options) Options {
+	if opts.Damping <= 0 || opts.Damping >= 1 {
+		opts.Damping = defaultDamping
+	}
+	if opts.MaxIterations <= 0 {
+		opts.MaxIterations = defaultMaxIterations
+	}
+	if opts.Tolerance <= 0 {
+		opts.Tolerance = defaultTolerance
+	}
+	if opts.KeyPages <= 0 {
+		opts.KeyPages = defaultKeyPages
+	}
+	if opts.BetweennessSamples <= 0 {
+		opts.BetweennessSamples = defaultBetweennessSamples
+	}
+	return opts
+}
+
+func findHome(g *FinalResponse, home string) string {
+	if home != "" {
+		return home
+	}
+	best := ""
+	bestDepth := math.MaxInt
+	for _, n := range g.Nodes {
+		if n.Depth < bestDepth {
+			best, bestDepth = n.ID, n.Depth
+		}
+	}
+	if best == "" && len(g.Links) > 0 {
+		best = g.Links[0].Source
+	}
+	return best
+}
+
+// pageRank calcula o PageRank de cada nó, redistribuindo a massa dos nós sem saída.
+func pageRank(idx *index, opts Options) []float64 {
+	n := len(idx.ids)
+	rank := make([]float64, n)
+	next := make([]float64, n)
+	for i := range rank {
+		rank[i] = 1 / float64(n)
+	}
+
+	for iter := 0; iter < opts.MaxIterations; iter++ {
+		dangling := 0.0
+		for i := range rank {
+			if len(idx.out[i]) == 0 {
+				dangling += rank[i]
+			}
+		}
+		base := (1-opts.Damping)/float64(n) + opts.Damping*dangling/float64(n)
+		for i := range next {
+			next[i] = base
+		}
+		for i, targets := range idx.out {
+			if len(targets) == 0 {
+				continue
+			}
+			share := opts.Damping * rank[i] / float64(len(targets))
+			for _, t := range targets {
+				next[t] += share
+			}
+		}
+
+		diff := 0.0
+		for i := range rank {
+			diff += math.Abs(next[i] - rank[i])
+		}
+		rank, next = next, rank
+		if diff < opts.Tolerance {
+			break
+		}
+	}
+	return rank
+}
+
+// hits calcula as pontuações de hub e autoridade de cada nó.
+func hits(idx *index, opts Options) (hubs, authorities []float64) {
+	n := len(idx.ids)
+	hubs = make([]float64, n)
+	authorities = make([]float64, n)
+	for i := range hubs {
+		hubs[i] = 1
+		authorities[i] = 1
+	}
+
+	for iter := 0; iter < opts.MaxIterations; iter++ {
+		nextAuth := make([]float64, n)
+		for i := range idx.in {
+			for _, s := range idx.in[i] {
+				nextAuth[i] += hubs[s]
+			}
+		}
+		normalize(nextAuth)
+
+		nextHubs := make([]float64, n)
+		for i := range idx.out {
+			for _, t := range idx.out[i] {
+				nextHubs[i] += nextAuth[t]
+			}
+		}
+		normalize(nextHubs)
+
+		diff := 0.0
+		for i := range hubs {
+			diff += math.Abs(nextHubs[i]-hubs[i]) + math.Abs(nextAuth[i]-authorities[i])
+		}
+		hubs, authorities = nextHubs, nextAuth
+		if diff < opts.Tolerance {
+			break
+		}
+	}
+	return hubs, authorities
+}
+
+func normalize(v []float64) {
+	sum := 0.0
+	for _, x := range v {
+		sum += x * x
+	}
+	if sum == 0 {
+		return
+	}
+	norm := math.Sqrt(sum)
+	for i := range v {
+		v[i] /= norm
+	}
+}
+
+// stronglyConnectedComponents aplica o algoritmo de Tarjan de forma iterativa,
+// retornando os componentes ordenados do maior para o menor.
+func stronglyConnectedComponents(idx *index) [][]int {
+	n := len(idx.ids)
+	order := make([]int, n)
+	low := make([]int, n)
+	onStack := make([]bool, n)
+	for i := range order {
+		order[i] = -1
+	}
+
+	var components [][]int
+	var stack []int
+	counter := 0
+
+	type frame struct{ node, edge int }
+	for root := 0; root < n; root++ {
+		if order[root] != -1 {
+			continue
+		}
+		call := []frame{{node: root}}
+		order[root], low[root] = counter, counter
+		counter++
+		stack = append(stack, root)
+		onStack[root] = true
+
+		for len(call) > 0 {
+			top := &call[len(call)-1]
+			v := top.node
+			if top.edge < len(idx.out[v]) {
+				w := idx.out[v][top.edge]
+				top.edge++
+				if order[w] == -1 {
+					order[w], low[w] = counter, counter
+					counter++
+					stack = append(stack, w)
+					onStack[w] = true
+					call = append(call, frame{node: w})
+				} else if onStack[w] {
+					low[v] = min(low[v], order[w])
+				}
+				continue
+			}
+
+			if low[v] == order[v] {
+				var component []int
+				for {
+					w := stack[len(stack)-1]
+					stack = stack[:len(stack)-1]
+					onStack[w] = false
+					component = append(component, w)
+					if w == v {
+						break
+					}
+				}
+				components = append(components, component)
+			}
+			call = call[:len(call)-1]
+			if len(call) > 0 {
+				parent := call[len(call)-1].node
+				low[parent] = min(low[parent], low[v])
+			}
+		}
+	}
+
+	sort.SliceStable(components, func(a, b int) bool {
+		return len(components[a]) > len(components[b])
+	})
+	return components
+}
+
+// clickDepths calcula a distância em cliques a partir da página inicial.
+// Nós inalcançáveis recebem -1.
+func clickDepths(idx *index, home string) []int {
+	depths := make([]int, len(idx.ids))
+	for i := range depths {
+		depths[i] = -1
+	}
+	start, ok := idx.pos[home]
+	if !ok {
+		return depths
+	}
+
+	depths[start] = 0
+	queue := []int{start}
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+		for _, w := range idx.out[v] {
+			if depths[w] == -1 {
+				depths[w] = depths[v] + 1
+				queue = append(queue, w)
+			}
+		}
+	}
+	return depths
+}
+
+// betweenness calcula a centralidade de intermediação pelo algoritmo de Brandes.
+// Em grafos maiores que exactBetweennessLimit, usa apenas samples origens
+// distribuídas uniformemente e escala o resultado.
+func betweenness(idx *index, samples int) []float64 {
+	n := len(idx.ids)
+	centrality := make([]float64, n)
+
+	sources := make([]int, 0, n)
+	if n <= exactBetweennessLimit || samples >= n {
+		for i := 0; i < n; i++ {
+			sources = append(sources, i)
+		}
+	} else {
+		step := float64(n) / float64(samples)
+		for i := 0; i < samples; i++ {
+			sources = append(sources, int(float64(i)*step))
+		}
+	}
+
+	sigma := make([]float64, n)
+	dist := make([]int, n)
+	delta := make([]float64, n)
+	preds := make([][]int, n)
+
+	for _, s := range sources {
+		for i := 0; i < n; i++ {
+			sigma[i], dist[i], delta[i] = 0, -1, 0
+			preds[i] = preds[i][:0]
+		}
+		sigma[s], dist[s] = 1, 0
+
+		var visited []int
+		queue := []int{s}
+		for len(queue) > 0 {
+			v := queue[0]
+			queue = queue[1:]
+			visited = append(visited, v)
+			for _, w := range idx.out[v] {
+				if dist[w] < 0 {
+					dist[w] = dist[v] + 1
+					queue = append(queue, w)
+				}
+				if dist[w] == dist[v]+1 {
+					sigma[w] += sigma[v]
+					preds[w] = append(preds[w], v)
+				}
+			}
+		}
+
+		for i := len(visited) - 1; i >= 0; i-- {
+			w := visited[i]
+			for _, v := range preds[w] {
+				delta[v] += sigma[v] / sigma[w] * (1 + delta[w])
+			}
+			if w != s {
+				centrality[w] += delta[w]
+			}
+		}
+	}
+
+	if len(sources) < n {
+		scale := float64(n) / float64(len(sources))
+		for i := range centrality {
+			centrality[i] *= scale
+		}
+	}
+	return centrality
+}
+
+func topByPageRank(rank []float64, k int) []int {
+	order := make([]int, len(rank))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		switch {
+		case rank[a] > rank[b]:
+			return -1
+		case rank[a] < rank[b]:
+			return 1
+		}
+		return 0
+	})
+	if k < len(order) {
+		order = order[:k]
+	}
+	return order
+}
@@ -0,0 +1,163 @@
+package graph
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func sampleGraph() *FinalResponse {
+	return &FinalResponse{
+		Nodes: []Node{
+			{ID: "home", Depth: 1},
+			{ID: "a", Depth: 2},
+			{ID: "b", Depth: 2},
+			{ID: "c", Depth: 3},
+			{ID: "orphan", Depth: 4},
+		},
+		Links: []Link{
+			NewLink("home", "a"),
+			NewLink("home", "b"),
+			NewLink("a", "b"),
+			NewLink("b", "home"),
+			NewLink("b", "c"),
+			NewLink("b", "c"),
+			NewLink("orphan", "a"),
+		},
+	}
+}
+
+func metricsByID(r *Report) map[string]NodeMetrics {
+	out := make(map[string]NodeMetrics, len(r.Nodes))
+	for _, m := range r.Nodes {
+		out[m.ID] = m
+	}
+	return out
+}
+
+func TestAnalyze(t *testing.T) {
+	report := Analyze(sampleGraph(), Options{})
+	metrics := metricsByID(report)
+
+	t.Run("degrees ignore duplicated edges", func(t *testing.T) {
+		if report.Summary.Links != 6 {
+			t.Errorf("expected 6 unique links, got %d", report.Summary.Links)
+		}
+		if m := metrics["b"]; m.InDegree != 2 || m.OutDegree != 2 {
+			t.Errorf("unexpected degrees for b: in=%d out=%d", m.InDegree, m.OutDegree)
+		}
+	})
+
+	t.Run("home is the shallowest node", func(t *testing.T) {
+		if report.Home != "home" {
+			t.Errorf("expected home %q, got %q", "home", report.Home)
+		}
+	})
+
+	t.Run("click depth from home", func(t *testing.T) {
+		expected := map[string]int{"home": 0, "a": 1, "b": 1, "c": 2, "orphan": -1}
+		for id, depth := range expected {
+			if metrics[id].ClickDepth != depth {
+				t.Errorf("expected click depth %d for %s, got %d", depth, id, metrics[id].ClickDepth)
+			}
+		}
+		if report.Summary.Unreachable != 1 {
+			t.Errorf("expected 1 unreachable node, got %d", report.Summary.Unreachable)
+		}
+	})
+
+	t.Run("orphans and dead ends", func(t *testing.T) {
+		if !slices.Equal(report.Orphans, []string{"orphan"}) {
+			t.Errorf("unexpected orphans: %v", report.Orphans)
+		}
+		if !slices.Equal(report.DeadEnds, []string{"c"}) {
+			t.Errorf("unexpected dead ends: %v", report.DeadEnds)
+		}
+	})
+
+	t.Run("strongly connected components", func(t *testing.T) {
+		if report.Summary.Components != 3 {
+			t.Fatalf("expected 3 components, got %d: %v", report.Summary.Components, report.Components)
+		}
+		if !slices.Equal(report.Components[0], []string{"a", "b", "home"}) {
+			t.Errorf("unexpected largest component: %v", report.Components[0])
+		}
+	})
+
+	t.Run("pagerank sums to one", func(t *testing.T) {
+		sum := 0.0
+		for _, m := range report.Nodes {
+			sum += m.PageRank
+		}
+		if math.Abs(sum-1) > 1e-6 {
+			t.Errorf("expected pagerank to sum to 1, got %f", sum)
+		}
+		if metrics["orphan"].PageRank >= metrics["b"].PageRank {
+			t.Errorf("expected b to outrank orphan")
+		}
+	})
+
+	t.Run("hits scores", func(t *testing.T) {
+		if metrics["b"].Authority <= metrics["orphan"].Authority {
+			t.Errorf("expected b to be a stronger authority than orphan")
+		}
+		if metrics["c"].Hub != 0 {
+			t.Errorf("expected dead end c to have zero hub score, got %f", metrics["c"].Hub)
+		}
+	})
+
+	t.Run("betweenness only for key pages", func(t *testing.T) {
+		limited := Analyze(sampleGraph(), Options{KeyPages: 2})
+		withValue := 0
+		for _, m := range limited.Nodes {
+			if m.Betweenness != nil {
+				withValue++
+			}
+		}
+		if withValue != 2 || len(limited.KeyPages) != 2 {
+			t.Errorf("expected betweenness for 2 key pages, got %d", withValue)
+		}
+		if b := metrics["b"].Betweenness; b == nil || *b <= 0 {
+			t.Errorf("expected b to have positive betweenness")
+		}
+	})
+}
+
+func TestAnnotate(t *testing.T) {
+	g := sampleGraph()
+	Annotate(g, Analyze(g, Options{}))
+
+	for _, n := range g.Nodes {
+		if n.Metrics == nil {
+			t.Fatalf("expected metrics for node %s", n.ID)
+		}
+		if n.Metrics.ID != "" {
+			t.Errorf("expected annotated metrics to omit the id, got %q", n.Metrics.ID)
+		}
+	}
+}
+
+func TestAnalyzeEmptyGraph(t *testing.T) {
+	report := Analyze(&FinalResponse{}, Options{})
+	if report.Summary.Nodes != 0 || len(report.Nodes) != 0 {
+		t.Errorf("expected empty report, got %+v", report.Summary)
+	}
+}
+
+func TestAnalyzeTaggedLeaves(t *testing.T) {
+	g := sampleGraph()
+	g.Nodes = append(g.Nodes,
+		Node{ID: "https://external.example", Depth: 3, Tags: []string{TagExternal}},
+		Node{ID: "broken", Depth: 3, Tags: []string{TagError}, ErrorClass: "timeout"},
+	)
+	g.Links = append(g.Links, NewLink("a", "https://external.example"), NewLink("a", "broken"))
+
+	report := Analyze(g, Options{})
+
+	if !slices.Equal(report.DeadEnds, []string{"c"}) {
+		t.Errorf("expected external and failed nodes to be excluded from dead ends, got %v", report.DeadEnds)
+	}
+	if report.Summary.External != 1 || report.Summary.Failed != 1 {
+		t.Errorf("unexpected summary counters: %+v", report.Summary)
+	}
+}
@@ -0,0 +1,98 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+)
+
+// FinalResponse é o grafo produzido pelo extractor e salvo em disco.
+type FinalResponse struct {
+	Nodes       []Node `json:"nodes"`
+	Links       []Link `json:"links"`
+	GeneratedAt int64  `json:"generatedAt"`
+}
+
+const (
+	// TagExternal marca destinos fora dos domínios permitidos, que não são visitados.
+	TagExternal = "external"
+	// TagError marca páginas cuja busca falhou.
+	TagError = "error"
+)
+
+// Node representa uma página encontrada durante o crawling.
+type Node struct {
+	ID          string       `json:"id"`
+	Depth       int          `json:"depth"`
+	StatusCode  int          `json:"statusCode"`
+	ContentType string       `json:"contentType"`
+	ElapsedTime int64        `json:"elapsedTime"`
+	Title       string       `json:"title"`
+	Domain      string       `json:"domain"`
+	ContentHash string       `json:"contentHash,omitempty"`
+	Tags        []string     `json:"tags,omitempty"`
+	Error       string       `json:"error,omitempty"`
+	ErrorClass  string       `json:"errorClass,omitempty"`
+	Metrics     *NodeMetrics `json:"metrics,omitempty"`
+}
+
+// HasTag informa se o nó possui a tag informada.
+func (n Node) HasTag(tag string) bool {
+	return slices.Contains(n.Tags, tag)
+}
+
+// Link representa uma aresta dirigida entre duas páginas.
+// Count indica quantas âncoras da origem apontam para o destino.
+type Link struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Count  int    `json:"count,omitempty"`
+}
+
+// NewLink cria uma aresta entre source e target.
+func NewLink(source, target string) Link {
+	return Link{
+		Source: source,
+		Target: target,
+	}
+}
+
+// NewCountedLink cria uma aresta entre source e target com multiplicidade count.
+func NewCountedLink(source, target string, count int) Link {
+	return Link{
+		Source: source,
+		Target: target,
+		Count:  count,
+	}
+}
+
+// Load lê um grafo salvo em formato JSON.
+func Load(filename string) (*FinalResponse, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read graph file %s: %w", filename, err)
+	}
+
+	var g FinalResponse
+	if err := json.Unmarshal(data, &g); err != nil {
+		return nil, fmt.Errorf("failed to decode graph file %s: %w", filename, err)
+	}
+	return &g, nil
+}
+
+// Save grava o grafo em formato JSON indentado.
+func Save(g *FinalResponse, filename string) error {
+	return writeJSON(g, filename)
+}
+
+func writeJSON(v any, filename string) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", filename, err)
+	}
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filename, err)
+	}
+	return nil
+}
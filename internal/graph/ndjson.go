@@ -0,0 +1,233 @@
+package graph
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// RecordHeader abre o fluxo e carrega o instante de geração do grafo.
+	RecordHeader = "header"
+	// RecordNode carrega um nó do grafo.
+	RecordNode = "node"
+	// RecordLink carrega uma aresta do grafo.
+	RecordLink = "link"
+
+	defaultFlushEvery    = 100
+	defaultFlushInterval = 2 * time.Second
+)
+
+// Record é uma linha do fluxo NDJSON gerado durante o crawling.
+type Record struct {
+	Type        string `json:"type"`
+	GeneratedAt int64  `json:"generatedAt,omitempty"`
+	Node        *Node  `json:"node,omitempty"`
+	Link        *Link  `json:"link,omitempty"`
+}
+
+// StreamOptions controla a frequência de descarga do StreamWriter.
+type StreamOptions struct {
+	// FlushEvery descarrega o buffer a cada N registros. Zero usa o valor padrão.
+	FlushEvery int
+	// FlushInterval descarrega o buffer periodicamente. Zero usa o valor padrão.
+	FlushInterval time.Duration
+}
+
+// StreamWriter grava nós e arestas em NDJSON assim que são produzidos.
+// É seguro para uso concorrente.
+type StreamWriter struct {
+	mu         sync.Mutex
+	buf        *bufio.Writer
+	enc        *json.Encoder
+	closer     io.Closer
+	pending    int
+	flushEvery int
+	err        error
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// NewStreamWriter cria um StreamWriter sobre w. Se w implementar io.Closer,
+// ele será fechado por Close.
+func NewStreamWriter(w io.Writer, opts StreamOptions) *StreamWriter {
+	if opts.FlushEvery <= 0 {
+		opts.FlushEvery = defaultFlushEvery
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = defaultFlushInterval
+	}
+
+	buf := bufio.NewWriter(w)
+	s := &StreamWriter{
+		buf:        buf,
+		enc:        json.NewEncoder(buf),
+		flushEvery: opts.FlushEvery,
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	if c, ok := w.(io.Closer); ok {
+		s.closer = c
+	}
+
+	go s.flushPeriodically(opts.FlushInterval)
+	return s
+}
+
+// CreateStream cria o arquivo filename e retorna um StreamWriter sobre ele.
+func CreateStream(filename string, opts StreamOptions) (*StreamWriter, error) {
+	f, err := os.Create(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stream file %s: %w", filename, err)
+	}
+	return NewStreamWriter(f, opts), nil
+}
+
+// WriteHeader grava o registro inicial com o instante de geração do grafo.
+func (s *StreamWriter) WriteHeader(generatedAt int64) error {
+	return s.write(Record{Type: RecordHeader, GeneratedAt: generatedAt})
+}
+
+// WriteNode grava um nó do grafo.
+func (s *StreamWriter) WriteNode(n Node) error {
+	return s.write(Record{Type: RecordNode, Node: &n})
+}
+
+// WriteLink grava uma aresta do grafo.
+func (s *StreamWriter) WriteLink(l Link) error {
+	return s.write(Record{Type: RecordLink, Link: &l})
+}
+
+// Flush descarrega os registros pendentes no destino.
+func (s *StreamWriter) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.flushLocked()
+}
+
+// Close interrompe a descarga periódica, descarrega os registros pendentes e fecha o destino.
+// As chamadas seguintes não fazem nada e retornam o erro da primeira.
+func (s *StreamWriter) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.stop)
+		<-s.done
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.closeErr = s.flushLocked()
+		if s.closer != nil {
+			if cerr := s.closer.Close(); s.closeErr == nil {
+				s.closeErr = cerr
+			}
+		}
+	})
+	return s.closeErr
+}
+
+func (s *StreamWriter) write(r Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	if err := s.enc.Encode(r); err != nil {
+		s.err = fmt.Errorf("failed to encode %s record: %w", r.Type, err)
+		return s.err
+	}
+	s.pending++
+	if s.pending >= s.flushEvery {
+		return s.flushLocked()
+	}
+	return nil
+}
+
+func (s *StreamWriter) flushLocked() error {
+	if s.err != nil {
+		return s.err
+	}
+	if err := s.buf.Flush(); err != nil {
+		s.err = fmt.Errorf("failed to flush stream: %w", err)
+		return s.err
+	}
+	s.pending = 0
+	return nil
+}
+
+func (s *StreamWriter) flushPeriodically(interval time.Duration) {
+	defer close(s.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			_ = s.Flush()
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// ReadStream agrega um fluxo NDJSON no grafo equivalente. Registros repetidos
+// de um mesmo nó substituem os anteriores.
+func ReadStream(r io.Reader) (*FinalResponse, error) {
+	g := &FinalResponse{Nodes: []Node{}, Links: []Link{}}
+	positions := make(map[string]int)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("failed to decode record at line %d: %w", line, err)
+		}
+
+		switch rec.Type {
+		case RecordHeader:
+			g.GeneratedAt = rec.GeneratedAt
+		case RecordNode:
+			if rec.Node == nil {
+				return nil, fmt.Errorf("node record without node at line %d", line)
+			}
+			if i, ok := positions[rec.Node.ID]; ok {
+				g.Nodes[i] = *rec.Node
+				continue
+			}
+			positions[rec.Node.ID] = len(g.Nodes)
+			g.Nodes = append(g.Nodes, *rec.Node)
+		case RecordLink:
+			if rec.Link == nil {
+				return nil, fmt.Errorf("link record without link at line %d", line)
+			}
+			g.Links = append(g.Links, *rec.Link)
+		default:
+			return nil, fmt.Errorf("unknown record type %q at line %d", rec.Type, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read stream: %w", err)
+	}
+	return g, nil
+}
+
+// LoadStream lê um arquivo NDJSON e retorna o grafo agregado.
+func LoadStream(filename string) (*FinalResponse, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open stream file %s: %w", filename, err)
+	}
+	defer f.Close()
+	return ReadStream(f)
+}
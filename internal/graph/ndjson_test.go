@@ -0,0 +1,116 @@
+package graph
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStreamRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewStreamWriter(&buf, StreamOptions{FlushEvery: 1})
+
+	if err := w.WriteHeader(42); err != nil {
+		t.Fatalf("WriteHeader() returned an unexpected error: %v", err)
+	}
+	_ = w.WriteNode(Node{ID: "home", Depth: 1, StatusCode: 200})
+	_ = w.WriteLink(NewCountedLink("home", "a", 2))
+	_ = w.WriteNode(Node{ID: "a", Depth: 2, Tags: []string{TagError}})
+	_ = w.WriteNode(Node{ID: "a", Depth: 2, StatusCode: 200})
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() returned an unexpected error: %v", err)
+	}
+
+	if lines := strings.Count(buf.String(), "\n"); lines != 5 {
+		t.Fatalf("expected 5 ndjson lines, got %d", lines)
+	}
+
+	g, err := ReadStream(&buf)
+	if err != nil {
+		t.Fatalf("ReadStream() returned an unexpected error: %v", err)
+	}
+	if g.GeneratedAt != 42 {
+		t.Errorf("expected generatedAt 42, got %d", g.GeneratedAt)
+	}
+	if len(g.Nodes) != 2 {
+		t.Fatalf("expected 2 nodes, got %d", len(g.Nodes))
+	}
+	if g.Nodes[1].StatusCode != 200 || g.Nodes[1].HasTag(TagError) {
+		t.Errorf("expected the last record of a node to win, got %+v", g.Nodes[1])
+	}
+	if len(g.Links) != 1 || g.Links[0].Count != 2 {
+		t.Errorf("unexpected links: %+v", g.Links)
+	}
+}
+
+func TestStreamWriterPeriodicFlush(t *testing.T) {
+	var buf safeBuffer
+	w := NewStreamWriter(&buf, StreamOptions{FlushEvery: 1000, FlushInterval: 10 * time.Millisecond})
+	defer w.Close()
+
+	_ = w.WriteNode(Node{ID: "home"})
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if strings.Contains(buf.String(), `"home"`) {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("expected the record to be flushed by the periodic flush")
+}
+
+func TestReadStreamInvalidRecord(t *testing.T) {
+	_, err := ReadStream(strings.NewReader(`{"type":"node","node":{"id":"a"}}` + "\n" + `{"type":"unknown"}` + "\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("expected an error pointing to line 2, got %v", err)
+	}
+}
+
+func TestStreamWriterCloseTwice(t *testing.T) {
+	closeErr := errors.New("disk full")
+	dst := &failingCloser{err: closeErr}
+	w := NewStreamWriter(dst, StreamOptions{})
+	_ = w.WriteNode(Node{ID: "home"})
+
+	if err := w.Close(); !errors.Is(err, closeErr) {
+		t.Fatalf("expected the close error, got %v", err)
+	}
+	if err := w.Close(); !errors.Is(err, closeErr) {
+		t.Errorf("expected a second Close to return the first error, got %v", err)
+	}
+	if dst.closes != 1 {
+		t.Errorf("expected the destination to be closed once, got %d", dst.closes)
+	}
+}
+
+type failingCloser struct {
+	bytes.Buffer
+	err    error
+	closes int
+}
+
+func (c *failingCloser) Close() error {
+	c.closes++
+	return c.err
+}
+
+type safeBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *safeBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *safeBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
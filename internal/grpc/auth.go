@@ -0,0 +1,75 @@
+package grpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/nettojulio/ufape-crawler-golang/internal/auth"
+	"github.com/nettojulio/ufape-crawler-golang/internal/grpc/crawlerv1"
+)
+
+// methodScopes mapeia os métodos do CrawlerService aos escopos exigidos. Os
+// demais serviços, como health checking e reflection, não exigem chave.
+var methodScopes = map[string]auth.Scope{
+	crawlerv1.CrawlerService_Crawl_FullMethodName:     auth.ScopeCrawl,
+	crawlerv1.CrawlerService_CrawlSite_FullMethodName: auth.ScopeJobs,
+}
+
+// authenticate valida a chave enviada nos metadados x-api-key ou
+// authorization e retorna ctx com o cliente autenticado.
+func authenticate(ctx context.Context, store *auth.Store, method string) (context.Context, error) {
+	scope, ok := methodScopes[method]
+	if !ok {
+		return ctx, nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	first := func(key string) string {
+		if values := md.Get(key); len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+	key, err := store.Authenticate(auth.TokenFromHeaders(first("x-api-key"), first("authorization")))
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if !key.Allows(scope) {
+		return nil, status.Error(codes.PermissionDenied, auth.ErrForbidden.Error())
+	}
+	return auth.WithCaller(ctx, store, key), nil
+}
+
+func unaryAuthInterceptor(store *auth.Store) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx, store, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func streamAuthInterceptor(store *auth.Store) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), store, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authenticatedStream troca o contexto do stream pelo que carrega o cliente.
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,252 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/nettojulio/ufape-crawler-golang/internal/auth"
+	"github.com/nettojulio/ufape-crawler-golang/internal/crawler"
+	"github.com/nettojulio/ufape-crawler-golang/internal/extractor"
+	"github.com/nettojulio/ufape-crawler-golang/internal/grpc/crawlerv1"
+	"github.com/nettojulio/ufape-crawler-golang/internal/live"
+	"github.com/nettojulio/ufape-crawler-golang/internal/logging"
+	"github.com/nettojulio/ufape-crawler-golang/internal/storage"
+)
+
+type crawlerServer struct {
+	crawlerv1.UnimplementedCrawlerServiceServer
+
+	service    crawler.Crawler
+	repository storage.Repository
+	jobs       *live.Manager
+	validate   *validator.Validate
+	logger     *slog.Logger
+}
+
+// Crawl busca uma página com a mesma semântica do POST /.
+func (s *crawlerServer) Crawl(ctx context.Context, req *crawlerv1.CrawlRequest) (*crawlerv1.CrawlResponse, error) {
+	payload := payloadFromRequest(req)
+	if err := s.validate.Struct(&payload); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	originalURL, err := url.Parse(payload.Url)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid url")
+	}
+
+	if err := auth.CallerFromContext(ctx).Charge(crawler.Attempts(payload)); err != nil {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
+
+	modifiedURL := crawler.PrepareURLAndDefaults(&payload, originalURL)
+	ctx = logging.WithContext(ctx, s.logger.With("rpc", "Crawl", "url", payload.Url))
+
+	result, err := crawler.CrawlWithRetry(ctx, s.service, payload, originalURL, modifiedURL, crawler.CurrentDefaults().RetryDelay)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+
+	s.recordFetch(ctx, modifiedURL, result)
+
+	dto := crawler.NewResponseDTO(result, originalURL)
+	dto.AttachBody(result, payload)
+	return responseFromDTO(&dto), nil
+}
+
+// CrawlSite inicia um crawling de várias páginas, como o POST /crawls, e envia
+// os eventos de progresso até o fim. Se o cliente encerrar a chamada, o
+// crawling é cancelado.
+func (s *crawlerServer) CrawlSite(req *crawlerv1.CrawlSiteRequest, stream crawlerv1.CrawlerService_CrawlSiteServer) error {
+	if s.jobs == nil {
+		return status.Error(codes.Unimplemented, "multi-page crawls are disabled")
+	}
+
+	jobReq := live.JobRequest{
+		SeedURL:        req.GetSeedUrl(),
+		MaxDepth:       int(req.GetMaxDepth()),
+		FullGraph:      req.FullGraph,
+		AllowedDomains: req.GetAllowedDomains(),
+		Session:        req.GetSession(),
+	}
+	if err := s.validate.Struct(&jobReq); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if caller := auth.CallerFromContext(stream.Context()); caller != nil {
+		if err := caller.Charge(0); err != nil {
+			return status.Error(codes.ResourceExhausted, err.Error())
+		}
+		jobReq.Owner = caller.Owner()
+		jobReq.Charge = caller.Charge
+	}
+
+	job, err := s.jobs.Start(jobReq)
+	if errors.Is(err, live.ErrTooManyJobs) {
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	events, unsubscribe, err := s.jobs.Subscribe(job.ID, job.Owner, 0)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	defer unsubscribe()
+
+	ctx := stream.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			_ = s.jobs.Cancel(job.ID, job.Owner)
+			return status.FromContextError(ctx.Err()).Err()
+		case msg, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if err := stream.Send(eventFromMessage(job.ID, msg)); err != nil {
+				_ = s.jobs.Cancel(job.ID, job.Owner)
+				return err
+			}
+		}
+	}
+}
+
+// recordFetch grava a busca no repositório, sem falhar a chamada em caso de erro.
+func (s *crawlerServer) recordFetch(ctx context.Context, requestedURL *url.URL, result *crawler.CrawlResult) {
+	if s.repository == nil {
+		return
+	}
+
+	fetch := storage.Fetch{
+		URL:         requestedURL.String(),
+		FinalURL:    result.FinalURL.String(),
+		StatusCode:  result.StatusCode,
+		ContentType: result.ContentType,
+		ElapsedTime: result.ElapsedTime,
+		ErrorClass:  string(result.ErrorClass),
+		FetchedAt:   time.Now().UTC(),
+	}
+	if result.ErrorClass != "" {
+		fetch.Error = result.Title
+	}
+	if err := s.repository.SaveFetch(ctx, fetch); err != nil {
+		logging.FromContext(ctx, s.logger).ErrorContext(ctx, "failed to record fetch", "error", err)
+	}
+}
+
+// payloadFromRequest converte a requisição no payload da API REST, mantendo
+// nil os campos não informados para que recebam os valores padrão.
+func payloadFromRequest(req *crawlerv1.CrawlRequest) crawler.Payload {
+	payload := crawler.Payload{
+		Url:               req.GetUrl(),
+		Timeout:           optionalInt(req.Timeout),
+		RemoveFragment:    req.RemoveFragment,
+		CollectSubdomains: req.CollectSubdomains,
+		LowerCaseURLs:     req.LowerCaseUrls,
+		CanRetry:          req.CanRetry,
+		MaxAttempts:       optionalInt(req.MaxAttempts),
+		IncludeBody:       req.IncludeBody,
+		MaxBodySize:       optionalInt(req.MaxBodySize),
+		RequestOptions: crawler.RequestOptions{
+			Headers:        req.GetHeaders(),
+			UserAgent:      req.GetUserAgent(),
+			AcceptLanguage: req.GetAcceptLanguage(),
+			Cookies:        req.GetCookies(),
+			Proxy:          req.GetProxy(),
+			Session:        req.GetSession(),
+		},
+	}
+	if basic := req.GetBasicAuth(); basic != nil {
+		payload.BasicAuth = &crawler.BasicAuth{Username: basic.GetUsername(), Password: basic.GetPassword()}
+	}
+	if len(req.GetAllowedDomains()) > 0 {
+		domains := append([]string(nil), req.GetAllowedDomains()...)
+		payload.AllowedDomains = &domains
+	}
+	return payload
+}
+
+func optionalInt(v *int32) *int {
+	if v == nil {
+		return nil
+	}
+	i := int(*v)
+	return &i
+}
+
+func responseFromDTO(dto *crawler.ResponseDTO) *crawlerv1.CrawlResponse {
+	links := &crawlerv1.Links{
+		Available:   dto.Links.Available,
+		Unavailable: dto.Links.Unavailable,
+	}
+	if len(dto.Links.Occurrences) > 0 {
+		links.Occurrences = make(map[string]int32, len(dto.Links.Occurrences))
+		for link, count := range dto.Links.Occurrences {
+			links.Occurrences[link] = int32(count)
+		}
+	}
+	return &crawlerv1.CrawlResponse{
+		StatusCode:    int32(dto.StatusCode),
+		ContentType:   dto.ContentType,
+		Charset:       dto.Charset,
+		ElapsedTime:   durationpb.New(time.Duration(dto.ElapsedTime)),
+		Links:         links,
+		Title:         dto.Title,
+		ErrorClass:    string(dto.ErrorClass),
+		ContentHash:   dto.ContentHash,
+		Body:          dto.Body,
+		BodyEncoding:  dto.BodyEncoding,
+		BodyTruncated: dto.BodyTruncated,
+		CorrectUrl:    dto.Details.CorrectURL,
+	}
+}
+
+func eventFromMessage(crawlID string, msg live.Message) *crawlerv1.CrawlEvent {
+	event := &crawlerv1.CrawlEvent{
+		CrawlId:    crawlID,
+		Seq:        msg.Seq,
+		Type:       string(msg.Type),
+		Time:       timestamppb.New(msg.Time),
+		Url:        msg.URL,
+		Depth:      int32(msg.Depth),
+		StatusCode: int32(msg.StatusCode),
+		Title:      msg.Title,
+		ErrorClass: msg.ErrorClass,
+		Error:      msg.Error,
+		Counters:   countersFromEvent(msg.Counters),
+	}
+	if msg.ElapsedTime > 0 {
+		event.ElapsedTime = durationpb.New(time.Duration(msg.ElapsedTime))
+	}
+	if msg.Links != nil {
+		event.Links = &crawlerv1.LinkCounts{
+			Internal: int32(msg.Links.Internal),
+			New:      int32(msg.Links.New),
+			External: int32(msg.Links.External),
+		}
+	}
+	return event
+}
+
+func countersFromEvent(c extractor.Counters) *crawlerv1.Counters {
+	return &crawlerv1.Counters{
+		Discovered: int32(c.Discovered),
+		Queued:     int32(c.Queued),
+		Fetched:    int32(c.Fetched),
+		Failed:     int32(c.Failed),
+		External:   int32(c.External),
+		Links:      int32(c.Links),
+	}
+}
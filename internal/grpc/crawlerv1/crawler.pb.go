@@ -0,0 +1,1007 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: crawler/v1/crawler.proto
+
+package crawlerv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// CrawlRequest equivale ao payload do POST /. Campos não informados recebem os
+// mesmos valores padrão da API REST; allowed_domains vazio equivale a omitido.
+type CrawlRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Url               string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Timeout           *int32                 `protobuf:"varint,2,opt,name=timeout,proto3,oneof" json:"timeout,omitempty"`
+	RemoveFragment    *bool                  `protobuf:"varint,3,opt,name=remove_fragment,json=removeFragment,proto3,oneof" json:"remove_fragment,omitempty"`
+	AllowedDomains    []string               `protobuf:"bytes,4,rep,name=allowed_domains,json=allowedDomains,proto3" json:"allowed_domains,omitempty"`
+	CollectSubdomains *bool                  `protobuf:"varint,5,opt,name=collect_subdomains,json=collectSubdomains,proto3,oneof" json:"collect_subdomains,omitempty"`
+	LowerCaseUrls     *bool                  `protobuf:"varint,6,opt,name=lower_case_urls,json=lowerCaseUrls,proto3,oneof" json:"lower_case_urls,omitempty"`
+	CanRetry          *bool                  `protobuf:"varint,7,opt,name=can_retry,json=canRetry,proto3,oneof" json:"can_retry,omitempty"`
+	MaxAttempts       *int32                 `protobuf:"varint,8,opt,name=max_attempts,json=maxAttempts,proto3,oneof" json:"max_attempts,omitempty"`
+	// include_body aceita "none", "text" ou "base64".
+	IncludeBody *string `protobuf:"bytes,9,opt,name=include_body,json=includeBody,proto3,oneof" json:"include_body,omitempty"`
+	MaxBodySize *int32  `protobuf:"varint,10,opt,name=max_body_size,json=maxBodySize,proto3,oneof" json:"max_body_size,omitempty"`
+	// Opções da requisição HTTP da busca.
+	Headers        map[string]string `protobuf:"bytes,11,rep,name=headers,proto3" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	UserAgent      *string           `protobuf:"bytes,12,opt,name=user_agent,json=userAgent,proto3,oneof" json:"user_agent,omitempty"`
+	AcceptLanguage *string           `protobuf:"bytes,13,opt,name=accept_language,json=acceptLanguage,proto3,oneof" json:"accept_language,omitempty"`
+	BasicAuth      *BasicAuth        `protobuf:"bytes,14,opt,name=basic_auth,json=basicAuth,proto3" json:"basic_auth,omitempty"`
+	Cookies        map[string]string `protobuf:"bytes,15,rep,name=cookies,proto3" json:"cookies,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// proxy é aceito apenas quando PROXY_ALLOW_PER_REQUEST está habilitado.
+	Proxy *string `protobuf:"bytes,16,opt,name=proxy,proto3,oneof" json:"proxy,omitempty"`
+	// session é o nome de uma sessão configurada em CRAWLER_SESSIONS_FILE.
+	Session       *string `protobuf:"bytes,17,opt,name=session,proto3,oneof" json:"session,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CrawlRequest) Reset() {
+	*x = CrawlRequest{}
+	mi := &file_crawler_v1_crawler_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CrawlRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CrawlRequest) ProtoMessage() {}
+
+func (x *CrawlRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_v1_crawler_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CrawlRequest.ProtoReflect.Descriptor instead.
+func (*CrawlRequest) Descriptor() ([]byte, []int) {
+	return file_crawler_v1_crawler_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CrawlRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *CrawlRequest) GetTimeout() int32 {
+	if x != nil && x.Timeout != nil {
+		return *x.Timeout
+	}
+	return 0
+}
+
+func (x *CrawlRequest) GetRemoveFragment() bool {
+	if x != nil && x.RemoveFragment != nil {
+		return *x.RemoveFragment
+	}
+	return false
+}
+
+func (x *CrawlRequest) GetAllowedDomains() []string {
+	if x != nil {
+		return x.AllowedDomains
+	}
+	return nil
+}
+
+func (x *CrawlRequest) GetCollectSubdomains() bool {
+	if x != nil && x.CollectSubdomains != nil {
+		return *x.CollectSubdomains
+	}
+	return false
+}
+
+func (x *CrawlRequest) GetLowerCaseUrls() bool {
+	if x != nil && x.LowerCaseUrls != nil {
+		return *x.LowerCaseUrls
+	}
+	return false
+}
+
+func (x *CrawlRequest) GetCanRetry() bool {
+	if x != nil && x.CanRetry != nil {
+		return *x.CanRetry
+	}
+	return false
+}
+
+func (x *CrawlRequest) GetMaxAttempts() int32 {
+	if x != nil && x.MaxAttempts != nil {
+		return *x.MaxAttempts
+	}
+	return 0
+}
+
+func (x *CrawlRequest) GetIncludeBody() string {
+	if x != nil && x.IncludeBody != nil {
+		return *x.IncludeBody
+	}
+	return ""
+}
+
+func (x *CrawlRequest) GetMaxBodySize() int32 {
+	if x != nil && x.MaxBodySize != nil {
+		return *x.MaxBodySize
+	}
+	return 0
+}
+
+func (x *CrawlRequest) GetHeaders() map[string]string {
+	if x != nil {
+		return x.Headers
+	}
+	return nil
+}
+
+func (x *CrawlRequest) GetUserAgent() string {
+	if x != nil && x.UserAgent != nil {
+		return *x.UserAgent
+	}
+	return ""
+}
+
+func (x *CrawlRequest) GetAcceptLanguage() string {
+	if x != nil && x.AcceptLanguage != nil {
+		return *x.AcceptLanguage
+	}
+	return ""
+}
+
+func (x *CrawlRequest) GetBasicAuth() *BasicAuth {
+	if x != nil {
+		return x.BasicAuth
+	}
+	return nil
+}
+
+func (x *CrawlRequest) GetCookies() map[string]string {
+	if x != nil {
+		return x.Cookies
+	}
+	return nil
+}
+
+func (x *CrawlRequest) GetProxy() string {
+	if x != nil && x.Proxy != nil {
+		return *x.Proxy
+	}
+	return ""
+}
+
+func (x *CrawlRequest) GetSession() string {
+	if x != nil && x.Session != nil {
+		return *x.Session
+	}
+	return ""
+}
+
+type BasicAuth struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BasicAuth) Reset() {
+	*x = BasicAuth{}
+	mi := &file_crawler_v1_crawler_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BasicAuth) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BasicAuth) ProtoMessage() {}
+
+func (x *BasicAuth) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_v1_crawler_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BasicAuth.ProtoReflect.Descriptor instead.
+func (*BasicAuth) Descriptor() ([]byte, []int) {
+	return file_crawler_v1_crawler_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *BasicAuth) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *BasicAuth) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type Links struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Available     []string               `protobuf:"bytes,1,rep,name=available,proto3" json:"available,omitempty"`
+	Unavailable   []string               `protobuf:"bytes,2,rep,name=unavailable,proto3" json:"unavailable,omitempty"`
+	Occurrences   map[string]int32       `protobuf:"bytes,3,rep,name=occurrences,proto3" json:"occurrences,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Links) Reset() {
+	*x = Links{}
+	mi := &file_crawler_v1_crawler_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Links) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Links) ProtoMessage() {}
+
+func (x *Links) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_v1_crawler_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Links.ProtoReflect.Descriptor instead.
+func (*Links) Descriptor() ([]byte, []int) {
+	return file_crawler_v1_crawler_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Links) GetAvailable() []string {
+	if x != nil {
+		return x.Available
+	}
+	return nil
+}
+
+func (x *Links) GetUnavailable() []string {
+	if x != nil {
+		return x.Unavailable
+	}
+	return nil
+}
+
+func (x *Links) GetOccurrences() map[string]int32 {
+	if x != nil {
+		return x.Occurrences
+	}
+	return nil
+}
+
+type CrawlResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StatusCode    int32                  `protobuf:"varint,1,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"`
+	ContentType   string                 `protobuf:"bytes,2,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	ElapsedTime   *durationpb.Duration   `protobuf:"bytes,3,opt,name=elapsed_time,json=elapsedTime,proto3" json:"elapsed_time,omitempty"`
+	Links         *Links                 `protobuf:"bytes,4,opt,name=links,proto3" json:"links,omitempty"`
+	Title         string                 `protobuf:"bytes,5,opt,name=title,proto3" json:"title,omitempty"`
+	ErrorClass    string                 `protobuf:"bytes,6,opt,name=error_class,json=errorClass,proto3" json:"error_class,omitempty"`
+	ContentHash   string                 `protobuf:"bytes,7,opt,name=content_hash,json=contentHash,proto3" json:"content_hash,omitempty"`
+	Body          string                 `protobuf:"bytes,8,opt,name=body,proto3" json:"body,omitempty"`
+	BodyEncoding  string                 `protobuf:"bytes,9,opt,name=body_encoding,json=bodyEncoding,proto3" json:"body_encoding,omitempty"`
+	BodyTruncated bool                   `protobuf:"varint,10,opt,name=body_truncated,json=bodyTruncated,proto3" json:"body_truncated,omitempty"`
+	// correct_url é a URL normalizada que foi buscada.
+	CorrectUrl string `protobuf:"bytes,11,opt,name=correct_url,json=correctUrl,proto3" json:"correct_url,omitempty"`
+	// charset é a codificação detectada da página.
+	Charset       string `protobuf:"bytes,12,opt,name=charset,proto3" json:"charset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CrawlResponse) Reset() {
+	*x = CrawlResponse{}
+	mi := &file_crawler_v1_crawler_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CrawlResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CrawlResponse) ProtoMessage() {}
+
+func (x *CrawlResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_v1_crawler_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CrawlResponse.ProtoReflect.Descriptor instead.
+func (*CrawlResponse) Descriptor() ([]byte, []int) {
+	return file_crawler_v1_crawler_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CrawlResponse) GetStatusCode() int32 {
+	if x != nil {
+		return x.StatusCode
+	}
+	return 0
+}
+
+func (x *CrawlResponse) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *CrawlResponse) GetElapsedTime() *durationpb.Duration {
+	if x != nil {
+		return x.ElapsedTime
+	}
+	return nil
+}
+
+func (x *CrawlResponse) GetLinks() *Links {
+	if x != nil {
+		return x.Links
+	}
+	return nil
+}
+
+func (x *CrawlResponse) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *CrawlResponse) GetErrorClass() string {
+	if x != nil {
+		return x.ErrorClass
+	}
+	return ""
+}
+
+func (x *CrawlResponse) GetContentHash() string {
+	if x != nil {
+		return x.ContentHash
+	}
+	return ""
+}
+
+func (x *CrawlResponse) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *CrawlResponse) GetBodyEncoding() string {
+	if x != nil {
+		return x.BodyEncoding
+	}
+	return ""
+}
+
+func (x *CrawlResponse) GetBodyTruncated() bool {
+	if x != nil {
+		return x.BodyTruncated
+	}
+	return false
+}
+
+func (x *CrawlResponse) GetCorrectUrl() string {
+	if x != nil {
+		return x.CorrectUrl
+	}
+	return ""
+}
+
+func (x *CrawlResponse) GetCharset() string {
+	if x != nil {
+		return x.Charset
+	}
+	return ""
+}
+
+// CrawlSiteRequest equivale ao corpo do POST /crawls.
+type CrawlSiteRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SeedUrl        string                 `protobuf:"bytes,1,opt,name=seed_url,json=seedUrl,proto3" json:"seed_url,omitempty"`
+	MaxDepth       int32                  `protobuf:"varint,2,opt,name=max_depth,json=maxDepth,proto3" json:"max_depth,omitempty"`
+	FullGraph      *bool                  `protobuf:"varint,3,opt,name=full_graph,json=fullGraph,proto3,oneof" json:"full_graph,omitempty"`
+	AllowedDomains []string               `protobuf:"bytes,4,rep,name=allowed_domains,json=allowedDomains,proto3" json:"allowed_domains,omitempty"`
+	Session        string                 `protobuf:"bytes,5,opt,name=session,proto3" json:"session,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CrawlSiteRequest) Reset() {
+	*x = CrawlSiteRequest{}
+	mi := &file_crawler_v1_crawler_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CrawlSiteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CrawlSiteRequest) ProtoMessage() {}
+
+func (x *CrawlSiteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_v1_crawler_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CrawlSiteRequest.ProtoReflect.Descriptor instead.
+func (*CrawlSiteRequest) Descriptor() ([]byte, []int) {
+	return file_crawler_v1_crawler_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CrawlSiteRequest) GetSeedUrl() string {
+	if x != nil {
+		return x.SeedUrl
+	}
+	return ""
+}
+
+func (x *CrawlSiteRequest) GetMaxDepth() int32 {
+	if x != nil {
+		return x.MaxDepth
+	}
+	return 0
+}
+
+func (x *CrawlSiteRequest) GetFullGraph() bool {
+	if x != nil && x.FullGraph != nil {
+		return *x.FullGraph
+	}
+	return false
+}
+
+func (x *CrawlSiteRequest) GetAllowedDomains() []string {
+	if x != nil {
+		return x.AllowedDomains
+	}
+	return nil
+}
+
+func (x *CrawlSiteRequest) GetSession() string {
+	if x != nil {
+		return x.Session
+	}
+	return ""
+}
+
+type LinkCounts struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Internal      int32                  `protobuf:"varint,1,opt,name=internal,proto3" json:"internal,omitempty"`
+	New           int32                  `protobuf:"varint,2,opt,name=new,proto3" json:"new,omitempty"`
+	External      int32                  `protobuf:"varint,3,opt,name=external,proto3" json:"external,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LinkCounts) Reset() {
+	*x = LinkCounts{}
+	mi := &file_crawler_v1_crawler_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LinkCounts) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LinkCounts) ProtoMessage() {}
+
+func (x *LinkCounts) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_v1_crawler_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LinkCounts.ProtoReflect.Descriptor instead.
+func (*LinkCounts) Descriptor() ([]byte, []int) {
+	return file_crawler_v1_crawler_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *LinkCounts) GetInternal() int32 {
+	if x != nil {
+		return x.Internal
+	}
+	return 0
+}
+
+func (x *LinkCounts) GetNew() int32 {
+	if x != nil {
+		return x.New
+	}
+	return 0
+}
+
+func (x *LinkCounts) GetExternal() int32 {
+	if x != nil {
+		return x.External
+	}
+	return 0
+}
+
+type Counters struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Discovered    int32                  `protobuf:"varint,1,opt,name=discovered,proto3" json:"discovered,omitempty"`
+	Queued        int32                  `protobuf:"varint,2,opt,name=queued,proto3" json:"queued,omitempty"`
+	Fetched       int32                  `protobuf:"varint,3,opt,name=fetched,proto3" json:"fetched,omitempty"`
+	Failed        int32                  `protobuf:"varint,4,opt,name=failed,proto3" json:"failed,omitempty"`
+	External      int32                  `protobuf:"varint,5,opt,name=external,proto3" json:"external,omitempty"`
+	Links         int32                  `protobuf:"varint,6,opt,name=links,proto3" json:"links,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Counters) Reset() {
+	*x = Counters{}
+	mi := &file_crawler_v1_crawler_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Counters) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Counters) ProtoMessage() {}
+
+func (x *Counters) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_v1_crawler_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Counters.ProtoReflect.Descriptor instead.
+func (*Counters) Descriptor() ([]byte, []int) {
+	return file_crawler_v1_crawler_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Counters) GetDiscovered() int32 {
+	if x != nil {
+		return x.Discovered
+	}
+	return 0
+}
+
+func (x *Counters) GetQueued() int32 {
+	if x != nil {
+		return x.Queued
+	}
+	return 0
+}
+
+func (x *Counters) GetFetched() int32 {
+	if x != nil {
+		return x.Fetched
+	}
+	return 0
+}
+
+func (x *Counters) GetFailed() int32 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *Counters) GetExternal() int32 {
+	if x != nil {
+		return x.External
+	}
+	return 0
+}
+
+func (x *Counters) GetLinks() int32 {
+	if x != nil {
+		return x.Links
+	}
+	return 0
+}
+
+// CrawlEvent é um evento de progresso, com os mesmos campos do SSE de
+// /crawls/{id}/events.
+type CrawlEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CrawlId       string                 `protobuf:"bytes,1,opt,name=crawl_id,json=crawlId,proto3" json:"crawl_id,omitempty"`
+	Seq           int64                  `protobuf:"varint,2,opt,name=seq,proto3" json:"seq,omitempty"`
+	Type          string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Time          *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=time,proto3" json:"time,omitempty"`
+	Url           string                 `protobuf:"bytes,5,opt,name=url,proto3" json:"url,omitempty"`
+	Depth         int32                  `protobuf:"varint,6,opt,name=depth,proto3" json:"depth,omitempty"`
+	StatusCode    int32                  `protobuf:"varint,7,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"`
+	ElapsedTime   *durationpb.Duration   `protobuf:"bytes,8,opt,name=elapsed_time,json=elapsedTime,proto3" json:"elapsed_time,omitempty"`
+	Title         string                 `protobuf:"bytes,9,opt,name=title,proto3" json:"title,omitempty"`
+	Links         *LinkCounts            `protobuf:"bytes,10,opt,name=links,proto3" json:"links,omitempty"`
+	ErrorClass    string                 `protobuf:"bytes,11,opt,name=error_class,json=errorClass,proto3" json:"error_class,omitempty"`
+	Error         string                 `protobuf:"bytes,12,opt,name=error,proto3" json:"error,omitempty"`
+	Counters      *Counters              `protobuf:"bytes,13,opt,name=counters,proto3" json:"counters,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CrawlEvent) Reset() {
+	*x = CrawlEvent{}
+	mi := &file_crawler_v1_crawler_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CrawlEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CrawlEvent) ProtoMessage() {}
+
+func (x *CrawlEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_crawler_v1_crawler_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CrawlEvent.ProtoReflect.Descriptor instead.
+func (*CrawlEvent) Descriptor() ([]byte, []int) {
+	return file_crawler_v1_crawler_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CrawlEvent) GetCrawlId() string {
+	if x != nil {
+		return x.CrawlId
+	}
+	return ""
+}
+
+func (x *CrawlEvent) GetSeq() int64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *CrawlEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *CrawlEvent) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *CrawlEvent) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *CrawlEvent) GetDepth() int32 {
+	if x != nil {
+		return x.Depth
+	}
+	return 0
+}
+
+func (x *CrawlEvent) GetStatusCode() int32 {
+	if x != nil {
+		return x.StatusCode
+	}
+	return 0
+}
+
+func (x *CrawlEvent) GetElapsedTime() *durationpb.Duration {
+	if x != nil {
+		return x.ElapsedTime
+	}
+	return nil
+}
+
+func (x *CrawlEvent) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *CrawlEvent) GetLinks() *LinkCounts {
+	if x != nil {
+		return x.Links
+	}
+	return nil
+}
+
+func (x *CrawlEvent) GetErrorClass() string {
+	if x != nil {
+		return x.ErrorClass
+	}
+	return ""
+}
+
+func (x *CrawlEvent) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *CrawlEvent) GetCounters() *Counters {
+	if x != nil {
+		return x.Counters
+	}
+	return nil
+}
+
+var File_crawler_v1_crawler_proto protoreflect.FileDescriptor
+
+const file_crawler_v1_crawler_proto_rawDesc = "" +
+	"\n" +
+	"\x18crawler/v1/crawler.proto\x12\n" +
+	"crawler.v1\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x94\b\n" +
+	"\fCrawlRequest\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x1d\n" +
+	"\atimeout\x18\x02 \x01(\x05H\x00R\atimeout\x88\x01\x01\x12,\n" +
+	"\x0fremove_fragment\x18\x03 \x01(\bH\x01R\x0eremoveFragment\x88\x01\x01\x12'\n" +
+	"\x0fallowed_domains\x18\x04 \x03(\tR\x0eallowedDomains\x122\n" +
+	"\x12collect_subdomains\x18\x05 \x01(\bH\x02R\x11collectSubdomains\x88\x01\x01\x12+\n" +
+	"\x0flower_case_urls\x18\x06 \x01(\bH\x03R\rlowerCaseUrls\x88\x01\x01\x12 \n" +
+	"\tcan_retry\x18\a \x01(\bH\x04R\bcanRetry\x88\x01\x01\x12&\n" +
+	"\fmax_attempts\x18\b \x01(\x05H\x05R\vmaxAttempts\x88\x01\x01\x12&\n" +
+	"\finclude_body\x18\t \x01(\tH\x06R\vincludeBody\x88\x01\x01\x12'\n" +
+	"\rmax_body_size\x18\n" +
+	" \x01(\x05H\aR\vmaxBodySize\x88\x01\x01\x12?\n" +
+	"\aheaders\x18\v \x03(\v2%.crawler.v1.CrawlRequest.HeadersEntryR\aheaders\x12\"\n" +
+	"\n" +
+	"user_agent\x18\f \x01(\tH\bR\tuserAgent\x88\x01\x01\x12,\n" +
+	"\x0faccept_language\x18\r \x01(\tH\tR\x0eacceptLanguage\x88\x01\x01\x124\n" +
+	"\n" +
+	"basic_auth\x18\x0e \x01(\v2\x15.crawler.v1.BasicAuthR\tbasicAuth\x12?\n" +
+	"\acookies\x18\x0f \x03(\v2%.crawler.v1.CrawlRequest.CookiesEntryR\acookies\x12\x19\n" +
+	"\x05proxy\x18\x10 \x01(\tH\n" +
+	"R\x05proxy\x88\x01\x01\x12\x1d\n" +
+	"\asession\x18\x11 \x01(\tH\vR\asession\x88\x01\x01\x1a:\n" +
+	"\fHeadersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a:\n" +
+	"\fCookiesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\n" +
+	"\n" +
+	"\b_timeoutB\x12\n" +
+	"\x10_remove_fragmentB\x15\n" +
+	"\x13_collect_subdomainsB\x12\n" +
+	"\x10_lower_case_urlsB\f\n" +
+	"\n" +
+	"_can_retryB\x0f\n" +
+	"\r_max_attemptsB\x0f\n" +
+	"\r_include_bodyB\x10\n" +
+	"\x0e_max_body_sizeB\r\n" +
+	"\v_user_agentB\x12\n" +
+	"\x10_accept_languageB\b\n" +
+	"\x06_proxyB\n" +
+	"\n" +
+	"\b_session\"C\n" +
+	"\tBasicAuth\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\"\xcd\x01\n" +
+	"\x05Links\x12\x1c\n" +
+	"\tavailable\x18\x01 \x03(\tR\tavailable\x12 \n" +
+	"\vunavailable\x18\x02 \x03(\tR\vunavailable\x12D\n" +
+	"\voccurrences\x18\x03 \x03(\v2\".crawler.v1.Links.OccurrencesEntryR\voccurrences\x1a>\n" +
+	"\x10OccurrencesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\"\xaf\x03\n" +
+	"\rCrawlResponse\x12\x1f\n" +
+	"\vstatus_code\x18\x01 \x01(\x05R\n" +
+	"statusCode\x12!\n" +
+	"\fcontent_type\x18\x02 \x01(\tR\vcontentType\x12<\n" +
+	"\felapsed_time\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\velapsedTime\x12'\n" +
+	"\x05links\x18\x04 \x01(\v2\x11.crawler.v1.LinksR\x05links\x12\x14\n" +
+	"\x05title\x18\x05 \x01(\tR\x05title\x12\x1f\n" +
+	"\verror_class\x18\x06 \x01(\tR\n" +
+	"errorClass\x12!\n" +
+	"\fcontent_hash\x18\a \x01(\tR\vcontentHash\x12\x12\n" +
+	"\x04body\x18\b \x01(\tR\x04body\x12#\n" +
+	"\rbody_encoding\x18\t \x01(\tR\fbodyEncoding\x12%\n" +
+	"\x0ebody_truncated\x18\n" +
+	" \x01(\bR\rbodyTruncated\x12\x1f\n" +
+	"\vcorrect_url\x18\v \x01(\tR\n" +
+	"correctUrl\x12\x18\n" +
+	"\acharset\x18\f \x01(\tR\acharset\"\xc0\x01\n" +
+	"\x10CrawlSiteRequest\x12\x19\n" +
+	"\bseed_url\x18\x01 \x01(\tR\aseedUrl\x12\x1b\n" +
+	"\tmax_depth\x18\x02 \x01(\x05R\bmaxDepth\x12\"\n" +
+	"\n" +
+	"full_graph\x18\x03 \x01(\bH\x00R\tfullGraph\x88\x01\x01\x12'\n" +
+	"\x0fallowed_domains\x18\x04 \x03(\tR\x0eallowedDomains\x12\x18\n" +
+	"\asession\x18\x05 \x01(\tR\asessionB\r\n" +
+	"\v_full_graph\"V\n" +
+	"\n" +
+	"LinkCounts\x12\x1a\n" +
+	"\binternal\x18\x01 \x01(\x05R\binternal\x12\x10\n" +
+	"\x03new\x18\x02 \x01(\x05R\x03new\x12\x1a\n" +
+	"\bexternal\x18\x03 \x01(\x05R\bexternal\"\xa6\x01\n" +
+	"\bCounters\x12\x1e\n" +
+	"\n" +
+	"discovered\x18\x01 \x01(\x05R\n" +
+	"discovered\x12\x16\n" +
+	"\x06queued\x18\x02 \x01(\x05R\x06queued\x12\x18\n" +
+	"\afetched\x18\x03 \x01(\x05R\afetched\x12\x16\n" +
+	"\x06failed\x18\x04 \x01(\x05R\x06failed\x12\x1a\n" +
+	"\bexternal\x18\x05 \x01(\x05R\bexternal\x12\x14\n" +
+	"\x05links\x18\x06 \x01(\x05R\x05links\"\xb1\x03\n" +
+	"\n" +
+	"CrawlEvent\x12\x19\n" +
+	"\bcrawl_id\x18\x01 \x01(\tR\acrawlId\x12\x10\n" +
+	"\x03seq\x18\x02 \x01(\x03R\x03seq\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12.\n" +
+	"\x04time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12\x10\n" +
+	"\x03url\x18\x05 \x01(\tR\x03url\x12\x14\n" +
+	"\x05depth\x18\x06 \x01(\x05R\x05depth\x12\x1f\n" +
+	"\vstatus_code\x18\a \x01(\x05R\n" +
+	"statusCode\x12<\n" +
+	"\felapsed_time\x18\b \x01(\v2\x19.google.protobuf.DurationR\velapsedTime\x12\x14\n" +
+	"\x05title\x18\t \x01(\tR\x05title\x12,\n" +
+	"\x05links\x18\n" +
+	" \x01(\v2\x16.crawler.v1.LinkCountsR\x05links\x12\x1f\n" +
+	"\verror_class\x18\v \x01(\tR\n" +
+	"errorClass\x12\x14\n" +
+	"\x05error\x18\f \x01(\tR\x05error\x120\n" +
+	"\bcounters\x18\r \x01(\v2\x14.crawler.v1.CountersR\bcounters2\x93\x01\n" +
+	"\x0eCrawlerService\x12<\n" +
+	"\x05Crawl\x12\x18.crawler.v1.CrawlRequest\x1a\x19.crawler.v1.CrawlResponse\x12C\n" +
+	"\tCrawlSite\x12\x1c.crawler.v1.CrawlSiteRequest\x1a\x16.crawler.v1.CrawlEvent0\x01BNZLgithub.com/nettojulio/ufape-crawler-golang/internal/grpc/crawlerv1;crawlerv1b\x06proto3"
+
+var (
+	file_crawler_v1_crawler_proto_rawDescOnce sync.Once
+	file_crawler_v1_crawler_proto_rawDescData []byte
+)
+
+func file_crawler_v1_crawler_proto_rawDescGZIP() []byte {
+	file_crawler_v1_crawler_proto_rawDescOnce.Do(func() {
+		file_crawler_v1_crawler_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_crawler_v1_crawler_proto_rawDesc), len(file_crawler_v1_crawler_proto_rawDesc)))
+	})
+	return file_crawler_v1_crawler_proto_rawDescData
+}
+
+var file_crawler_v1_crawler_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_crawler_v1_crawler_proto_goTypes = []any{
+	(*CrawlRequest)(nil),          // 0: crawler.v1.CrawlRequest
+	(*BasicAuth)(nil),             // 1: crawler.v1.BasicAuth
+	(*Links)(nil),                 // 2: crawler.v1.Links
+	(*CrawlResponse)(nil),         // 3: crawler.v1.CrawlResponse
+	(*CrawlSiteRequest)(nil),      // 4: crawler.v1.CrawlSiteRequest
+	(*LinkCounts)(nil),            // 5: crawler.v1.LinkCounts
+	(*Counters)(nil),              // 6: crawler.v1.Counters
+	(*CrawlEvent)(nil),            // 7: crawler.v1.CrawlEvent
+	nil,                           // 8: crawler.v1.CrawlRequest.HeadersEntry
+	nil,                           // 9: crawler.v1.CrawlRequest.CookiesEntry
+	nil,                           // 10: crawler.v1.Links.OccurrencesEntry
+	(*durationpb.Duration)(nil),   // 11: google.protobuf.Duration
+	(*timestamppb.Timestamp)(nil), // 12: google.protobuf.Timestamp
+}
+var file_crawler_v1_crawler_proto_depIdxs = []int32{
+	8,  // 0: crawler.v1.CrawlRequest.headers:type_name -> crawler.v1.CrawlRequest.HeadersEntry
+	1,  // 1: crawler.v1.CrawlRequest.basic_auth:type_name -> crawler.v1.BasicAuth
+	9,  // 2: crawler.v1.CrawlRequest.cookies:type_name -> crawler.v1.CrawlRequest.CookiesEntry
+	10, // 3: crawler.v1.Links.occurrences:type_name -> crawler.v1.Links.OccurrencesEntry
+	11, // 4: crawler.v1.CrawlResponse.elapsed_time:type_name -> google.protobuf.Duration
+	2,  // 5: crawler.v1.CrawlResponse.links:type_name -> crawler.v1.Links
+	12, // 6: crawler.v1.CrawlEvent.time:type_name -> google.protobuf.Timestamp
+	11, // 7: crawler.v1.CrawlEvent.elapsed_time:type_name -> google.protobuf.Duration
+	5,  // 8: crawler.v1.CrawlEvent.links:type_name -> crawler.v1.LinkCounts
+	6,  // 9: crawler.v1.CrawlEvent.counters:type_name -> crawler.v1.Counters
+	0,  // 10: crawler.v1.CrawlerService.Crawl:input_type -> crawler.v1.CrawlRequest
+	4,  // 11: crawler.v1.CrawlerService.CrawlSite:input_type -> crawler.v1.CrawlSiteRequest
+	3,  // 12: crawler.v1.CrawlerService.Crawl:output_type -> crawler.v1.CrawlResponse
+	7,  // 13: crawler.v1.CrawlerService.CrawlSite:output_type -> crawler.v1.CrawlEvent
+	12, // [12:14] is the sub-list for method output_type
+	10, // [10:12] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
+}
+
+func init() { file_crawler_v1_crawler_proto_init() }
+func file_crawler_v1_crawler_proto_init() {
+	if File_crawler_v1_crawler_proto != nil {
+		return
+	}
+	file_crawler_v1_crawler_proto_msgTypes[0].OneofWrappers = []any{}
+	file_crawler_v1_crawler_proto_msgTypes[4].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_crawler_v1_crawler_proto_rawDesc), len(file_crawler_v1_crawler_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_crawler_v1_crawler_proto_goTypes,
+		DependencyIndexes: file_crawler_v1_crawler_proto_depIdxs,
+		MessageInfos:      file_crawler_v1_crawler_proto_msgTypes,
+	}.Build()
+	File_crawler_v1_crawler_proto = out.File
+	file_crawler_v1_crawler_proto_goTypes = nil
+	file_crawler_v1_crawler_proto_depIdxs = nil
+}
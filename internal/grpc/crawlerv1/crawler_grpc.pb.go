@@ -0,0 +1,175 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: crawler/v1/crawler.proto
+
+package crawlerv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	CrawlerService_Crawl_FullMethodName     = "/crawler.v1.CrawlerService/Crawl"
+	CrawlerService_CrawlSite_FullMethodName = "/crawler.v1.CrawlerService/CrawlSite"
+)
+
+// CrawlerServiceClient is the client API for CrawlerService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// CrawlerService expõe o crawler por gRPC, com a mesma semântica da API REST.
+type CrawlerServiceClient interface {
+	// Crawl busca uma página, como o POST /.
+	Crawl(ctx context.Context, in *CrawlRequest, opts ...grpc.CallOption) (*CrawlResponse, error)
+	// CrawlSite percorre o site a partir de seed_url, como o POST /crawls, e
+	// envia os eventos de progresso até o fim do crawling. Encerrar a chamada
+	// cancela o crawling.
+	CrawlSite(ctx context.Context, in *CrawlSiteRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CrawlEvent], error)
+}
+
+type crawlerServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCrawlerServiceClient(cc grpc.ClientConnInterface) CrawlerServiceClient {
+	return &crawlerServiceClient{cc}
+}
+
+func (c *crawlerServiceClient) Crawl(ctx context.Context, in *CrawlRequest, opts ...grpc.CallOption) (*CrawlResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CrawlResponse)
+	err := c.cc.Invoke(ctx, CrawlerService_Crawl_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *crawlerServiceClient) CrawlSite(ctx context.Context, in *CrawlSiteRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CrawlEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CrawlerService_ServiceDesc.Streams[0], CrawlerService_CrawlSite_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[CrawlSiteRequest, CrawlEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CrawlerService_CrawlSiteClient = grpc.ServerStreamingClient[CrawlEvent]
+
+// CrawlerServiceServer is the server API for CrawlerService service.
+// All implementations must embed UnimplementedCrawlerServiceServer
+// for forward compatibility.
+//
+// CrawlerService expõe o crawler por gRPC, com a mesma semântica da API REST.
+type CrawlerServiceServer interface {
+	// Crawl busca uma página, como o POST /.
+	Crawl(context.Context, *CrawlRequest) (*CrawlResponse, error)
+	// CrawlSite percorre o site a partir de seed_url, como o POST /crawls, e
+	// envia os eventos de progresso até o fim do crawling. Encerrar a chamada
+	// cancela o crawling.
+	CrawlSite(*CrawlSiteRequest, grpc.ServerStreamingServer[CrawlEvent]) error
+	mustEmbedUnimplementedCrawlerServiceServer()
+}
+
+// UnimplementedCrawlerServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedCrawlerServiceServer struct{}
+
+func (UnimplementedCrawlerServiceServer) Crawl(context.Context, *CrawlRequest) (*CrawlResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Crawl not implemented")
+}
+func (UnimplementedCrawlerServiceServer) CrawlSite(*CrawlSiteRequest, grpc.ServerStreamingServer[CrawlEvent]) error {
+	return status.Error(codes.Unimplemented, "method CrawlSite not implemented")
+}
+func (UnimplementedCrawlerServiceServer) mustEmbedUnimplementedCrawlerServiceServer() {}
+func (UnimplementedCrawlerServiceServer) testEmbeddedByValue()                        {}
+
+// UnsafeCrawlerServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CrawlerServiceServer will
+// result in compilation errors.
+type UnsafeCrawlerServiceServer interface {
+	mustEmbedUnimplementedCrawlerServiceServer()
+}
+
+func RegisterCrawlerServiceServer(s grpc.ServiceRegistrar, srv CrawlerServiceServer) {
+	// If the following call panics, it indicates UnimplementedCrawlerServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&CrawlerService_ServiceDesc, srv)
+}
+
+func _CrawlerService_Crawl_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CrawlRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CrawlerServiceServer).Crawl(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CrawlerService_Crawl_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CrawlerServiceServer).Crawl(ctx, req.(*CrawlRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CrawlerService_CrawlSite_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(CrawlSiteRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CrawlerServiceServer).CrawlSite(m, &grpc.GenericServerStream[CrawlSiteRequest, CrawlEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CrawlerService_CrawlSiteServer = grpc.ServerStreamingServer[CrawlEvent]
+
+// CrawlerService_ServiceDesc is the grpc.ServiceDesc for CrawlerService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CrawlerService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "crawler.v1.CrawlerService",
+	HandlerType: (*CrawlerServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Crawl",
+			Handler:    _CrawlerService_Crawl_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "CrawlSite",
+			Handler:       _CrawlerService_CrawlSite_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "crawler/v1/crawler.proto",
+}
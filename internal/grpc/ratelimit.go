@@ -0,0 +1,94 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"strconv"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/nettojulio/ufape-crawler-golang/internal/auth"
+	"github.com/nettojulio/ufape-crawler-golang/internal/grpc/crawlerv1"
+	"github.com/nettojulio/ufape-crawler-golang/internal/metrics"
+	"github.com/nettojulio/ufape-crawler-golang/internal/ratelimit"
+)
+
+// methodLimit escolhe o limite de taxa de um método do CrawlerService, com o
+// mesmo nome de política usado pela API REST. Os demais serviços não são
+// limitados.
+func methodLimit(limits *ratelimit.Limits, method string) (string, *ratelimit.Limiter) {
+	switch method {
+	case crawlerv1.CrawlerService_Crawl_FullMethodName:
+		return "crawl", limits.Crawl
+	case crawlerv1.CrawlerService_CrawlSite_FullMethodName:
+		return "jobs", limits.Jobs
+	}
+	return "", nil
+}
+
+// allow consome uma chamada do limite do método para o cliente: a chave de
+// API autenticada ou, sem ela, o IP do peer, as mesmas chaves da API REST. A
+// resposta recebe os metadados ratelimit-limit, ratelimit-remaining e
+// ratelimit-reset; as recusadas falham com ResourceExhausted e retry-after.
+func allow(ctx context.Context, limits *ratelimit.Limits, method string, setHeader func(metadata.MD) error) error {
+	policy, limiter := methodLimit(limits, method)
+	if limiter == nil {
+		return nil
+	}
+
+	client := "ip:" + peerIP(ctx)
+	if caller := auth.CallerFromContext(ctx); caller != nil {
+		client = "key:" + caller.Key.ID
+	}
+
+	d := limiter.Allow(client)
+	md := metadata.Pairs(
+		"ratelimit-limit", strconv.Itoa(d.Limit),
+		"ratelimit-remaining", strconv.Itoa(d.Remaining),
+		"ratelimit-reset", ratelimit.Seconds(d.Reset),
+	)
+	if !d.Allowed {
+		metrics.RateLimited.WithLabelValues(policy).Inc()
+		md.Set("retry-after", ratelimit.Seconds(d.RetryAfter))
+	}
+	_ = setHeader(md)
+	if !d.Allowed {
+		return status.Error(codes.ResourceExhausted, "rate limit exceeded")
+	}
+	return nil
+}
+
+// peerIP retorna o IP de quem fez a chamada, sem a porta.
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+		return host
+	}
+	return p.Addr.String()
+}
+
+func unaryRateLimitInterceptor(limits *ratelimit.Limits) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		setHeader := func(md metadata.MD) error { return grpc.SetHeader(ctx, md) }
+		if err := allow(ctx, limits, info.FullMethod, setHeader); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func streamRateLimitInterceptor(limits *ratelimit.Limits) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := allow(ss.Context(), limits, info.FullMethod, ss.SetHeader); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
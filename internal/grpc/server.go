@@ -0,0 +1,98 @@
+// Package grpc expõe o crawler por gRPC, ao lado da API REST, com health
+// checking e reflection.
+//
+// O código em crawlerv1 é gerado a partir de proto/crawler/v1/crawler.proto
+// com make generate-proto.
+package grpc
+
+import (
+	"log/slog"
+	"net"
+
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+
+	"github.com/nettojulio/ufape-crawler-golang/internal/auth"
+	"github.com/nettojulio/ufape-crawler-golang/internal/crawler"
+	"github.com/nettojulio/ufape-crawler-golang/internal/grpc/crawlerv1"
+	"github.com/nettojulio/ufape-crawler-golang/internal/live"
+	"github.com/nettojulio/ufape-crawler-golang/internal/ratelimit"
+	"github.com/nettojulio/ufape-crawler-golang/internal/storage"
+)
+
+// Dependencies agrupa os serviços usados pelo servidor gRPC.
+type Dependencies struct {
+	CrawlerService crawler.Crawler
+	// Repository é opcional; quando nil, as buscas não são persistidas.
+	Repository storage.Repository
+	// Jobs é opcional; quando nil, CrawlSite responde Unimplemented.
+	Jobs   *live.Manager
+	Logger *slog.Logger
+	// Auth é opcional; quando definido, os métodos do CrawlerService exigem
+	// uma chave de API nos metadados x-api-key ou authorization.
+	Auth *auth.Store
+	// RateLimits é opcional; quando definido, Crawl e CrawlSite consomem os
+	// mesmos limites das rotas equivalentes da API REST.
+	RateLimits *ratelimit.Limits
+}
+
+// Server é o servidor gRPC da aplicação.
+type Server struct {
+	server *grpc.Server
+	health *health.Server
+}
+
+// NewServer registra o CrawlerService, o serviço de health checking e a
+// reflection em um novo servidor gRPC.
+func NewServer(deps Dependencies, opts ...grpc.ServerOption) *Server {
+	if deps.Logger == nil {
+		deps.Logger = slog.Default()
+	}
+
+	// A autenticação vem antes, para que o limite use a chave do cliente.
+	if deps.Auth != nil {
+		opts = append(opts,
+			grpc.ChainUnaryInterceptor(unaryAuthInterceptor(deps.Auth)),
+			grpc.ChainStreamInterceptor(streamAuthInterceptor(deps.Auth)),
+		)
+	}
+	if deps.RateLimits != nil {
+		opts = append(opts,
+			grpc.ChainUnaryInterceptor(unaryRateLimitInterceptor(deps.RateLimits)),
+			grpc.ChainStreamInterceptor(streamRateLimitInterceptor(deps.RateLimits)),
+		)
+	}
+
+	s := &Server{
+		server: grpc.NewServer(opts...),
+		health: health.NewServer(),
+	}
+	crawlerv1.RegisterCrawlerServiceServer(s.server, &crawlerServer{
+		service:    deps.CrawlerService,
+		repository: deps.Repository,
+		jobs:       deps.Jobs,
+		validate:   validator.New(),
+		logger:     deps.Logger,
+	})
+	healthpb.RegisterHealthServer(s.server, s.health)
+	reflection.Register(s.server)
+
+	s.health.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	s.health.SetServingStatus(crawlerv1.CrawlerService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	return s
+}
+
+// Serve aceita conexões em lis até que o servidor seja parado.
+func (s *Server) Serve(lis net.Listener) error {
+	return s.server.Serve(lis)
+}
+
+// GracefulStop marca os serviços como indisponíveis no health checking e
+// aguarda o fim das chamadas em andamento.
+func (s *Server) GracefulStop() {
+	s.health.Shutdown()
+	s.server.GracefulStop()
+}
@@ -0,0 +1,268 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/nettojulio/ufape-crawler-golang/internal/auth"
+	"github.com/nettojulio/ufape-crawler-golang/internal/crawler"
+	"github.com/nettojulio/ufape-crawler-golang/internal/grpc/crawlerv1"
+	"github.com/nettojulio/ufape-crawler-golang/internal/live"
+	"github.com/nettojulio/ufape-crawler-golang/internal/ratelimit"
+)
+
+// fakeCrawler responde a qualquer URL com uma página sem links e guarda o
+// último payload recebido.
+type fakeCrawler struct {
+	payloads chan crawler.Payload
+	err      error
+}
+
+func (f *fakeCrawler) Crawl(ctx context.Context, payload crawler.Payload, originalURL, modifiedURL *url.URL) (*crawler.CrawlResult, error) {
+	select {
+	case f.payloads <- payload:
+	default:
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &crawler.CrawlResult{
+		StatusCode:  http.StatusOK,
+		ContentType: "text/html",
+		ElapsedTime: 150 * time.Millisecond,
+		Title:       "Página de Teste",
+		Body:        []byte("<html></html>"),
+		FinalURL:    modifiedURL,
+	}, nil
+}
+
+func newTestClient(t *testing.T, service crawler.Crawler) *grpc.ClientConn {
+	t.Helper()
+	return newTestClientWithAuth(t, service, nil)
+}
+
+func newTestClientWithAuth(t *testing.T, service crawler.Crawler, store *auth.Store) *grpc.ClientConn {
+	t.Helper()
+	return newTestClientWithDeps(t, Dependencies{CrawlerService: service, Auth: store})
+}
+
+// newTestClientWithDeps serve deps, com um live.Manager próprio, e conecta a
+// ele.
+func newTestClientWithDeps(t *testing.T, deps Dependencies) *grpc.ClientConn {
+	t.Helper()
+	jobs := live.NewManager(deps.CrawlerService, nil, live.Options{})
+	t.Cleanup(jobs.Stop)
+	deps.Jobs = jobs
+
+	lis := bufconn.Listen(1 << 20)
+	srv := NewServer(deps)
+	go srv.Serve(lis)
+	t.Cleanup(srv.GracefulStop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestCrawl(t *testing.T) {
+	service := &fakeCrawler{payloads: make(chan crawler.Payload, 1)}
+	client := crawlerv1.NewCrawlerServiceClient(newTestClient(t, service))
+
+	resp, err := client.Crawl(context.Background(), &crawlerv1.CrawlRequest{
+		Url:            "http://www.example.com/page#top",
+		IncludeBody:    ptr("text"),
+		AcceptLanguage: ptr("en-US"),
+		Headers:        map[string]string{"X-Section": "news"},
+		BasicAuth:      &crawlerv1.BasicAuth{Username: "user", Password: "secret"},
+	})
+	if err != nil {
+		t.Fatalf("Crawl returned an error: %v", err)
+	}
+	if resp.StatusCode != 200 || resp.Title != "Página de Teste" || resp.CorrectUrl != "http://example.com/page" {
+		t.Errorf("unexpected response: %v", resp)
+	}
+	if resp.Body != "<html></html>" || resp.BodyEncoding != crawler.BodyText {
+		t.Errorf("expected the body as text, got %q (%s)", resp.Body, resp.BodyEncoding)
+	}
+	if resp.ElapsedTime.AsDuration() != 150*time.Millisecond {
+		t.Errorf("unexpected elapsed time: %v", resp.ElapsedTime.AsDuration())
+	}
+
+	payload := <-service.payloads
+	if *payload.Timeout != 60 || !*payload.RemoveFragment || (*payload.AllowedDomains)[0] != "example.com" {
+		t.Errorf("expected the REST defaults to be applied, got %+v", payload)
+	}
+	if payload.AcceptLanguage != "en-US" || payload.Headers["X-Section"] != "news" || payload.BasicAuth == nil || payload.BasicAuth.Password != "secret" {
+		t.Errorf("expected the request options to be passed through, got %+v", payload.RequestOptions)
+	}
+}
+
+func TestCrawlErrors(t *testing.T) {
+	client := crawlerv1.NewCrawlerServiceClient(newTestClient(t, &fakeCrawler{err: errors.New("connection refused")}))
+
+	_, err := client.Crawl(context.Background(), &crawlerv1.CrawlRequest{Url: "not a url"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument, got %v", err)
+	}
+
+	_, err = client.Crawl(context.Background(), &crawlerv1.CrawlRequest{Url: "http://example.com", IncludeBody: ptr("raw")})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument for an unknown body mode, got %v", err)
+	}
+
+	_, err = client.Crawl(context.Background(), &crawlerv1.CrawlRequest{Url: "http://example.com"})
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("expected Unavailable, got %v", err)
+	}
+}
+
+func TestCrawlSite(t *testing.T) {
+	client := crawlerv1.NewCrawlerServiceClient(newTestClient(t, &fakeCrawler{payloads: make(chan crawler.Payload, 1)}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := client.CrawlSite(ctx, &crawlerv1.CrawlSiteRequest{SeedUrl: "http://example.com", MaxDepth: 1})
+	if err != nil {
+		t.Fatalf("CrawlSite returned an error: %v", err)
+	}
+
+	var events []*crawlerv1.CrawlEvent
+	for {
+		event, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("stream failed: %v", err)
+		}
+		events = append(events, event)
+	}
+
+	if len(events) < 2 || events[0].Type != "crawl_started" || events[len(events)-1].Type != "crawl_finished" {
+		t.Fatalf("unexpected events: %v", events)
+	}
+	last := events[len(events)-1]
+	if last.CrawlId == "" || last.Counters.Fetched != 1 || last.Seq != int64(len(events)) {
+		t.Errorf("unexpected final event: %v", last)
+	}
+}
+
+func TestHealth(t *testing.T) {
+	client := healthpb.NewHealthClient(newTestClient(t, &fakeCrawler{}))
+
+	resp, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: crawlerv1.CrawlerService_ServiceDesc.ServiceName})
+	if err != nil {
+		t.Fatalf("Check returned an error: %v", err)
+	}
+	if resp.Status != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("expected SERVING, got %v", resp.Status)
+	}
+}
+
+func TestAuth(t *testing.T) {
+	store, err := auth.NewStore([]auth.Key{
+		{ID: "ci", Key: "ci-secret", Scopes: []auth.Scope{auth.ScopeCrawl}, DailyQuota: 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn := newTestClientWithAuth(t, &fakeCrawler{payloads: make(chan crawler.Payload, 1)}, store)
+	client := crawlerv1.NewCrawlerServiceClient(conn)
+	req := &crawlerv1.CrawlRequest{Url: "https://ufape.edu.br"}
+	withKey := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "ci-secret")
+
+	if _, err := client.Crawl(context.Background(), req); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected Unauthenticated without a key, got %v", err)
+	}
+	stream, err := client.CrawlSite(withKey, &crawlerv1.CrawlSiteRequest{SeedUrl: "https://ufape.edu.br"})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected PermissionDenied without the jobs scope, got %v", err)
+	}
+
+	bearer := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer ci-secret")
+	if _, err := client.Crawl(bearer, req); err != nil {
+		t.Fatalf("expected the crawl to be allowed, got %v", err)
+	}
+	if _, err := client.Crawl(withKey, req); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("expected ResourceExhausted after the quota, got %v", err)
+	}
+
+	health := healthpb.NewHealthClient(conn)
+	if _, err := health.Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+		t.Errorf("expected health checking without a key, got %v", err)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	limits := &ratelimit.Limits{
+		Crawl: ratelimit.New(1, time.Hour, 1),
+		Jobs:  ratelimit.New(1, time.Hour, 1),
+	}
+	conn := newTestClientWithDeps(t, Dependencies{
+		CrawlerService: &fakeCrawler{payloads: make(chan crawler.Payload, 1)},
+		RateLimits:     limits,
+	})
+	client := crawlerv1.NewCrawlerServiceClient(conn)
+	req := &crawlerv1.CrawlRequest{Url: "https://ufape.edu.br"}
+
+	var header metadata.MD
+	if _, err := client.Crawl(context.Background(), req, grpc.Header(&header)); err != nil {
+		t.Fatalf("expected the first crawl to be allowed, got %v", err)
+	}
+	if got := header.Get("ratelimit-remaining"); len(got) != 1 || got[0] != "0" {
+		t.Errorf("expected ratelimit-remaining 0, got %v", got)
+	}
+
+	header = nil
+	_, err := client.Crawl(context.Background(), req, grpc.Header(&header))
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected ResourceExhausted after the burst, got %v", err)
+	}
+	if got := header.Get("retry-after"); len(got) != 1 || got[0] != "3600" {
+		t.Errorf("expected retry-after 3600, got %v", got)
+	}
+
+	for i, want := range []codes.Code{codes.OK, codes.ResourceExhausted} {
+		stream, err := client.CrawlSite(context.Background(), &crawlerv1.CrawlSiteRequest{SeedUrl: "https://ufape.edu.br"})
+		if err == nil {
+			_, err = stream.Recv()
+		}
+		if status.Code(err) != want {
+			t.Errorf("CrawlSite %d: expected %s, got %v", i, want, err)
+		}
+	}
+
+	health := healthpb.NewHealthClient(conn)
+	for range 2 {
+		if _, err := health.Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+			t.Errorf("expected health checking not to be limited, got %v", err)
+		}
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
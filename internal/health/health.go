@@ -0,0 +1,193 @@
+// Package health verifica as dependências da aplicação para o endpoint de
+// prontidão (/readyz).
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Status é o estado de uma verificação ou do relatório completo.
+type Status string
+
+const (
+	// StatusOK indica que a dependência responde normalmente.
+	StatusOK Status = "ok"
+	// StatusDegraded indica que a aplicação atende, mas com restrições.
+	StatusDegraded Status = "degraded"
+	// StatusUnavailable indica que a aplicação não deve receber tráfego.
+	StatusUnavailable Status = "unavailable"
+)
+
+// DefaultTimeout limita cada verificação quando o Checker não define outro.
+const DefaultTimeout = 2 * time.Second
+
+// Result é o resultado de uma verificação.
+type Result struct {
+	Status Status `json:"status" example:"ok"`
+	// Detail explica um estado diferente de ok.
+	Detail string `json:"detail,omitempty" example:"2 of 2 crawl slots in use, new crawls are rejected"`
+}
+
+// Check verifica uma dependência. Deve respeitar o cancelamento de ctx.
+type Check func(ctx context.Context) Result
+
+// Report é o resultado de todas as verificações. Status é o pior estado entre elas.
+type Report struct {
+	Status Status            `json:"status" example:"ok"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// Ready informa se a aplicação pode receber tráfego.
+func (r Report) Ready() bool {
+	return r.Status != StatusUnavailable
+}
+
+// Checker executa as verificações registradas e sabe quando a aplicação está
+// encerrando.
+type Checker struct {
+	timeout      time.Duration
+	mu           sync.RWMutex
+	names        []string
+	checks       map[string]Check
+	shuttingDown atomic.Bool
+}
+
+// NewChecker cria um Checker sem verificações. timeout <= 0 usa DefaultTimeout.
+func NewChecker(timeout time.Duration) *Checker {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Checker{timeout: timeout, checks: make(map[string]Check)}
+}
+
+// Add registra check com o nome exibido no relatório, substituindo uma
+// verificação anterior de mesmo nome.
+func (c *Checker) Add(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.checks[name]; !ok {
+		c.names = append(c.names, name)
+	}
+	c.checks[name] = check
+}
+
+// Shutdown marca a aplicação como encerrando: a partir daí o relatório é
+// sempre unavailable, para que o balanceador pare de enviar tráfego.
+func (c *Checker) Shutdown() {
+	c.shuttingDown.Store(true)
+}
+
+// Check executa as verificações em paralelo, cada uma limitada pelo timeout do
+// Checker.
+func (c *Checker) Check(ctx context.Context) Report {
+	c.mu.RLock()
+	names := append([]string(nil), c.names...)
+	checks := make([]Check, len(names))
+	for i, name := range names {
+		checks[i] = c.checks[name]
+	}
+	c.mu.RUnlock()
+
+	results := make([]Result, len(names))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, c.timeout)
+			defer cancel()
+			results[i] = check(checkCtx)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]Result, len(names)+1)}
+	for i, name := range names {
+		report.Checks[name] = results[i]
+		report.Status = worst(report.Status, results[i].Status)
+	}
+	if c.shuttingDown.Load() {
+		report.Checks["shutdown"] = Result{Status: StatusUnavailable, Detail: "server is shutting down"}
+		report.Status = StatusUnavailable
+	}
+	return report
+}
+
+func worst(a, b Status) Status {
+	rank := map[Status]int{StatusOK: 0, StatusDegraded: 1, StatusUnavailable: 2}
+	if rank[b] > rank[a] {
+		return b
+	}
+	return a
+}
+
+// Pinger é implementado por storage.Repository.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Storage verifica o backend de persistência. Sem ele a aplicação não
+// registra os crawlings, então uma falha a torna unavailable.
+func Storage(p Pinger) Check {
+	return func(ctx context.Context) Result {
+		if err := p.Ping(ctx); err != nil {
+			return Result{Status: StatusUnavailable, Detail: err.Error()}
+		}
+		return Result{Status: StatusOK}
+	}
+}
+
+// Capacity verifica a ocupação de uma fila de trabalho, como os crawlings de
+// várias páginas. Com todas as vagas ocupadas a aplicação fica degraded, pois
+// novos trabalhos são recusados.
+func Capacity(usage func() (used, limit int)) Check {
+	return func(context.Context) Result {
+		used, limit := usage()
+		if limit > 0 && used >= limit {
+			return Result{
+				Status: StatusDegraded,
+				Detail: fmt.Sprintf("%d of %d crawl slots in use, new crawls are rejected", used, limit),
+			}
+		}
+		return Result{Status: StatusOK}
+	}
+}
+
+// Proxies verifica o pool de proxies de saída: com algum proxy afastado após
+// falhas, a aplicação fica degraded; sem nenhum disponível, as buscas falham
+// até a verificação periódica devolver um proxy ao pool.
+func Proxies(available func() (available, total int)) Check {
+	return func(context.Context) Result {
+		up, total := available()
+		switch {
+		case up == 0:
+			return Result{Status: StatusDegraded, Detail: fmt.Sprintf("all %d proxies evicted, crawls fail until one recovers", total)}
+		case up < total:
+			return Result{Status: StatusDegraded, Detail: fmt.Sprintf("%d of %d proxies available", up, total)}
+		}
+		return Result{Status: StatusOK}
+	}
+}
+
+// Outbound verifica a conectividade de saída buscando url. Qualquer resposta
+// HTTP conta como sucesso; uma falha de rede deixa a aplicação degraded, já
+// que os crawlings de sites acessíveis continuam possíveis.
+func Outbound(client *http.Client, url string) Check {
+	return func(ctx context.Context) Result {
+		req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+		if err != nil {
+			return Result{Status: StatusDegraded, Detail: err.Error()}
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return Result{Status: StatusDegraded, Detail: err.Error()}
+		}
+		resp.Body.Close()
+		return Result{Status: StatusOK}
+	}
+}
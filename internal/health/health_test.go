@@ -0,0 +1,93 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type pingerFunc func(ctx context.Context) error
+
+func (f pingerFunc) Ping(ctx context.Context) error { return f(ctx) }
+
+func TestChecker(t *testing.T) {
+	t.Run("should be ok without checks", func(t *testing.T) {
+		report := NewChecker(0).Check(context.Background())
+		if report.Status != StatusOK || !report.Ready() || len(report.Checks) != 0 {
+			t.Errorf("unexpected report: %+v", report)
+		}
+	})
+
+	t.Run("should report the worst status", func(t *testing.T) {
+		c := NewChecker(0)
+		c.Add("crawls", Capacity(func() (int, int) { return 2, 2 }))
+		c.Add("storage", Storage(pingerFunc(func(context.Context) error { return nil })))
+
+		report := c.Check(context.Background())
+		if report.Status != StatusDegraded || !report.Ready() {
+			t.Errorf("expected a ready degraded report, got %+v", report)
+		}
+		if got := report.Checks["crawls"]; got.Status != StatusDegraded || got.Detail == "" {
+			t.Errorf("expected crawls degraded with details, got %+v", got)
+		}
+
+		c.Add("storage", Storage(pingerFunc(func(context.Context) error { return errors.New("connection refused") })))
+		report = c.Check(context.Background())
+		if report.Status != StatusUnavailable || report.Ready() {
+			t.Errorf("expected an unavailable report, got %+v", report)
+		}
+		if got := report.Checks["storage"]; got.Detail != "connection refused" {
+			t.Errorf("expected the storage error as detail, got %+v", got)
+		}
+		if len(report.Checks) != 2 {
+			t.Errorf("expected the replaced check to be reported once, got %+v", report.Checks)
+		}
+	})
+
+	t.Run("should bound each check by the timeout", func(t *testing.T) {
+		c := NewChecker(20 * time.Millisecond)
+		c.Add("storage", Storage(pingerFunc(func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})))
+
+		start := time.Now()
+		report := c.Check(context.Background())
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Fatalf("check took %v, expected the timeout to apply", elapsed)
+		}
+		if report.Status != StatusUnavailable {
+			t.Errorf("expected unavailable after the timeout, got %+v", report)
+		}
+	})
+
+	t.Run("should be unavailable after shutdown", func(t *testing.T) {
+		c := NewChecker(0)
+		c.Shutdown()
+
+		report := c.Check(context.Background())
+		if report.Ready() || report.Checks["shutdown"].Status != StatusUnavailable {
+			t.Errorf("expected a not-ready report during shutdown, got %+v", report)
+		}
+	})
+}
+
+func TestOutbound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	if got := Outbound(server.Client(), server.URL)(context.Background()); got.Status != StatusOK {
+		t.Errorf("expected any HTTP response to count as reachable, got %+v", got)
+	}
+
+	url := server.URL
+	server.Close()
+	if got := Outbound(http.DefaultClient, url)(context.Background()); got.Status != StatusDegraded || got.Detail == "" {
+		t.Errorf("expected degraded when unreachable, got %+v", got)
+	}
+}
@@ -0,0 +1,77 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/nettojulio/ufape-crawler-golang/internal/auth"
+	"github.com/nettojulio/ufape-crawler-golang/internal/logging"
+)
+
+// AuthHandler expõe o consumo das chaves de API.
+type AuthHandler struct {
+	store *auth.Store
+}
+
+// NewAuthHandler cria o handler de consumo das chaves de API.
+func NewAuthHandler(store *auth.Store) *AuthHandler {
+	return &AuthHandler{store: store}
+}
+
+// HandleUsage godoc
+// @Summary      Consumo das chaves de API
+// @Description  Retorna, para cada chave, as requisições e as páginas buscadas no dia corrente (UTC) e a cota restante. Requer o escopo admin.
+// @Tags         Auth
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Success      200  {array}   auth.Usage
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Router       /auth/usage [get]
+func (h *AuthHandler) HandleUsage(c echo.Context) error {
+	return c.JSON(http.StatusOK, h.store.Usage())
+}
+
+// requireScope autentica a requisição pela chave de API e exige o escopo
+// informado. O cliente autenticado fica no contexto da requisição (veja
+// auth.CallerFromContext) e o ID da chave passa a constar no log. Com store
+// nil, a autenticação está desabilitada e a requisição segue sem cliente.
+func requireScope(store *auth.Store, scope auth.Scope) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		if store == nil {
+			return next
+		}
+		return func(c echo.Context) error {
+			req := c.Request()
+			token := auth.TokenFromHeaders(req.Header.Get("X-API-Key"), req.Header.Get(echo.HeaderAuthorization))
+			if token == "" {
+				token = auth.TokenFromWebSocketProtocol(req.Header.Get("Sec-WebSocket-Protocol"))
+			}
+			key, err := store.Authenticate(token)
+			if err != nil {
+				c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer realm="ufape-crawler"`)
+				return c.JSON(http.StatusUnauthorized, echo.Map{"error": err.Error()})
+			}
+			if !key.Allows(scope) {
+				return c.JSON(http.StatusForbidden, echo.Map{"error": auth.ErrForbidden.Error()})
+			}
+
+			ctx := auth.WithCaller(req.Context(), store, key)
+			ctx = logging.WithContext(ctx, logging.FromContext(ctx, nil).With("api_key", key.ID))
+			c.SetRequest(req.WithContext(ctx))
+			return next(c)
+		}
+	}
+}
+
+// chargeQuota desconta pages da cota do cliente da requisição, respondendo
+// 429 quando ela não basta. Retorna false quando a resposta já foi enviada.
+func chargeQuota(c echo.Context, pages int) (bool, error) {
+	err := auth.CallerFromContext(c.Request().Context()).Charge(pages)
+	if errors.Is(err, auth.ErrQuotaExceeded) {
+		return false, c.JSON(http.StatusTooManyRequests, echo.Map{"error": err.Error()})
+	}
+	return true, nil
+}
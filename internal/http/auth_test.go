@@ -0,0 +1,149 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nettojulio/ufape-crawler-golang/internal/auth"
+	"github.com/nettojulio/ufape-crawler-golang/internal/config"
+	"github.com/nettojulio/ufape-crawler-golang/internal/crawler"
+)
+
+func newAuthTestServer(t *testing.T) *echo.Echo {
+	t.Helper()
+	store, err := auth.NewStore([]auth.Key{
+		{ID: "ci", Key: "ci-secret", Scopes: []auth.Scope{auth.ScopeCrawl}, DailyQuota: 2},
+		{ID: "ops", Key: "ops-secret", Scopes: []auth.Scope{auth.ScopeAdmin}},
+	})
+	assert.NoError(t, err)
+
+	cfg := &config.Config{Batch: config.BatchConfig{MaxItems: 10, Concurrency: 2}}
+	return NewServer(cfg, Dependencies{
+		CrawlerService: crawler.NewService(crawler.NewHTTPClient(5 * time.Second)),
+		Auth:           store,
+	})
+}
+
+func authRequest(e *echo.Echo, method, target, body string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAuthentication(t *testing.T) {
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(echo.HeaderContentType, echo.MIMETextHTMLCharsetUTF8)
+		w.Write([]byte("<html><head><title>Página de Teste</title></head></html>"))
+	}))
+	defer site.Close()
+	crawlBody := `{"url": "` + site.URL + `"}`
+
+	t.Run("Cenário de Falha - Requisição sem Chave", func(t *testing.T) {
+		e := newAuthTestServer(t)
+		rec := authRequest(e, http.MethodPost, "/", crawlBody, nil)
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.NotEmpty(t, rec.Header().Get(echo.HeaderWWWAuthenticate))
+		assert.Contains(t, rec.Body.String(), auth.ErrMissingKey.Error())
+	})
+
+	t.Run("Cenário de Falha - Chave Inválida", func(t *testing.T) {
+		e := newAuthTestServer(t)
+		rec := authRequest(e, http.MethodPost, "/", crawlBody, map[string]string{"X-API-Key": "wrong"})
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Contains(t, rec.Body.String(), auth.ErrInvalidKey.Error())
+	})
+
+	t.Run("Cenário de Falha - Chave sem o Escopo", func(t *testing.T) {
+		e := newAuthTestServer(t)
+		rec := authRequest(e, http.MethodGet, "/auth/usage", "", map[string]string{"X-API-Key": "ci-secret"})
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("Cenário de Sucesso - Chave no Cabeçalho e como Bearer", func(t *testing.T) {
+		e := newAuthTestServer(t)
+		rec := authRequest(e, http.MethodPost, "/", crawlBody, map[string]string{"X-API-Key": "ci-secret"})
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		rec = authRequest(e, http.MethodPost, "/", crawlBody, map[string]string{echo.HeaderAuthorization: "Bearer ci-secret"})
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("Cenário de Falha - Cota Diária Esgotada", func(t *testing.T) {
+		e := newAuthTestServer(t)
+		headers := map[string]string{"X-API-Key": "ci-secret"}
+		batch := `{"items": [{"url": "` + site.URL + `"}, {"url": "` + site.URL + `/a"}, {"url": "` + site.URL + `/b"}]}`
+
+		rec := authRequest(e, http.MethodPost, "/batch", batch, headers)
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code, "Um lote maior que a cota restante deveria ser recusado por inteiro")
+
+		for range 2 {
+			assert.Equal(t, http.StatusOK, authRequest(e, http.MethodPost, "/", crawlBody, headers).Code)
+		}
+		rec = authRequest(e, http.MethodPost, "/", crawlBody, headers)
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+		assert.Contains(t, rec.Body.String(), auth.ErrQuotaExceeded.Error())
+	})
+
+	t.Run("Cenário de Falha - Cota Cobrada por Tentativa", func(t *testing.T) {
+		e := newAuthTestServer(t)
+		headers := map[string]string{"X-API-Key": "ci-secret"}
+
+		rec := authRequest(e, http.MethodPost, "/", `{"url": "`+site.URL+`", "max_attempts": 11}`, headers)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, "max_attempts acima do limite deveria ser recusado")
+
+		rec = authRequest(e, http.MethodPost, "/", `{"url": "`+site.URL+`", "max_attempts": 3}`, headers)
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code, "Três tentativas não cabem em uma cota de duas páginas")
+
+		batch := `{"items": [{"url": "` + site.URL + `"}], "defaults": {"max_attempts": 3}}`
+		rec = authRequest(e, http.MethodPost, "/batch", batch, headers)
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code, "As tentativas dos itens do lote deveriam ser somadas")
+
+		rec = authRequest(e, http.MethodPost, "/", `{"url": "`+site.URL+`", "max_attempts": 2}`, headers)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, http.StatusTooManyRequests, authRequest(e, http.MethodPost, "/", crawlBody, headers).Code)
+	})
+
+	t.Run("Cenário de Sucesso - Consumo por Chave", func(t *testing.T) {
+		e := newAuthTestServer(t)
+		authRequest(e, http.MethodPost, "/", crawlBody, map[string]string{"X-API-Key": "ci-secret"})
+
+		rec := authRequest(e, http.MethodGet, "/auth/usage", "", map[string]string{"X-API-Key": "ops-secret"})
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var usage []auth.Usage
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &usage))
+		if assert.Len(t, usage, 2) {
+			assert.Equal(t, "ci", usage[0].KeyID)
+			assert.Equal(t, 1, usage[0].Pages)
+			assert.Equal(t, 1, usage[0].Remaining)
+			assert.Equal(t, 1, usage[1].Requests)
+		}
+	})
+
+	t.Run("Cenário de Sucesso - Rotas Públicas e Inexistentes", func(t *testing.T) {
+		e := newAuthTestServer(t)
+		assert.Equal(t, http.StatusOK, authRequest(e, http.MethodGet, "/healthz", "", nil).Code)
+		assert.Equal(t, http.StatusNotFound, authRequest(e, http.MethodGet, "/nao-existe", "", nil).Code)
+	})
+
+	t.Run("Cenário de Falha - Métricas sem Chave Admin", func(t *testing.T) {
+		e := newAuthTestServer(t)
+		assert.Equal(t, http.StatusUnauthorized, authRequest(e, http.MethodGet, "/metrics", "", nil).Code)
+		assert.Equal(t, http.StatusForbidden, authRequest(e, http.MethodGet, "/metrics", "", map[string]string{"X-API-Key": "ci-secret"}).Code)
+		assert.Equal(t, http.StatusOK, authRequest(e, http.MethodGet, "/metrics", "", map[string]string{"X-API-Key": "ops-secret"}).Code)
+	})
+}
@@ -0,0 +1,177 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+	"github.com/nettojulio/ufape-crawler-golang/internal/crawler"
+	"github.com/nettojulio/ufape-crawler-golang/internal/logging"
+)
+
+// MIMEApplicationNDJSON é o tipo de conteúdo das respostas em fluxo.
+const MIMEApplicationNDJSON = "application/x-ndjson"
+
+type BatchHandler struct {
+	crawlerHandler *CrawlerHandler
+	maxItems       int
+	// slots limita quantos itens são buscados ao mesmo tempo, somando todos os lotes em andamento.
+	slots chan struct{}
+}
+
+// NewBatchHandler cria o handler de crawling em lote, aceitando até maxItems
+// itens por requisição e no máximo concurrency buscas simultâneas no servidor.
+func NewBatchHandler(crawlerHandler *CrawlerHandler, maxItems, concurrency int) *BatchHandler {
+	return &BatchHandler{
+		crawlerHandler: crawlerHandler,
+		maxItems:       maxItems,
+		slots:          make(chan struct{}, max(concurrency, 1)),
+	}
+}
+
+// HandleBatch godoc
+// @Summary      Executa o crawling de várias URLs
+// @Description  Recebe uma lista de payloads e opções compartilhadas em defaults, aplicadas aos campos omitidos em cada item. Os itens são buscados em paralelo, respeitando o limite de concorrência do servidor. Falhas de um item são retornadas no próprio item, sem falhar o lote. Com stream, a resposta é NDJSON com um resultado por linha, na ordem em que terminam.
+// @Tags         Crawler
+// @Accept       json
+// @Produce      json
+// @Produce      application/x-ndjson
+// @Param        payload body crawler.BatchPayload true "Itens do lote - URL é obrigatório em cada item"
+// @Success      200  {object}  crawler.BatchResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      429  {object}  map[string]string
+// @Security     ApiKeyAuth
+// @Router       /batch [post]
+func (h *BatchHandler) HandleBatch(c echo.Context) error {
+	var batch crawler.BatchPayload
+	if err := c.Bind(&batch); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "invalid request body"})
+	}
+	if err := c.Validate(&batch); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+	}
+	if len(batch.Items) > h.maxItems {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": fmt.Sprintf("batch exceeds the limit of %d items", h.maxItems)})
+	}
+	if ok, err := chargeQuota(c, batchAttempts(c, batch)); !ok {
+		return err
+	}
+
+	results := h.run(c, batch)
+
+	if batch.Stream {
+		return streamResults(c, results)
+	}
+
+	response := crawler.BatchResponse{Results: make([]crawler.BatchItemResult, len(batch.Items))}
+	for r := range results {
+		response.Results[r.Index] = r
+		if r.Error != "" {
+			response.Failed++
+		} else {
+			response.Succeeded++
+		}
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// batchAttempts soma as tentativas dos itens do lote, o que eles consomem da
+// cota. Os itens inválidos não são buscados e não contam.
+func batchAttempts(c echo.Context, batch crawler.BatchPayload) int {
+	var defaults crawler.Payload
+	if batch.Defaults != nil {
+		defaults = *batch.Defaults
+	}
+	total := 0
+	for _, item := range batch.Items {
+		payload := crawler.MergePayload(item, defaults)
+		if c.Validate(&payload) == nil {
+			total += crawler.Attempts(payload)
+		}
+	}
+	return total
+}
+
+// run busca todos os itens do lote e entrega cada resultado assim que termina.
+// O canal é fechado depois do último item.
+func (h *BatchHandler) run(c echo.Context, batch crawler.BatchPayload) <-chan crawler.BatchItemResult {
+	var defaults crawler.Payload
+	if batch.Defaults != nil {
+		defaults = *batch.Defaults
+	}
+
+	results := make(chan crawler.BatchItemResult)
+	var wg sync.WaitGroup
+	for i, item := range batch.Items {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results <- h.crawlItem(c, i, crawler.MergePayload(item, defaults))
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+	return results
+}
+
+func (h *BatchHandler) crawlItem(c echo.Context, index int, payload crawler.Payload) crawler.BatchItemResult {
+	result := crawler.BatchItemResult{Index: index, URL: payload.Url}
+	if err := c.Validate(&payload); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	ctx := c.Request().Context()
+	if err := h.acquire(ctx); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	defer h.release()
+
+	ctx = logging.WithContext(ctx, logging.FromContext(ctx, nil).With("batch_index", index))
+	response, err := h.crawlerHandler.crawl(ctx, payload)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Result = response
+	return result
+}
+
+func (h *BatchHandler) acquire(ctx context.Context) error {
+	select {
+	case h.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (h *BatchHandler) release() {
+	<-h.slots
+}
+
+// streamResults escreve cada resultado como uma linha NDJSON. Depois que o
+// cliente se desconecta, os resultados restantes são descartados.
+func streamResults(c echo.Context, results <-chan crawler.BatchItemResult) error {
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, MIMEApplicationNDJSON)
+	res.WriteHeader(http.StatusOK)
+
+	enc := json.NewEncoder(res)
+	var writeErr error
+	for r := range results {
+		if writeErr != nil {
+			continue
+		}
+		if writeErr = enc.Encode(r); writeErr == nil {
+			res.Flush()
+		}
+	}
+	return nil
+}
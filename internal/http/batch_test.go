@@ -0,0 +1,136 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/nettojulio/ufape-crawler-golang/internal/crawler"
+	"github.com/stretchr/testify/assert"
+)
+
+// concurrentCrawlerService devolve o título da URL e mede a concorrência máxima.
+type concurrentCrawlerService struct {
+	mu       sync.Mutex
+	active   int
+	peak     int
+	payloads []crawler.Payload
+}
+
+func (m *concurrentCrawlerService) Crawl(ctx context.Context, payload crawler.Payload, originalURL, modifiedURL *url.URL) (*crawler.CrawlResult, error) {
+	m.mu.Lock()
+	m.active++
+	m.peak = max(m.peak, m.active)
+	m.payloads = append(m.payloads, payload)
+	m.mu.Unlock()
+
+	time.Sleep(20 * time.Millisecond)
+
+	m.mu.Lock()
+	m.active--
+	m.mu.Unlock()
+	return &crawler.CrawlResult{StatusCode: http.StatusOK, Title: originalURL.Path, FinalURL: modifiedURL}, nil
+}
+
+func newBatchTestServer(service CrawlerServicer, maxItems, concurrency int) *echo.Echo {
+	e := echo.New()
+	e.Validator = &CustomValidator{validator: validator.New()}
+	e.POST("/batch", NewBatchHandler(NewCrawlerHandler(service, nil), maxItems, concurrency).HandleBatch)
+	return e
+}
+
+func postBatch(e *echo.Echo, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/batch", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestBatchHandler_HandleBatch(t *testing.T) {
+	t.Run("Cenário de Sucesso - Resultados em Ordem com Falha Parcial", func(t *testing.T) {
+		service := &concurrentCrawlerService{}
+		e := newBatchTestServer(service, 10, 2)
+		reqBody := `{
+			"defaults": {"timeout": 5, "allowed_domains": ["example.com"]},
+			"items": [
+				{"url": "http://example.com/a"},
+				{"timeout": 10},
+				{"url": "http://example.com/c", "timeout": 30},
+				{"url": "http://example.com/d"},
+				{"url": "http://example.com/e"}
+			]
+		}`
+
+		rec := postBatch(e, reqBody)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var response crawler.BatchResponse
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, 4, response.Succeeded)
+		assert.Equal(t, 1, response.Failed)
+		if assert.Len(t, response.Results, 5) {
+			assert.Equal(t, "/a", response.Results[0].Result.Title, "Os resultados deveriam seguir a ordem dos itens")
+			assert.Contains(t, response.Results[1].Error, "'Url'", "O item sem URL deveria falhar isoladamente")
+			assert.Equal(t, "/e", response.Results[4].Result.Title)
+		}
+		assert.LessOrEqual(t, service.peak, 2, "A concorrência deveria respeitar o limite do servidor")
+
+		for _, p := range service.payloads {
+			if strings.HasSuffix(p.Url, "/c") {
+				assert.Equal(t, 30, *p.Timeout, "As opções do item deveriam prevalecer sobre defaults")
+			} else {
+				assert.Equal(t, 5, *p.Timeout, "Os campos omitidos deveriam vir de defaults")
+			}
+		}
+	})
+
+	t.Run("Cenário de Sucesso - Resultados em NDJSON", func(t *testing.T) {
+		e := newBatchTestServer(&concurrentCrawlerService{}, 10, 4)
+		reqBody := `{"stream": true, "items": [{"url": "http://example.com/a"}, {"url": "http://example.com/b"}, {"url": "::"}]}`
+
+		rec := postBatch(e, reqBody)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, MIMEApplicationNDJSON, rec.Header().Get(echo.HeaderContentType))
+		seen := map[int]bool{}
+		scanner := bufio.NewScanner(rec.Body)
+		for scanner.Scan() {
+			var r crawler.BatchItemResult
+			assert.NoError(t, json.Unmarshal(scanner.Bytes(), &r), "Cada linha deveria ser um JSON válido")
+			seen[r.Index] = true
+		}
+		assert.Len(t, seen, 3, "Cada item deveria gerar exatamente uma linha")
+	})
+
+	t.Run("Cenário de Falha - Lote Acima do Limite", func(t *testing.T) {
+		e := newBatchTestServer(&concurrentCrawlerService{}, 2, 1)
+		items := make([]string, 3)
+		for i := range items {
+			items[i] = fmt.Sprintf(`{"url": "http://example.com/%d"}`, i)
+		}
+
+		rec := postBatch(e, `{"items": [`+strings.Join(items, ",")+`]}`)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "limit of 2 items")
+	})
+
+	t.Run("Cenário de Falha - Lote Vazio", func(t *testing.T) {
+		e := newBatchTestServer(&concurrentCrawlerService{}, 10, 1)
+
+		rec := postBatch(e, `{"items": []}`)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
@@ -2,13 +2,15 @@ package http
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/url"
-	"strings"
 	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/nettojulio/ufape-crawler-golang/internal/crawler"
+	"github.com/nettojulio/ufape-crawler-golang/internal/logging"
+	"github.com/nettojulio/ufape-crawler-golang/internal/storage"
 )
 
 type CrawlerServicer interface {
@@ -17,11 +19,15 @@ type CrawlerServicer interface {
 
 type CrawlerHandler struct {
 	crawlerService CrawlerServicer
+	repository     storage.Repository
 }
 
-func NewCrawlerHandler(cs CrawlerServicer) *CrawlerHandler {
+// NewCrawlerHandler cria o handler de crawling. repo pode ser nil quando a
+// persistência está desabilitada.
+func NewCrawlerHandler(cs CrawlerServicer, repo storage.Repository) *CrawlerHandler {
 	return &CrawlerHandler{
 		crawlerService: cs,
+		repository:     repo,
 	}
 }
 
@@ -33,6 +39,8 @@ func NewCrawlerHandler(cs CrawlerServicer) *CrawlerHandler {
 // @Produce      json
 // @Param        payload body crawler.Payload true "Configurações do Crawler - URL é obrigatório"
 // @Success      200  {object}  crawler.ResponseDTO
+// @Failure      429  {object}  map[string]string
+// @Security     ApiKeyAuth
 // @Router       / [post]
 func (h *CrawlerHandler) HandleCrawl(c echo.Context) error {
 	var payload crawler.Payload
@@ -42,120 +50,65 @@ func (h *CrawlerHandler) HandleCrawl(c echo.Context) error {
 	if err := c.Validate(&payload); err != nil {
 		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
 	}
-
-	originalURL, err := url.Parse(payload.Url)
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, echo.Map{"error": "invalid url"})
-	}
-
-	modifiedURL := prepareURLAndDefaults(&payload, originalURL)
-
-	var result *crawler.CrawlResult
-	for attempt := 1; attempt <= *payload.MaxAttempts; attempt++ {
-		result, err = h.crawlerService.Crawl(c.Request().Context(), payload, originalURL, modifiedURL)
-
-		if err != nil || (result.StatusCode != http.StatusOK && result.StatusCode != http.StatusNotFound) {
-			if attempt < *payload.MaxAttempts {
-				time.Sleep(1 * time.Second)
-				continue
-			}
-		}
-		break
+	if ok, err := chargeQuota(c, crawler.Attempts(payload)); !ok {
+		return err
 	}
 
+	responseDTO, err := h.crawl(c.Request().Context(), payload)
 	if err != nil {
+		if errors.Is(err, errInvalidURL) {
+			return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+		}
 		return c.JSON(http.StatusServiceUnavailable, echo.Map{"error": err.Error()})
 	}
-
-	responseDTO := mapResultToDTO(result, originalURL)
 	return c.JSON(http.StatusOK, responseDTO)
 }
 
-func prepareURLAndDefaults(payload *crawler.Payload, originalURL *url.URL) *url.URL {
-	if payload.Timeout == nil {
-		def := 60
-		payload.Timeout = &def
-	}
-	if payload.RemoveFragment == nil {
-		def := true
-		payload.RemoveFragment = &def
-	}
-	if payload.AllowedDomains == nil {
-		def := []string{originalURL.Host}
-		payload.AllowedDomains = &def
-	}
-	if payload.CollectSubdomains == nil {
-		def := true
-		payload.CollectSubdomains = &def
-	}
-	if payload.LowerCaseURLs == nil {
-		def := false
-		payload.LowerCaseURLs = &def
-	}
-	if payload.CanRetry == nil {
-		def := false
-		payload.CanRetry = &def
-	}
-	if payload.MaxAttempts == nil {
-		def := 1
-		if *payload.CanRetry {
-			def = 10
-		}
-		payload.MaxAttempts = &def
-	}
+var errInvalidURL = errors.New("invalid url")
 
-	for i, domain := range *payload.AllowedDomains {
-		(*payload.AllowedDomains)[i] = strings.TrimPrefix(domain, "www.")
+// crawl executa o crawling de um payload já validado, registrando a busca. O
+// logger da requisição em ctx recebe a URL, que passa a identificar as linhas
+// registradas pelo crawler.
+func (h *CrawlerHandler) crawl(ctx context.Context, payload crawler.Payload) (*crawler.ResponseDTO, error) {
+	originalURL, err := url.Parse(payload.Url)
+	if err != nil {
+		return nil, errInvalidURL
 	}
+	ctx = logging.WithContext(ctx, logging.FromContext(ctx, nil).With("url", payload.Url))
 
-	normalizedStr := crawler.NormalizeURL(originalURL.String(), *payload.RemoveFragment, *payload.LowerCaseURLs)
-	modifiedURL, _ := url.Parse(normalizedStr)
-	return modifiedURL
-}
+	modifiedURL := crawler.PrepareURLAndDefaults(&payload, originalURL)
 
-// mapResultToDTO converte o resultado interno do crawler para o DTO da API.
-func mapResultToDTO(result *crawler.CrawlResult, originalURL *url.URL) crawler.ResponseDTO {
-	return crawler.ResponseDTO{
-		StatusCode:  result.StatusCode,
-		ContentType: result.ContentType,
-		ElapsedTime: result.ElapsedTime.Nanoseconds(),
-		Links:       result.Links,
-		Title:       result.Title,
-		Details: crawler.DetailsResponseDTO{
-			CorrectURL: result.FinalURL.String(),
-			Original:   mapURLToDetails(originalURL),
-			Modified:   mapURLToDetails(result.FinalURL),
-		},
+	result, err := crawler.CrawlWithRetry(ctx, h.crawlerService, payload, originalURL, modifiedURL, crawler.CurrentDefaults().RetryDelay)
+	if err != nil {
+		return nil, err
 	}
+
+	h.recordFetch(ctx, modifiedURL, result)
+
+	responseDTO := crawler.NewResponseDTO(result, originalURL)
+	responseDTO.AttachBody(result, payload)
+	return &responseDTO, nil
 }
 
-// mapURLToDetails converte uma url.URL para a struct de detalhes do DTO.
-func mapURLToDetails(u *url.URL) crawler.URLDetails {
-	if u == nil {
-		return crawler.URLDetails{}
+// recordFetch grava a busca no repositório, sem falhar a requisição em caso de erro.
+func (h *CrawlerHandler) recordFetch(ctx context.Context, requestedURL *url.URL, result *crawler.CrawlResult) {
+	if h.repository == nil {
+		return
 	}
 
-	details := crawler.URLDetails{
-		Scheme:      u.Scheme,
-		Opaque:      u.Opaque,
-		Host:        u.Host,
-		Path:        u.Path,
-		RawPath:     u.RawPath,
-		OmitHost:    u.OmitHost,
-		ForceQuery:  u.ForceQuery,
-		RawQuery:    u.RawQuery,
-		Fragment:    u.Fragment,
-		RawFragment: u.RawFragment,
+	fetch := storage.Fetch{
+		URL:         requestedURL.String(),
+		FinalURL:    result.FinalURL.String(),
+		StatusCode:  result.StatusCode,
+		ContentType: result.ContentType,
+		ElapsedTime: result.ElapsedTime,
+		ErrorClass:  string(result.ErrorClass),
+		FetchedAt:   time.Now().UTC(),
 	}
-
-	if u.User != nil {
-		password, isSet := u.User.Password()
-		details.User = &crawler.UserURLDetails{
-			Username:    u.User.Username(),
-			Password:    password,
-			PasswordSet: isSet,
-		}
+	if result.ErrorClass != "" {
+		fetch.Error = result.Title
+	}
+	if err := h.repository.SaveFetch(ctx, fetch); err != nil {
+		logging.FromContext(ctx, nil).ErrorContext(ctx, "failed to record fetch", "error", err)
 	}
-
-	return details
 }
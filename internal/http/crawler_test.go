@@ -14,6 +14,7 @@ import (
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
 	"github.com/nettojulio/ufape-crawler-golang/internal/crawler"
+	"github.com/nettojulio/ufape-crawler-golang/internal/storage"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -39,7 +40,7 @@ func TestCrawlerHandler_HandleCrawl(t *testing.T) {
 			FinalURL:    func() *url.URL { u, _ := url.Parse("http://example.com"); return u }(),
 		}
 		mockService := &mockCrawlerService{resultToReturn: mockResult}
-		handler := NewCrawlerHandler(mockService)
+		handler := NewCrawlerHandler(mockService, nil)
 		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(reqBody))
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		rec := httptest.NewRecorder()
@@ -53,10 +54,95 @@ func TestCrawlerHandler_HandleCrawl(t *testing.T) {
 		assert.Equal(t, "Página de Teste", responseDTO.Title, "O título na resposta está incorreto")
 	})
 
+	t.Run("Cenário de Sucesso - Busca Registrada no Repositório", func(t *testing.T) {
+		reqBody := `{"url": "http://example.com"}`
+		mockResult := &crawler.CrawlResult{
+			StatusCode:  http.StatusOK,
+			ContentType: "text/html",
+			ElapsedTime: 50 * time.Millisecond,
+			FinalURL:    func() *url.URL { u, _ := url.Parse("http://example.com/home"); return u }(),
+		}
+		repo, err := storage.NewFileStore(t.TempDir())
+		assert.NoError(t, err)
+		handler := NewCrawlerHandler(&mockCrawlerService{resultToReturn: mockResult}, repo)
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(reqBody))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err = handler.HandleCrawl(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		fetches, err := repo.ListFetches(context.Background(), "")
+		assert.NoError(t, err)
+		if assert.Len(t, fetches, 1, "A busca deveria ser registrada no repositório") {
+			assert.Equal(t, "http://example.com", fetches[0].URL)
+			assert.Equal(t, "http://example.com/home", fetches[0].FinalURL)
+			assert.Equal(t, 50*time.Millisecond, fetches[0].ElapsedTime)
+		}
+	})
+
+	t.Run("Cenário de Sucesso - Corpo Incluído na Resposta", func(t *testing.T) {
+		reqBody := `{"url": "http://example.com", "include_body": "text", "max_body_size": 4}`
+		mockResult := &crawler.CrawlResult{
+			StatusCode: http.StatusOK,
+			Body:       []byte("<html></html>"),
+			FinalURL:   func() *url.URL { u, _ := url.Parse("http://example.com"); return u }(),
+		}
+		handler := NewCrawlerHandler(&mockCrawlerService{resultToReturn: mockResult}, nil)
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(reqBody))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.HandleCrawl(c)
+
+		assert.NoError(t, err)
+		var responseDTO crawler.ResponseDTO
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &responseDTO))
+		assert.Equal(t, "<htm", responseDTO.Body, "O corpo deveria respeitar max_body_size")
+		assert.Equal(t, crawler.BodyText, responseDTO.BodyEncoding)
+		assert.True(t, responseDTO.BodyTruncated, "O corpo cortado deveria ser sinalizado")
+	})
+
+	t.Run("Cenário de Falha - Modo de Corpo Inválido", func(t *testing.T) {
+		reqBody := `{"url": "http://example.com", "include_body": "xml"}`
+		handler := NewCrawlerHandler(&mockCrawlerService{}, nil)
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(reqBody))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.HandleCrawl(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, "Modos desconhecidos de include_body deveriam ser rejeitados")
+	})
+
+	t.Run("Cenário de Falha - Opções da Requisição Inválidas", func(t *testing.T) {
+		for _, reqBody := range []string{
+			`{"url": "http://example.com", "timeout": 0}`,
+			`{"url": "http://example.com", "basic_auth": {"password": "senha"}}`,
+			`{"url": "http://example.com", "headers": {"": "valor"}}`,
+		} {
+			handler := NewCrawlerHandler(&mockCrawlerService{}, nil)
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(reqBody))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := handler.HandleCrawl(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, rec.Code, "O payload %s deveria ser rejeitado", reqBody)
+		}
+	})
+
 	t.Run("Cenário de Falha - URL Ausente na Requisição", func(t *testing.T) {
 		reqBody := `{"timeout": 10}`
 		mockService := &mockCrawlerService{}
-		handler := NewCrawlerHandler(mockService)
+		handler := NewCrawlerHandler(mockService, nil)
 		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(reqBody))
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		rec := httptest.NewRecorder()
@@ -76,7 +162,7 @@ func TestCrawlerHandler_HandleCrawl(t *testing.T) {
 		reqBody := `{"url": "http://failing-site.com"}`
 		expectedErr := errors.New("falha de conexão simulada")
 		mockService := &mockCrawlerService{errorToReturn: expectedErr}
-		handler := NewCrawlerHandler(mockService)
+		handler := NewCrawlerHandler(mockService, nil)
 		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(reqBody))
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 		rec := httptest.NewRecorder()
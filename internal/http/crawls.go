@@ -0,0 +1,266 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/nettojulio/ufape-crawler-golang/internal/auth"
+	"github.com/nettojulio/ufape-crawler-golang/internal/live"
+	"golang.org/x/net/websocket"
+)
+
+// sseHeartbeat é o intervalo dos comentários que mantêm a conexão SSE aberta.
+const sseHeartbeat = 15 * time.Second
+
+// wsProtocol é o subprotocolo aceito no WebSocket. Os navegadores enviam a
+// chave de API em outro subprotocolo (veja auth.WebSocketKeyPrefix) e
+// precisam receber um dos pedidos de volta.
+const wsProtocol = "ufape-crawler"
+
+type CrawlJobManager interface {
+	Start(req live.JobRequest) (live.Job, error)
+	Get(id, owner string) (live.Job, error)
+	List(owner string) []live.Job
+	Cancel(id, owner string) error
+	Subscribe(id, owner string, after int64) (<-chan live.Message, func(), error)
+}
+
+type CrawlJobHandler struct {
+	jobs CrawlJobManager
+}
+
+func NewCrawlJobHandler(jobs CrawlJobManager) *CrawlJobHandler {
+	return &CrawlJobHandler{jobs: jobs}
+}
+
+// HandleStart godoc
+// @Summary      Inicia um crawling de várias páginas
+// @Description  Percorre o site em largura a partir de seedUrl, em segundo plano. O progresso pode ser acompanhado em /crawls/{id}/events (SSE) ou /crawls/{id}/ws (WebSocket).
+// @Tags         Crawls
+// @Accept       json
+// @Produce      json
+// @Param        crawl body live.JobRequest true "Crawling - seedUrl é obrigatório"
+// @Success      202  {object}  live.Job
+// @Failure      400  {object}  map[string]string
+// @Failure      429  {object}  map[string]string
+// @Security     ApiKeyAuth
+// @Router       /crawls [post]
+func (h *CrawlJobHandler) HandleStart(c echo.Context) error {
+	var req live.JobRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "invalid request body"})
+	}
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+	}
+	if caller := auth.CallerFromContext(c.Request().Context()); caller != nil {
+		if ok, err := chargeQuota(c, 0); !ok {
+			return err
+		}
+		req.Owner = caller.Owner()
+		req.Charge = caller.Charge
+	}
+
+	job, err := h.jobs.Start(req)
+	if errors.Is(err, live.ErrTooManyJobs) {
+		return c.JSON(http.StatusTooManyRequests, echo.Map{"error": err.Error()})
+	}
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+	}
+	return c.JSON(http.StatusAccepted, job)
+}
+
+// HandleList godoc
+// @Summary      Lista os crawlings de várias páginas
+// @Tags         Crawls
+// @Produce      json
+// @Success      200  {array}  live.Job
+// @Security     ApiKeyAuth
+// @Router       /crawls [get]
+func (h *CrawlJobHandler) HandleList(c echo.Context) error {
+	return c.JSON(http.StatusOK, h.jobs.List(visibleOwner(c)))
+}
+
+// HandleGet godoc
+// @Summary      Consulta um crawling de várias páginas
+// @Description  Retorna o status e os contadores de progresso do crawling.
+// @Tags         Crawls
+// @Produce      json
+// @Param        id   path      string  true  "ID do crawling"
+// @Success      200  {object}  live.Job
+// @Failure      404  {object}  map[string]string
+// @Security     ApiKeyAuth
+// @Router       /crawls/{id} [get]
+func (h *CrawlJobHandler) HandleGet(c echo.Context) error {
+	job, err := h.jobs.Get(c.Param("id"), visibleOwner(c))
+	if err != nil {
+		return crawlJobError(c, err)
+	}
+	return c.JSON(http.StatusOK, job)
+}
+
+// HandleCancel godoc
+// @Summary      Cancela um crawling de várias páginas
+// @Tags         Crawls
+// @Param        id   path      string  true  "ID do crawling"
+// @Success      202
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Security     ApiKeyAuth
+// @Router       /crawls/{id} [delete]
+func (h *CrawlJobHandler) HandleCancel(c echo.Context) error {
+	if err := h.jobs.Cancel(c.Param("id"), visibleOwner(c)); err != nil {
+		return crawlJobError(c, err)
+	}
+	return c.NoContent(http.StatusAccepted)
+}
+
+// HandleEvents godoc
+// @Summary      Acompanha um crawling por Server-Sent Events
+// @Description  Envia um evento SSE por etapa do crawling (crawl_started, page_started, page_fetched, links_discovered, page_failed, crawl_finished), com os contadores de progresso. O campo id de cada evento pode ser enviado em Last-Event-ID, ou no parâmetro after, para retomar sem perder eventos. A conexão é encerrada ao fim do crawling.
+// @Tags         Crawls
+// @Produce      text/event-stream
+// @Param        id             path    string   true   "ID do crawling"
+// @Param        after          query   integer  false  "Envia apenas eventos posteriores a este id"
+// @Param        Last-Event-ID  header  string   false  "Último id recebido"
+// @Success      200  {object}  live.Message
+// @Failure      404  {object}  map[string]string
+// @Security     ApiKeyAuth
+// @Router       /crawls/{id}/events [get]
+func (h *CrawlJobHandler) HandleEvents(c echo.Context) error {
+	events, unsubscribe, err := h.jobs.Subscribe(c.Param("id"), visibleOwner(c), lastEventID(c))
+	if err != nil {
+		return crawlJobError(c, err)
+	}
+	defer unsubscribe()
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	heartbeat := time.NewTicker(sseHeartbeat)
+	defer heartbeat.Stop()
+
+	ctx := c.Request().Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(res, ": ping\n\n"); err != nil {
+				return nil
+			}
+			res.Flush()
+		case msg, ok := <-events:
+			if !ok {
+				return nil
+			}
+			data, err := json.Marshal(msg)
+			if err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintf(res, "id: %d\nevent: %s\ndata: %s\n\n", msg.Seq, msg.Type, data); err != nil {
+				return nil
+			}
+			res.Flush()
+		}
+	}
+}
+
+// HandleWebSocket godoc
+// @Summary      Acompanha um crawling por WebSocket
+// @Description  Após o handshake, envia cada evento do crawling como uma mensagem JSON, no mesmo formato do SSE, e fecha a conexão ao fim do crawling. O parâmetro after retoma a partir de um evento. Navegadores, que não definem cabeçalhos no handshake, enviam a chave nos subprotocolos ufape-crawler e api-key.<chave em base64url>.
+// @Tags         Crawls
+// @Param        id     path    string   true   "ID do crawling"
+// @Param        after  query   integer  false  "Envia apenas eventos posteriores a este seq"
+// @Success      101
+// @Failure      404  {object}  map[string]string
+// @Security     ApiKeyAuth
+// @Router       /crawls/{id}/ws [get]
+func (h *CrawlJobHandler) HandleWebSocket(c echo.Context) error {
+	events, unsubscribe, err := h.jobs.Subscribe(c.Param("id"), visibleOwner(c), lastEventID(c))
+	if err != nil {
+		return crawlJobError(c, err)
+	}
+	defer unsubscribe()
+
+	server := websocket.Server{
+		// Painéis de outras origens são aceitos; a rota não usa cookies. O
+		// subprotocolo com a chave nunca é devolvido ao cliente.
+		Handshake: func(config *websocket.Config, _ *http.Request) error {
+			if slices.Contains(config.Protocol, wsProtocol) {
+				config.Protocol = []string{wsProtocol}
+			} else {
+				config.Protocol = nil
+			}
+			return nil
+		},
+		Handler: func(ws *websocket.Conn) {
+			defer ws.Close()
+
+			// O cliente não envia mensagens; a leitura só detecta o fechamento.
+			closed := make(chan struct{})
+			go func() {
+				defer close(closed)
+				var discard []byte
+				for websocket.Message.Receive(ws, &discard) == nil {
+				}
+			}()
+
+			for {
+				select {
+				case <-closed:
+					return
+				case msg, ok := <-events:
+					if !ok {
+						return
+					}
+					if err := websocket.JSON.Send(ws, msg); err != nil {
+						return
+					}
+				}
+			}
+		},
+	}
+	server.ServeHTTP(c.Response(), c.Request())
+	return nil
+}
+
+// visibleOwner retorna o dono dos crawlings que o cliente da requisição pode
+// consultar; "" permite todos.
+func visibleOwner(c echo.Context) string {
+	return auth.CallerFromContext(c.Request().Context()).Visible()
+}
+
+// lastEventID lê o último evento recebido do cabeçalho Last-Event-ID ou do
+// parâmetro after.
+func lastEventID(c echo.Context) int64 {
+	value := c.Request().Header.Get("Last-Event-ID")
+	if value == "" {
+		value = c.QueryParam("after")
+	}
+	id, _ := strconv.ParseInt(value, 10, 64)
+	return id
+}
+
+func crawlJobError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, live.ErrNotFound):
+		return c.JSON(http.StatusNotFound, echo.Map{"error": err.Error()})
+	case errors.Is(err, live.ErrFinished):
+		return c.JSON(http.StatusConflict, echo.Map{"error": err.Error()})
+	default:
+		return c.JSON(http.StatusServiceUnavailable, echo.Map{"error": err.Error()})
+	}
+}
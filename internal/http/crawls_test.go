@@ -0,0 +1,197 @@
+package http
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/nettojulio/ufape-crawler-golang/internal/auth"
+	"github.com/nettojulio/ufape-crawler-golang/internal/crawler"
+	"github.com/nettojulio/ufape-crawler-golang/internal/live"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/websocket"
+)
+
+func newCrawlJobTestServer(t *testing.T, maxRunning int) (*echo.Echo, *live.Manager) {
+	t.Helper()
+	finalURL, _ := url.Parse("http://example.com")
+	service := &mockCrawlerService{resultToReturn: &crawler.CrawlResult{
+		StatusCode: http.StatusOK,
+		Title:      "Página de Teste",
+		FinalURL:   finalURL,
+	}}
+	jobs := live.NewManager(service, nil, live.Options{MaxRunning: maxRunning})
+	t.Cleanup(jobs.Stop)
+
+	e := echo.New()
+	e.Validator = &CustomValidator{validator: validator.New()}
+	crawlJobRoutes(e, NewCrawlJobHandler(jobs), routeGuards{})
+	return e, jobs
+}
+
+func waitJob(t *testing.T, jobs *live.Manager, id string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if job, _ := jobs.Get(id, ""); job.Status != live.StatusRunning {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("o crawling não terminou a tempo")
+}
+
+func TestCrawlJobHandler(t *testing.T) {
+	t.Run("Cenário de Sucesso - Crawling Iniciado e Eventos em SSE", func(t *testing.T) {
+		e, jobs := newCrawlJobTestServer(t, 2)
+		req := httptest.NewRequest(http.MethodPost, "/crawls", strings.NewReader(`{"seedUrl": "http://example.com", "maxDepth": 1}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+		var job live.Job
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &job))
+		assert.NotEmpty(t, job.ID)
+		waitJob(t, jobs, job.ID)
+
+		req = httptest.NewRequest(http.MethodGet, "/crawls/"+job.ID+"/events", nil)
+		rec = httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "text/event-stream", rec.Header().Get(echo.HeaderContentType))
+		body := rec.Body.String()
+		assert.True(t, strings.HasPrefix(body, "id: 1\nevent: crawl_started\ndata: {"), "O primeiro evento deveria ser crawl_started com id 1")
+		assert.Contains(t, body, "event: page_fetched\n")
+		assert.Contains(t, body, `"title":"Página de Teste"`)
+		assert.True(t, strings.HasSuffix(body, "\n\n"))
+		assert.Contains(t, body, "event: crawl_finished\n", "O fluxo deveria terminar com crawl_finished")
+
+		req = httptest.NewRequest(http.MethodGet, "/crawls/"+job.ID+"/events", nil)
+		req.Header.Set("Last-Event-ID", "1")
+		rec = httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.NotContains(t, rec.Body.String(), "event: crawl_started", "Eventos até Last-Event-ID não deveriam ser reenviados")
+	})
+
+	t.Run("Cenário de Falha - Corpo Inválido", func(t *testing.T) {
+		e, _ := newCrawlJobTestServer(t, 2)
+		req := httptest.NewRequest(http.MethodPost, "/crawls", strings.NewReader(`{"maxDepth": 1}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("Cenário de Falha - Crawling Inexistente", func(t *testing.T) {
+		e, _ := newCrawlJobTestServer(t, 2)
+		for _, path := range []string{"/crawls/nao-existe", "/crawls/nao-existe/events"} {
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+			assert.Equal(t, http.StatusNotFound, rec.Code, path)
+		}
+	})
+
+	t.Run("Cenário de Falha - Cancelamento de Crawling Encerrado", func(t *testing.T) {
+		e, jobs := newCrawlJobTestServer(t, 2)
+		job, err := jobs.Start(live.JobRequest{SeedURL: "http://example.com", MaxDepth: 1})
+		assert.NoError(t, err)
+		waitJob(t, jobs, job.ID)
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/crawls/"+job.ID, nil))
+		assert.Equal(t, http.StatusConflict, rec.Code)
+	})
+}
+
+func TestCrawlJobOwnership(t *testing.T) {
+	store, err := auth.NewStore([]auth.Key{
+		{ID: "a", Key: "a-secret", Scopes: []auth.Scope{auth.ScopeJobs}},
+		{ID: "b", Key: "b-secret", Scopes: []auth.Scope{auth.ScopeJobs}},
+		{ID: "ops", Key: "ops-secret", Scopes: []auth.Scope{auth.ScopeAdmin}},
+	})
+	assert.NoError(t, err)
+	_, jobs := newCrawlJobTestServer(t, 2)
+	e := echo.New()
+	e.Validator = &CustomValidator{validator: validator.New()}
+	crawlJobRoutes(e, NewCrawlJobHandler(jobs), routeGuards{auth: requireScope(store, auth.ScopeJobs)})
+
+	rec := authRequest(e, http.MethodPost, "/crawls", `{"seedUrl": "http://example.com", "maxDepth": 1}`, map[string]string{"X-API-Key": "a-secret"})
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	var job live.Job
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &job))
+	assert.Equal(t, "a", job.Owner)
+	waitJob(t, jobs, job.ID)
+
+	t.Run("Cenário de Falha - Crawling de Outra Chave", func(t *testing.T) {
+		other := map[string]string{"X-API-Key": "b-secret"}
+		for _, r := range []struct{ method, path string }{
+			{http.MethodGet, "/crawls/" + job.ID},
+			{http.MethodGet, "/crawls/" + job.ID + "/events"},
+			{http.MethodDelete, "/crawls/" + job.ID},
+		} {
+			assert.Equal(t, http.StatusNotFound, authRequest(e, r.method, r.path, "", other).Code, r.path)
+		}
+
+		var list []live.Job
+		assert.NoError(t, json.Unmarshal(authRequest(e, http.MethodGet, "/crawls", "", other).Body.Bytes(), &list))
+		assert.Empty(t, list, "Os crawlings de outra chave não deveriam ser listados")
+	})
+
+	t.Run("Cenário de Sucesso - Dono e Administrador", func(t *testing.T) {
+		for _, key := range []string{"a-secret", "ops-secret"} {
+			headers := map[string]string{"X-API-Key": key}
+			assert.Equal(t, http.StatusOK, authRequest(e, http.MethodGet, "/crawls/"+job.ID, "", headers).Code, key)
+
+			var list []live.Job
+			assert.NoError(t, json.Unmarshal(authRequest(e, http.MethodGet, "/crawls", "", headers).Body.Bytes(), &list))
+			assert.Len(t, list, 1, key)
+		}
+	})
+}
+
+func TestCrawlJobWebSocketAuth(t *testing.T) {
+	store, err := auth.NewStore([]auth.Key{{ID: "a", Key: "a-secret", Scopes: []auth.Scope{auth.ScopeJobs}}})
+	assert.NoError(t, err)
+	_, jobs := newCrawlJobTestServer(t, 2)
+	e := echo.New()
+	crawlJobRoutes(e, NewCrawlJobHandler(jobs), routeGuards{auth: requireScope(store, auth.ScopeJobs)})
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	job, err := jobs.Start(live.JobRequest{SeedURL: "http://example.com", MaxDepth: 1, Owner: "a"})
+	assert.NoError(t, err)
+	waitJob(t, jobs, job.ID)
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/crawls/" + job.ID + "/ws"
+
+	t.Run("Cenário de Falha - Handshake sem Chave", func(t *testing.T) {
+		_, err := websocket.Dial(wsURL, "", server.URL)
+		assert.Error(t, err)
+	})
+
+	t.Run("Cenário de Sucesso - Chave no Subprotocolo", func(t *testing.T) {
+		config, err := websocket.NewConfig(wsURL, server.URL)
+		assert.NoError(t, err)
+		config.Protocol = []string{"ufape-crawler", auth.WebSocketKeyPrefix + base64.RawURLEncoding.EncodeToString([]byte("a-secret"))}
+
+		ws, err := websocket.DialConfig(config)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer ws.Close()
+		assert.Equal(t, []string{"ufape-crawler"}, ws.Config().Protocol, "Apenas o subprotocolo sem a chave deveria ser devolvido")
+
+		var msg live.Message
+		assert.NoError(t, websocket.JSON.Receive(ws, &msg))
+		assert.Equal(t, int64(1), msg.Seq)
+	})
+}
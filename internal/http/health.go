@@ -1,13 +1,16 @@
 package http
 
 import (
+	"net/http"
+
 	"github.com/labstack/echo/v4"
 	"github.com/nettojulio/ufape-crawler-golang/internal/crawler"
+	"github.com/nettojulio/ufape-crawler-golang/internal/health"
 )
 
 // HealthCheckHandler godoc
 // @Summary      Verifica a saúde da API
-// @Description  Retorna o status "OK" e a versão atual da aplicação.
+// @Description  Retorna o status "OK" e a versão atual da aplicação. Mantido por compatibilidade; prefira GET /healthz.
 // @Tags         Health
 // @Produce      json
 // @Success      200  {object} crawler.APIHealth "API está saudável"
@@ -20,3 +23,32 @@ func HealthCheckHandler(version string) echo.HandlerFunc {
 		})
 	}
 }
+
+// LivenessHandler godoc
+// @Summary      Verifica se o processo está vivo
+// @Description  Responde sempre que o servidor atende requisições, sem verificar dependências. Use como liveness probe.
+// @Tags         Health
+// @Produce      json
+// @Success      200  {object} crawler.APIHealth "Processo está vivo"
+// @Router       /healthz [get]
+func LivenessHandler(version string) echo.HandlerFunc {
+	return HealthCheckHandler(version)
+}
+
+// ReadinessHandler godoc
+// @Summary      Verifica se a API pode receber tráfego
+// @Description  Verifica a persistência, a ocupação dos crawlings de várias páginas e, quando configurada, a conectividade de saída. Estados "degraded" respondem 200 com os detalhes; "unavailable", inclusive durante o encerramento, responde 503.
+// @Tags         Health
+// @Produce      json
+// @Success      200  {object} health.Report "Pronta, possivelmente com restrições"
+// @Failure      503  {object} health.Report "Não pronta"
+// @Router       /readyz [get]
+func ReadinessHandler(checker *health.Checker) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		report := checker.Check(c.Request().Context())
+		if !report.Ready() {
+			return c.JSON(http.StatusServiceUnavailable, report)
+		}
+		return c.JSON(http.StatusOK, report)
+	}
+}
@@ -1,13 +1,16 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/labstack/echo/v4"
+	"github.com/nettojulio/ufape-crawler-golang/internal/config"
 	"github.com/nettojulio/ufape-crawler-golang/internal/crawler"
+	"github.com/nettojulio/ufape-crawler-golang/internal/health"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -36,3 +39,61 @@ func TestHealthCheckHandler(t *testing.T) {
 	assert.NoError(t, err, "O corpo da resposta deveria ser um JSON decodificável")
 	assert.Equal(t, expectedResponse, actualResponse, "O corpo da resposta não corresponde ao esperado")
 }
+
+func TestLivenessHandler(t *testing.T) {
+	e := NewServer(&config.Config{Version: "v1.2.3-test"}, Dependencies{})
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"status":"OK","version":"v1.2.3-test"}`, rec.Body.String())
+}
+
+func TestReadinessHandler(t *testing.T) {
+	t.Run("Cenário de Sucesso - Pronta sem Verificações", func(t *testing.T) {
+		e := NewServer(&config.Config{}, Dependencies{})
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"status":"ok","checks":{}}`, rec.Body.String())
+	})
+
+	t.Run("Cenário de Sucesso - Degradada Continua Pronta", func(t *testing.T) {
+		checker := health.NewChecker(0)
+		checker.Add("crawls", health.Capacity(func() (int, int) { return 2, 2 }))
+		e := NewServer(&config.Config{}, Dependencies{Health: checker})
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var report health.Report
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+		assert.Equal(t, health.StatusDegraded, report.Status)
+		assert.NotEmpty(t, report.Checks["crawls"].Detail, "O motivo da degradação deveria ser informado")
+	})
+
+	t.Run("Cenário de Falha - Persistência Indisponível", func(t *testing.T) {
+		checker := health.NewChecker(0)
+		checker.Add("storage", func(context.Context) health.Result {
+			return health.Result{Status: health.StatusUnavailable, Detail: "connection refused"}
+		})
+		e := NewServer(&config.Config{}, Dependencies{Health: checker})
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.Contains(t, rec.Body.String(), "connection refused")
+	})
+
+	t.Run("Cenário de Falha - Encerramento em Andamento", func(t *testing.T) {
+		checker := health.NewChecker(0)
+		e := NewServer(&config.Config{}, Dependencies{Health: checker})
+		checker.Shutdown()
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.Contains(t, rec.Body.String(), "shutting down")
+	})
+}
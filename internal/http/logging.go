@@ -0,0 +1,48 @@
+package http
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/nettojulio/ufape-crawler-golang/internal/logging"
+)
+
+// requestLogger guarda no contexto da requisição um logger com o request_id
+// gerado por middleware.RequestID e registra uma linha ao fim de cada
+// requisição. Respostas 5xx são registradas como erro e 4xx como aviso.
+func requestLogger(base *slog.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			req := c.Request()
+			logger := base.With("request_id", c.Response().Header().Get(echo.HeaderXRequestID))
+			ctx := logging.WithContext(req.Context(), logger)
+			c.SetRequest(req.WithContext(ctx))
+
+			if err := next(c); err != nil {
+				c.Error(err)
+			}
+
+			status := c.Response().Status
+			level := slog.LevelInfo
+			switch {
+			case status >= 500:
+				level = slog.LevelError
+			case status >= 400:
+				level = slog.LevelWarn
+			}
+			logger.LogAttrs(ctx, level, "request completed",
+				slog.String("method", req.Method),
+				slog.String("uri", req.RequestURI),
+				slog.String("route", c.Path()),
+				slog.Int("status", status),
+				slog.Duration("latency", time.Since(start)),
+				slog.Int64("bytes_out", c.Response().Size),
+				slog.String("remote_ip", c.RealIP()),
+			)
+			return nil
+		}
+	}
+}
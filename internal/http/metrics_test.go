@@ -0,0 +1,46 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nettojulio/ufape-crawler-golang/internal/metrics"
+)
+
+func TestMetrics(t *testing.T) {
+	e := echo.New()
+	e.Use(metricsMiddleware)
+	e.GET("/", HealthCheckHandler("v1.2.3-test"))
+	metricsRoutes(e, routeGuards{})
+
+	t.Run("Cenário de Sucesso - Requisições Contadas pela Rota", func(t *testing.T) {
+		ok := metrics.APIRequests.WithLabelValues(http.MethodGet, "/", "200")
+		before := testutil.ToFloat64(ok)
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		rec = httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/nao-existe", nil))
+
+		assert.Equal(t, 1.0, testutil.ToFloat64(ok)-before)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.GreaterOrEqual(t, testutil.ToFloat64(metrics.APIRequests.WithLabelValues(http.MethodGet, "unmatched", "404")), 1.0,
+			"Rotas inexistentes deveriam ser agrupadas, e não rotuladas pela URL")
+	})
+
+	t.Run("Cenário de Sucesso - Exposição no Formato Prometheus", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		body := rec.Body.String()
+		assert.Contains(t, body, `crawler_api_requests_total{code="200",method="GET",route="/"}`)
+		assert.Contains(t, body, "crawler_crawl_in_flight")
+		assert.Contains(t, body, "go_goroutines")
+	})
+}
@@ -0,0 +1,74 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/nettojulio/ufape-crawler-golang/internal/auth"
+	"github.com/nettojulio/ufape-crawler-golang/internal/config"
+	"github.com/nettojulio/ufape-crawler-golang/internal/metrics"
+	"github.com/nettojulio/ufape-crawler-golang/internal/ratelimit"
+)
+
+// rateLimits são os limites de taxa das rotas que buscam páginas: crawl para o
+// POST / e jobs para as rotas mais pesadas (lotes, crawlings de várias páginas
+// e execuções de agendamentos). Campos nil não limitam.
+type rateLimits struct {
+	crawl echo.MiddlewareFunc
+	jobs  echo.MiddlewareFunc
+}
+
+// NewRateLimits cria os limites de taxa de cfg, ou nil quando estão
+// desabilitados.
+func NewRateLimits(cfg config.RateLimitConfig) *ratelimit.Limits {
+	if !cfg.Enabled {
+		return nil
+	}
+	return &ratelimit.Limits{
+		Crawl: ratelimit.New(cfg.CrawlRate, cfg.Window, cfg.CrawlBurst),
+		Jobs:  ratelimit.New(cfg.JobsRate, cfg.Window, cfg.JobsBurst),
+	}
+}
+
+func newRateLimits(limits *ratelimit.Limits) rateLimits {
+	if limits == nil {
+		return rateLimits{}
+	}
+	var r rateLimits
+	if limits.Crawl != nil {
+		r.crawl = rateLimit("crawl", limits.Crawl)
+	}
+	if limits.Jobs != nil {
+		r.jobs = rateLimit("jobs", limits.Jobs)
+	}
+	return r
+}
+
+// rateLimit aplica limiter por cliente: a chave de API autenticada ou, sem
+// ela, o IP. Toda resposta recebe os cabeçalhos RateLimit-Limit,
+// RateLimit-Remaining e RateLimit-Reset; as recusadas respondem 429 com
+// Retry-After.
+func rateLimit(policy string, limiter *ratelimit.Limiter) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			client := "ip:" + c.RealIP()
+			if caller := auth.CallerFromContext(c.Request().Context()); caller != nil {
+				client = "key:" + caller.Key.ID
+			}
+
+			d := limiter.Allow(client)
+			header := c.Response().Header()
+			header.Set("RateLimit-Limit", strconv.Itoa(d.Limit))
+			header.Set("RateLimit-Remaining", strconv.Itoa(d.Remaining))
+			header.Set("RateLimit-Reset", ratelimit.Seconds(d.Reset))
+			if !d.Allowed {
+				metrics.RateLimited.WithLabelValues(policy).Inc()
+				header.Set(echo.HeaderRetryAfter, ratelimit.Seconds(d.RetryAfter))
+				return c.JSON(http.StatusTooManyRequests, echo.Map{"error": "rate limit exceeded"})
+			}
+			return next(c)
+		}
+	}
+}
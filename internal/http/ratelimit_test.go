@@ -0,0 +1,110 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nettojulio/ufape-crawler-golang/internal/auth"
+	"github.com/nettojulio/ufape-crawler-golang/internal/config"
+)
+
+func newRateLimitTestServer(t *testing.T, trustProxy bool, store *auth.Store) *echo.Echo {
+	t.Helper()
+	cfg := &config.Config{
+		Batch: config.BatchConfig{MaxItems: 10, Concurrency: 2},
+		RateLimit: config.RateLimitConfig{
+			Enabled:    true,
+			Window:     time.Hour,
+			CrawlRate:  1,
+			CrawlBurst: 2,
+			JobsRate:   1,
+			JobsBurst:  1,
+			TrustProxy: trustProxy,
+		},
+	}
+	return NewServer(cfg, Dependencies{Auth: store})
+}
+
+// rateLimitedRequest envia um corpo inválido, recusado pelo handler com 400
+// depois de passar pelo limite de taxa, para não depender da rede.
+func rateLimitedRequest(e *echo.Echo, target, remoteAddr string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(`{}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.RemoteAddr = remoteAddr
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRateLimit(t *testing.T) {
+	t.Run("Cenário de Sucesso - Cabeçalhos RateLimit nas Respostas", func(t *testing.T) {
+		e := newRateLimitTestServer(t, false, nil)
+		rec := rateLimitedRequest(e, "/", "192.0.2.1:1234", nil)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "2", rec.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, "1", rec.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "3600", rec.Header().Get("RateLimit-Reset"))
+		assert.Empty(t, rec.Header().Get(echo.HeaderRetryAfter))
+	})
+
+	t.Run("Cenário de Falha - Limite Excedido por IP", func(t *testing.T) {
+		e := newRateLimitTestServer(t, false, nil)
+		rateLimitedRequest(e, "/", "192.0.2.1:1234", nil)
+		rateLimitedRequest(e, "/", "192.0.2.1:1234", nil)
+		rec := rateLimitedRequest(e, "/", "192.0.2.1:5678", nil)
+
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+		assert.Equal(t, "0", rec.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "3600", rec.Header().Get(echo.HeaderRetryAfter))
+
+		rec = rateLimitedRequest(e, "/", "192.0.2.2:1234", nil)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, "Outro IP deveria ter o próprio limite")
+	})
+
+	t.Run("Cenário de Falha - Rotas Pesadas com Limite Próprio", func(t *testing.T) {
+		e := newRateLimitTestServer(t, false, nil)
+		assert.Equal(t, http.StatusBadRequest, rateLimitedRequest(e, "/batch", "192.0.2.1:1234", nil).Code)
+		assert.Equal(t, http.StatusTooManyRequests, rateLimitedRequest(e, "/batch", "192.0.2.1:1234", nil).Code)
+		assert.Equal(t, http.StatusBadRequest, rateLimitedRequest(e, "/", "192.0.2.1:1234", nil).Code,
+			"O POST / não deveria consumir o limite das rotas pesadas")
+	})
+
+	t.Run("Cenário de Falha - X-Forwarded-For Ignorado sem Proxy Confiável", func(t *testing.T) {
+		e := newRateLimitTestServer(t, false, nil)
+		for _, ip := range []string{"198.51.100.1", "198.51.100.2"} {
+			rateLimitedRequest(e, "/", "192.0.2.1:1234", map[string]string{echo.HeaderXForwardedFor: ip})
+		}
+		rec := rateLimitedRequest(e, "/", "192.0.2.1:1234", map[string]string{echo.HeaderXForwardedFor: "198.51.100.3"})
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	})
+
+	t.Run("Cenário de Sucesso - X-Forwarded-For com Proxy Confiável", func(t *testing.T) {
+		e := newRateLimitTestServer(t, true, nil)
+		for _, ip := range []string{"198.51.100.1", "198.51.100.1", "198.51.100.2"} {
+			rec := rateLimitedRequest(e, "/", "10.0.0.1:1234", map[string]string{echo.HeaderXForwardedFor: ip})
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("Cenário de Falha - Limite por Chave de API", func(t *testing.T) {
+		store, err := auth.NewStore([]auth.Key{{ID: "ci", Key: "ci-secret", Scopes: []auth.Scope{auth.ScopeCrawl}}})
+		assert.NoError(t, err)
+		e := newRateLimitTestServer(t, false, store)
+		headers := map[string]string{"X-API-Key": "ci-secret"}
+
+		rateLimitedRequest(e, "/", "192.0.2.1:1234", headers)
+		rateLimitedRequest(e, "/", "192.0.2.2:1234", headers)
+		rec := rateLimitedRequest(e, "/", "192.0.2.3:1234", headers)
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code, "A chave deveria ter um limite único, em qualquer IP")
+	})
+}
@@ -5,21 +5,93 @@ import (
 	echoSwagger "github.com/swaggo/echo-swagger"
 
 	_ "github.com/nettojulio/ufape-crawler-golang/docs"
+	"github.com/nettojulio/ufape-crawler-golang/internal/auth"
 	"github.com/nettojulio/ufape-crawler-golang/internal/config"
+	"github.com/nettojulio/ufape-crawler-golang/internal/health"
+	"github.com/nettojulio/ufape-crawler-golang/internal/metrics"
 )
 
-func registerRoutes(e *echo.Echo, cfg *config.Config, crawlerHandler *CrawlerHandler) {
-	healthCheckRoutes(e, cfg)
-	crawlerRoutes(e, crawlerHandler)
+// routeGuards são os middlewares de um grupo de rotas protegidas, na ordem
+// de execução: a autenticação com o escopo do grupo e, nas rotas que buscam
+// páginas, o limite de taxa. Campos nil são ignorados.
+type routeGuards struct {
+	auth  echo.MiddlewareFunc
+	limit echo.MiddlewareFunc
+}
+
+// read retorna os middlewares das rotas que apenas consultam.
+func (g routeGuards) read() []echo.MiddlewareFunc {
+	return g.chain(g.auth)
+}
+
+// fetch retorna os middlewares das rotas que buscam páginas.
+func (g routeGuards) fetch() []echo.MiddlewareFunc {
+	return g.chain(g.auth, g.limit)
+}
+
+func (g routeGuards) chain(m ...echo.MiddlewareFunc) []echo.MiddlewareFunc {
+	var chain []echo.MiddlewareFunc
+	for _, mw := range m {
+		if mw != nil {
+			chain = append(chain, mw)
+		}
+	}
+	return chain
+}
+
+func registerRoutes(e *echo.Echo, cfg *config.Config, checker *health.Checker, authStore *auth.Store, limits rateLimits, crawlerHandler *CrawlerHandler, batchHandler *BatchHandler, crawlJobHandler *CrawlJobHandler, scheduleHandler *ScheduleHandler) {
+	healthCheckRoutes(e, cfg, checker)
+	// As métricas incluem o consumo por chave de API; só as chaves admin as leem.
+	metricsRoutes(e, routeGuards{auth: requireScope(authStore, auth.ScopeAdmin)})
+	crawlerRoutes(e, crawlerHandler, batchHandler,
+		routeGuards{auth: requireScope(authStore, auth.ScopeCrawl), limit: limits.crawl},
+		routeGuards{auth: requireScope(authStore, auth.ScopeCrawl), limit: limits.jobs})
+	if crawlJobHandler != nil {
+		crawlJobRoutes(e, crawlJobHandler, routeGuards{auth: requireScope(authStore, auth.ScopeJobs), limit: limits.jobs})
+	}
+	if scheduleHandler != nil {
+		scheduleRoutes(e, scheduleHandler, routeGuards{auth: requireScope(authStore, auth.ScopeAdmin), limit: limits.jobs})
+	}
+	if authStore != nil {
+		authRoutes(e, NewAuthHandler(authStore), routeGuards{auth: requireScope(authStore, auth.ScopeAdmin)})
+	}
 	swaggerRoutes(e)
 }
 
-func healthCheckRoutes(e *echo.Echo, cfg *config.Config) {
+func healthCheckRoutes(e *echo.Echo, cfg *config.Config, checker *health.Checker) {
 	e.GET("/", HealthCheckHandler(cfg.Version))
+	e.GET("/healthz", LivenessHandler(cfg.Version))
+	e.GET("/readyz", ReadinessHandler(checker))
+}
+
+func metricsRoutes(e *echo.Echo, g routeGuards) {
+	e.GET("/metrics", echo.WrapHandler(metrics.Handler()), g.read()...)
+}
+
+func crawlerRoutes(e *echo.Echo, h *CrawlerHandler, b *BatchHandler, crawl, batch routeGuards) {
+	e.POST("/", h.HandleCrawl, crawl.fetch()...)
+	e.POST("/batch", b.HandleBatch, batch.fetch()...)
+}
+
+func crawlJobRoutes(e *echo.Echo, h *CrawlJobHandler, g routeGuards) {
+	e.POST("/crawls", h.HandleStart, g.fetch()...)
+	e.GET("/crawls", h.HandleList, g.read()...)
+	e.GET("/crawls/:id", h.HandleGet, g.read()...)
+	e.DELETE("/crawls/:id", h.HandleCancel, g.read()...)
+	e.GET("/crawls/:id/events", h.HandleEvents, g.read()...)
+	e.GET("/crawls/:id/ws", h.HandleWebSocket, g.read()...)
+}
+
+func scheduleRoutes(e *echo.Echo, h *ScheduleHandler, g routeGuards) {
+	e.POST("/schedules", h.HandleCreate, g.read()...)
+	e.GET("/schedules", h.HandleList, g.read()...)
+	e.GET("/schedules/:id", h.HandleGet, g.read()...)
+	e.DELETE("/schedules/:id", h.HandleDelete, g.read()...)
+	e.POST("/schedules/:id/run", h.HandleRun, g.fetch()...)
 }
 
-func crawlerRoutes(e *echo.Echo, h *CrawlerHandler) {
-	e.POST("/", h.HandleCrawl)
+func authRoutes(e *echo.Echo, h *AuthHandler, g routeGuards) {
+	e.GET("/auth/usage", h.HandleUsage, g.read()...)
 }
 
 func swaggerRoutes(e *echo.Echo) {
@@ -0,0 +1,130 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/nettojulio/ufape-crawler-golang/internal/monitor"
+)
+
+type ScheduleManager interface {
+	Create(sched monitor.Schedule) (monitor.Schedule, error)
+	Get(id string) (monitor.Schedule, error)
+	List() []monitor.Schedule
+	Delete(id string) error
+	Trigger(id string) error
+}
+
+type ScheduleHandler struct {
+	scheduler ScheduleManager
+}
+
+func NewScheduleHandler(scheduler ScheduleManager) *ScheduleHandler {
+	return &ScheduleHandler{scheduler: scheduler}
+}
+
+// HandleCreate godoc
+// @Summary      Cria um crawling agendado
+// @Description  Agenda crawlings recorrentes de uma URL, por expressão cron de cinco campos ou por intervalo. Quando uma execução encontra mudanças em relação à anterior, um POST JSON assinado com HMAC-SHA256 é enviado ao webhook. O segredo é retornado apenas nesta resposta.
+// @Tags         Schedules
+// @Accept       json
+// @Produce      json
+// @Param        schedule body monitor.ScheduleRequest true "Agendamento - seedUrl e cron ou interval são obrigatórios"
+// @Success      201  {object}  monitor.Schedule
+// @Failure      400  {object}  map[string]string
+// @Security     ApiKeyAuth
+// @Router       /schedules [post]
+func (h *ScheduleHandler) HandleCreate(c echo.Context) error {
+	var req monitor.ScheduleRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": "invalid request body"})
+	}
+	if err := c.Validate(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+	}
+
+	sched, err := h.scheduler.Create(req.Schedule())
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+	}
+	return c.JSON(http.StatusCreated, sched)
+}
+
+// HandleList godoc
+// @Summary      Lista os crawlings agendados
+// @Tags         Schedules
+// @Produce      json
+// @Success      200  {array}  monitor.Schedule
+// @Security     ApiKeyAuth
+// @Router       /schedules [get]
+func (h *ScheduleHandler) HandleList(c echo.Context) error {
+	schedules := h.scheduler.List()
+	for i := range schedules {
+		schedules[i] = schedules[i].Redacted()
+	}
+	return c.JSON(http.StatusOK, schedules)
+}
+
+// HandleGet godoc
+// @Summary      Consulta um crawling agendado
+// @Description  Retorna o agendamento, a próxima execução e o resumo das mudanças da última.
+// @Tags         Schedules
+// @Produce      json
+// @Param        id   path      string  true  "ID do agendamento"
+// @Success      200  {object}  monitor.Schedule
+// @Failure      404  {object}  map[string]string
+// @Security     ApiKeyAuth
+// @Router       /schedules/{id} [get]
+func (h *ScheduleHandler) HandleGet(c echo.Context) error {
+	sched, err := h.scheduler.Get(c.Param("id"))
+	if err != nil {
+		return scheduleError(c, err)
+	}
+	return c.JSON(http.StatusOK, sched.Redacted())
+}
+
+// HandleDelete godoc
+// @Summary      Remove um crawling agendado
+// @Tags         Schedules
+// @Param        id   path      string  true  "ID do agendamento"
+// @Success      204
+// @Failure      404  {object}  map[string]string
+// @Security     ApiKeyAuth
+// @Router       /schedules/{id} [delete]
+func (h *ScheduleHandler) HandleDelete(c echo.Context) error {
+	if err := h.scheduler.Delete(c.Param("id")); err != nil {
+		return scheduleError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// HandleRun godoc
+// @Summary      Executa um crawling agendado imediatamente
+// @Description  Inicia uma execução fora do horário previsto. A próxima execução agendada não é alterada.
+// @Tags         Schedules
+// @Produce      json
+// @Param        id   path      string  true  "ID do agendamento"
+// @Success      202
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      429  {object}  map[string]string
+// @Security     ApiKeyAuth
+// @Router       /schedules/{id}/run [post]
+func (h *ScheduleHandler) HandleRun(c echo.Context) error {
+	if err := h.scheduler.Trigger(c.Param("id")); err != nil {
+		return scheduleError(c, err)
+	}
+	return c.NoContent(http.StatusAccepted)
+}
+
+func scheduleError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, monitor.ErrNotFound):
+		return c.JSON(http.StatusNotFound, echo.Map{"error": err.Error()})
+	case errors.Is(err, monitor.ErrAlreadyRunning):
+		return c.JSON(http.StatusConflict, echo.Map{"error": err.Error()})
+	default:
+		return c.JSON(http.StatusServiceUnavailable, echo.Map{"error": err.Error()})
+	}
+}
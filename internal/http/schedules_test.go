@@ -0,0 +1,80 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/nettojulio/ufape-crawler-golang/internal/monitor"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduleHandler(t *testing.T) {
+	e := echo.New()
+	e.Validator = &CustomValidator{validator: validator.New()}
+	scheduler, err := monitor.NewScheduler(nil, nil, monitor.Options{})
+	assert.NoError(t, err)
+	defer scheduler.Stop()
+	scheduleRoutes(e, NewScheduleHandler(scheduler), routeGuards{})
+
+	var created monitor.Schedule
+
+	t.Run("Cenário de Sucesso - Criação com Segredo Gerado", func(t *testing.T) {
+		reqBody := `{"seedUrl": "https://example.com", "interval": "6h", "webhookUrl": "https://hooks.example.com/crawler"}`
+		req := httptest.NewRequest(http.MethodPost, "/schedules", strings.NewReader(reqBody))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+		assert.NotEmpty(t, created.ID)
+		assert.NotEmpty(t, created.WebhookSecret, "O segredo deveria ser retornado na criação")
+		assert.True(t, created.Enabled, "O agendamento deveria ser habilitado por padrão")
+		assert.NotNil(t, created.NextRunAt)
+	})
+
+	t.Run("Cenário de Sucesso - Consulta sem Segredo", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/schedules/"+created.ID, nil)
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.NotContains(t, rec.Body.String(), created.WebhookSecret, "O segredo não deveria ser exposto na consulta")
+	})
+
+	t.Run("Cenário de Falha - Recorrência Inválida", func(t *testing.T) {
+		reqBody := `{"seedUrl": "https://example.com", "cron": "99 * * * *"}`
+		req := httptest.NewRequest(http.MethodPost, "/schedules", strings.NewReader(reqBody))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("Cenário de Sucesso - Remoção", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/schedules/"+created.ID, nil)
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+
+	t.Run("Cenário de Falha - Agendamento Inexistente", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/schedules/"+created.ID+"/run", nil)
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
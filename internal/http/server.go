@@ -1,24 +1,97 @@
 package http
 
 import (
+	"log/slog"
+	"strings"
+
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho"
 
+	"github.com/nettojulio/ufape-crawler-golang/internal/auth"
 	"github.com/nettojulio/ufape-crawler-golang/internal/config"
 	"github.com/nettojulio/ufape-crawler-golang/internal/crawler"
+	"github.com/nettojulio/ufape-crawler-golang/internal/health"
+	"github.com/nettojulio/ufape-crawler-golang/internal/live"
+	"github.com/nettojulio/ufape-crawler-golang/internal/monitor"
+	"github.com/nettojulio/ufape-crawler-golang/internal/ratelimit"
+	"github.com/nettojulio/ufape-crawler-golang/internal/storage"
 )
 
-// NewServer agora aceita o crawler.Service como uma dependência.
-func NewServer(cfg *config.Config, crawlerService *crawler.Service) *echo.Echo {
+// Dependencies agrupa os serviços injetados no servidor HTTP.
+type Dependencies struct {
+	CrawlerService *crawler.Service
+	// Repository é opcional; quando nil, as buscas não são persistidas.
+	Repository storage.Repository
+	// Jobs é opcional; quando nil, as rotas de crawling de várias páginas não são registradas.
+	Jobs *live.Manager
+	// Scheduler é opcional; quando nil, as rotas de agendamento não são registradas.
+	Scheduler *monitor.Scheduler
+	// Logger registra as requisições; quando nil, usa slog.Default().
+	Logger *slog.Logger
+	// Health executa as verificações do GET /readyz; quando nil, a API é
+	// considerada sempre pronta.
+	Health *health.Checker
+	// Auth é opcional; quando nil, as rotas não exigem chave de API.
+	Auth *auth.Store
+	// RateLimits são os limites de taxa, compartilhados com o gRPC; quando
+	// nil, são criados a partir de cfg.RateLimit.
+	RateLimits *ratelimit.Limits
+}
+
+// NewServer monta o servidor Echo com as dependências da aplicação.
+func NewServer(cfg *config.Config, deps Dependencies) *echo.Echo {
 	e := echo.New()
 
-	e.Use(middleware.Logger())
+	logger := deps.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	e.HideBanner = true
+	e.IPExtractor = echo.ExtractIPDirect()
+	if cfg.RateLimit.TrustProxy {
+		e.IPExtractor = echo.ExtractIPFromXFFHeader()
+	}
+	e.Use(middleware.RequestID())
+	e.Use(otelecho.Middleware(cfg.Tracing.ServiceName, otelecho.WithSkipper(skipTracing)))
+	e.Use(requestLogger(logger))
+	e.Use(metricsMiddleware)
 	e.Validator = &CustomValidator{validator: validator.New()}
 
-	crawlerHandler := NewCrawlerHandler(crawlerService)
+	crawlerHandler := NewCrawlerHandler(deps.CrawlerService, deps.Repository)
+	batchHandler := NewBatchHandler(crawlerHandler, cfg.Batch.MaxItems, cfg.Batch.Concurrency)
 
-	registerRoutes(e, cfg, crawlerHandler)
+	var crawlJobHandler *CrawlJobHandler
+	if deps.Jobs != nil {
+		crawlJobHandler = NewCrawlJobHandler(deps.Jobs)
+	}
+	var scheduleHandler *ScheduleHandler
+	if deps.Scheduler != nil {
+		scheduleHandler = NewScheduleHandler(deps.Scheduler)
+	}
+
+	checker := deps.Health
+	if checker == nil {
+		checker = health.NewChecker(0)
+	}
+
+	limits := deps.RateLimits
+	if limits == nil {
+		limits = NewRateLimits(cfg.RateLimit)
+	}
+	registerRoutes(e, cfg, checker, deps.Auth, newRateLimits(limits), crawlerHandler, batchHandler, crawlJobHandler, scheduleHandler)
 
 	return e
 }
+
+// skipTracing evita spans para as rotas de métricas, de saúde e de documentação.
+func skipTracing(c echo.Context) bool {
+	switch path := c.Request().URL.Path; path {
+	case "/metrics", "/healthz", "/readyz":
+		return true
+	default:
+		return strings.HasPrefix(path, "/swagger/")
+	}
+}
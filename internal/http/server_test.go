@@ -0,0 +1,92 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/nettojulio/ufape-crawler-golang/internal/config"
+)
+
+func TestNewServer_Tracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+
+	cfg := &config.Config{Version: "v1.2.3-test", Tracing: config.TracingConfig{ServiceName: "crawler-test"}}
+	e := NewServer(cfg, Dependencies{})
+
+	t.Run("Cenário de Sucesso - Traceparent Recebido é Continuado", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		spans := recorder.Ended()
+		if assert.Len(t, spans, 1) {
+			assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext().TraceID().String())
+			assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent().SpanID().String())
+		}
+	})
+
+	t.Run("Cenário de Sucesso - Métricas sem Span", func(t *testing.T) {
+		before := len(recorder.Ended())
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Len(t, recorder.Ended(), before, "A rota /metrics não deveria gerar spans")
+	})
+}
+
+func TestNewServer_RequestLogging(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	e := NewServer(&config.Config{}, Dependencies{Logger: logger})
+
+	t.Run("Cenário de Sucesso - Linha de Log com Request ID", func(t *testing.T) {
+		buf.Reset()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(echo.HeaderXRequestID, "req-123")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var line map[string]any
+		if assert.NoError(t, json.Unmarshal(buf.Bytes(), &line)) {
+			assert.Equal(t, "request completed", line["msg"])
+			assert.Equal(t, "req-123", line["request_id"])
+			assert.Equal(t, "/", line["route"])
+			assert.EqualValues(t, http.StatusOK, line["status"])
+		}
+	})
+
+	t.Run("Cenário de Falha - Rota Inexistente Registrada como Aviso", func(t *testing.T) {
+		buf.Reset()
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/nao-existe", nil))
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		var line map[string]any
+		if assert.NoError(t, json.Unmarshal(buf.Bytes(), &line)) {
+			assert.Equal(t, "WARN", line["level"])
+			assert.NotEmpty(t, line["request_id"], "O ID gerado pelo servidor deveria constar no log")
+		}
+	})
+}
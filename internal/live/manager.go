@@ -0,0 +1,443 @@
+// Package live executa crawlings de várias páginas no servidor e distribui o
+// progresso de cada um para assinantes, como as rotas SSE e WebSocket.
+package live
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nettojulio/ufape-crawler-golang/internal/crawler"
+	"github.com/nettojulio/ufape-crawler-golang/internal/extractor"
+	"github.com/nettojulio/ufape-crawler-golang/internal/logging"
+	"github.com/nettojulio/ufape-crawler-golang/internal/storage"
+)
+
+// Status de um crawling.
+type Status string
+
+const (
+	StatusRunning   Status = "running"
+	StatusCompleted Status = "completed"
+	StatusCanceled  Status = "canceled"
+)
+
+var (
+	ErrNotFound    = errors.New("crawl not found")
+	ErrTooManyJobs = errors.New("too many crawls running")
+	ErrFinished    = errors.New("crawl already finished")
+)
+
+const (
+	// DefaultMaxDepth é a profundidade usada quando a requisição não define uma.
+	DefaultMaxDepth = 3
+	// subscriberBuffer é quantos eventos um assinante lento pode acumular
+	// antes de passar a perdê-los.
+	subscriberBuffer = 256
+)
+
+// JobRequest é o corpo aceito para iniciar um crawling.
+type JobRequest struct {
+	SeedURL        string   `json:"seedUrl" validate:"required,url" example:"https://ufape.edu.br"`
+	MaxDepth       int      `json:"maxDepth,omitempty" validate:"gte=0" example:"3"`
+	FullGraph      *bool    `json:"fullGraph,omitempty" example:"true"`
+	AllowedDomains []string `json:"allowedDomains,omitempty" example:"ufape.edu.br"`
+	// Session é a sessão configurada cujos cookies são usados em todas as
+	// páginas do crawling.
+	Session string `json:"session,omitempty" example:"intranet"`
+
+	// Owner é o ID da chave de API que iniciou o crawling. Os métodos que
+	// recebem owner só encontram os crawlings dele; "" encontra todos.
+	Owner string `json:"-" swaggerignore:"true"`
+	// Charge, quando definido, é chamado antes de cada página buscada; um erro
+	// interrompe o crawling e é informado em Job.Error. É usado para
+	// descontar as páginas da cota da chave de API.
+	Charge func(pages int) error `json:"-" swaggerignore:"true"`
+}
+
+// Job é o estado de um crawling iniciado pelo Manager.
+type Job struct {
+	ID         string             `json:"id"`
+	SeedURL    string             `json:"seedUrl"`
+	MaxDepth   int                `json:"maxDepth"`
+	FullGraph  bool               `json:"fullGraph"`
+	Session    string             `json:"session,omitempty"`
+	Owner      string             `json:"owner,omitempty"`
+	Status     Status             `json:"status"`
+	RunID      string             `json:"runId,omitempty"`
+	Counters   extractor.Counters `json:"counters"`
+	StartedAt  time.Time          `json:"startedAt"`
+	FinishedAt *time.Time         `json:"finishedAt,omitempty"`
+	// Error explica por que o crawling foi interrompido, quando não foi um cancelamento.
+	Error string `json:"error,omitempty" example:"daily page quota exceeded"`
+}
+
+// Message é um evento de progresso numerado. Seq cresce a cada evento do
+// crawling e permite retomar uma assinatura sem perder eventos.
+type Message struct {
+	Seq int64 `json:"seq"`
+	extractor.Event
+}
+
+// Options configura o Manager.
+type Options struct {
+	// MaxRunning limita quantos crawlings executam ao mesmo tempo.
+	MaxRunning int
+	// Retain é quantos crawlings encerrados permanecem consultáveis.
+	Retain int
+	// MaxDepth é a maior JobRequest.MaxDepth aceita. O padrão é 10.
+	MaxDepth int
+	// History é quantos eventos recentes de cada crawling são reenviados a novos assinantes.
+	History int
+	// Sessions são as sessões aceitas em JobRequest.Session.
+	Sessions *crawler.SessionStore
+	Logger   *slog.Logger
+}
+
+// Manager inicia crawlings em segundo plano e publica seus eventos.
+type Manager struct {
+	mu   sync.Mutex
+	jobs map[string]*job
+
+	newFetcher func(JobRequest) extractor.Fetcher
+	repository storage.Repository
+	opts       Options
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+type job struct {
+	Job
+	cancel  context.CancelFunc
+	seq     int64
+	history []Message
+	subs    map[chan Message]struct{}
+}
+
+// NewManager cria um Manager que busca as páginas por meio de service. repo é
+// opcional; quando definido, cada crawling é registrado como uma execução.
+func NewManager(service crawler.Crawler, repo storage.Repository, opts Options) *Manager {
+	if opts.MaxRunning <= 0 {
+		opts.MaxRunning = 2
+	}
+	if opts.Retain <= 0 {
+		opts.Retain = 50
+	}
+	if opts.MaxDepth <= 0 {
+		opts.MaxDepth = 10
+	}
+	if opts.History <= 0 {
+		opts.History = 1000
+	}
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Manager{
+		jobs: make(map[string]*job),
+		newFetcher: func(req JobRequest) extractor.Fetcher {
+			return extractor.NewServiceFetcher(service, crawler.Payload{
+				AllowedDomains: &req.AllowedDomains,
+				RequestOptions: crawler.RequestOptions{Session: req.Session},
+			})
+		},
+		repository: repo,
+		opts:       opts,
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// Start inicia um crawling a partir de req.SeedURL. Sem AllowedDomains, o
+// crawling fica restrito ao domínio da URL inicial e seus subdomínios.
+func (m *Manager) Start(req JobRequest) (Job, error) {
+	seed, err := url.Parse(req.SeedURL)
+	if err != nil || seed.Host == "" {
+		return Job{}, errors.New("seedUrl must be an absolute url")
+	}
+	if req.MaxDepth == 0 {
+		req.MaxDepth = min(DefaultMaxDepth, m.opts.MaxDepth)
+	}
+	if req.MaxDepth > m.opts.MaxDepth {
+		return Job{}, fmt.Errorf("maxDepth exceeds the limit of %d", m.opts.MaxDepth)
+	}
+	if len(req.AllowedDomains) == 0 {
+		req.AllowedDomains = []string{strings.TrimPrefix(seed.Host, "www.")}
+	}
+	if req.Session != "" {
+		if _, err := m.opts.Sessions.Get(req.Session); err != nil {
+			return Job{}, err
+		}
+	}
+	fullGraph := req.FullGraph == nil || *req.FullGraph
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.ctx.Err() != nil {
+		return Job{}, errors.New("crawl manager is stopped")
+	}
+	if m.runningLocked() >= m.opts.MaxRunning {
+		return Job{}, ErrTooManyJobs
+	}
+
+	ctx, cancel := context.WithCancelCause(m.ctx)
+	id := storage.NewID()
+	logger := m.opts.Logger.With("crawl", id, "seed", req.SeedURL)
+	ctx = logging.WithContext(ctx, logger)
+	j := &job{
+		Job: Job{
+			ID:        id,
+			SeedURL:   req.SeedURL,
+			MaxDepth:  req.MaxDepth,
+			FullGraph: fullGraph,
+			Session:   req.Session,
+			Owner:     req.Owner,
+			Status:    StatusRunning,
+			StartedAt: time.Now().UTC(),
+		},
+		cancel: func() { cancel(nil) },
+		subs:   make(map[chan Message]struct{}),
+	}
+	m.jobs[j.ID] = j
+	m.pruneLocked()
+
+	fetcher := m.newFetcher(req)
+	if req.Charge != nil {
+		fetcher = quotaFetcher{next: fetcher, charge: req.Charge, stop: cancel}
+	}
+
+	c := extractor.NewCrawler(fetcher, extractor.Options{
+		MaxDepth:  req.MaxDepth,
+		FullGraph: fullGraph,
+		Source:    storage.SourceAPI,
+		OnEvent:   func(e extractor.Event) { m.publish(j, e) },
+		Logger:    logger,
+	})
+
+	m.wg.Add(1)
+	go m.run(ctx, j, c)
+	return j.Job, nil
+}
+
+func (m *Manager) run(ctx context.Context, j *job, c *extractor.Crawler) {
+	defer m.wg.Done()
+	defer j.cancel()
+
+	if m.repository != nil {
+		if err := c.RecordTo(ctx, m.repository, j.SeedURL); err != nil {
+			logging.FromContext(ctx, m.opts.Logger).Warn("failed to record crawl", "error", err)
+		} else {
+			m.mu.Lock()
+			j.RunID = c.RunID()
+			m.mu.Unlock()
+		}
+	}
+
+	err := c.Crawl(ctx, j.SeedURL)
+
+	status, runStatus := StatusCompleted, storage.RunStatusCompleted
+	if err != nil {
+		status, runStatus = StatusCanceled, storage.RunStatusFailed
+	}
+	if err := c.FinishRecording(context.WithoutCancel(ctx), runStatus); err != nil {
+		logging.FromContext(ctx, m.opts.Logger).Warn("failed to finish crawl run", "error", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	finishedAt := time.Now().UTC()
+	j.Status = status
+	j.FinishedAt = &finishedAt
+	if cause := context.Cause(ctx); err != nil && !errors.Is(cause, context.Canceled) {
+		j.Error = cause.Error()
+	}
+	for ch := range j.subs {
+		close(ch)
+	}
+	j.subs = nil
+}
+
+// publish numera o evento, guarda-o no histórico e o entrega aos assinantes.
+// Assinantes com o buffer cheio perdem o evento, mas os contadores dos
+// eventos seguintes mantêm o resumo correto.
+func (m *Manager) publish(j *job, event extractor.Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	j.seq++
+	msg := Message{Seq: j.seq, Event: event}
+	j.Counters = event.Counters
+
+	j.history = append(j.history, msg)
+	if len(j.history) > m.opts.History {
+		j.history = j.history[len(j.history)-m.opts.History:]
+	}
+
+	for ch := range j.subs {
+		select {
+		case ch <- msg:
+		default:
+		}
+	}
+}
+
+// Subscribe assina os eventos do crawling id de owner, reenviando os do
+// histórico com Seq maior que after. O canal é fechado quando o crawling
+// termina; a função retornada cancela a assinatura.
+func (m *Manager) Subscribe(id, owner string, after int64) (<-chan Message, func(), error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	j, err := m.lookupLocked(id, owner)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var replay []Message
+	for _, msg := range j.history {
+		if msg.Seq > after {
+			replay = append(replay, msg)
+		}
+	}
+	ch := make(chan Message, len(replay)+subscriberBuffer)
+	for _, msg := range replay {
+		ch <- msg
+	}
+
+	if j.subs == nil {
+		close(ch)
+		return ch, func() {}, nil
+	}
+	j.subs[ch] = struct{}{}
+
+	unsubscribe := func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if _, ok := j.subs[ch]; ok {
+			delete(j.subs, ch)
+			close(ch)
+		}
+	}
+	return ch, unsubscribe, nil
+}
+
+// Get retorna o estado do crawling id de owner.
+func (m *Manager) Get(id, owner string) (Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	j, err := m.lookupLocked(id, owner)
+	if err != nil {
+		return Job{}, err
+	}
+	return j.Job, nil
+}
+
+// List retorna os crawlings conhecidos de owner, do mais recente para o mais
+// antigo.
+func (m *Manager) List(owner string) []Job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	list := make([]Job, 0, len(m.jobs))
+	for _, j := range m.jobs {
+		if owner == "" || j.Owner == owner {
+			list = append(list, j.Job)
+		}
+	}
+	sort.Slice(list, func(i, k int) bool { return list[i].StartedAt.After(list[k].StartedAt) })
+	return list
+}
+
+// Cancel interrompe o crawling id de owner.
+func (m *Manager) Cancel(id, owner string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	j, err := m.lookupLocked(id, owner)
+	if err != nil {
+		return err
+	}
+	if j.Status != StatusRunning {
+		return ErrFinished
+	}
+	j.cancel()
+	return nil
+}
+
+// lookupLocked encontra o crawling id. Os crawlings de outro dono retornam
+// ErrNotFound, para não revelar que existem.
+func (m *Manager) lookupLocked(id, owner string) (*job, error) {
+	j, ok := m.jobs[id]
+	if !ok || owner != "" && j.Owner != owner {
+		return nil, ErrNotFound
+	}
+	return j, nil
+}
+
+// Running retorna quantos crawlings estão em andamento e o limite MaxRunning.
+func (m *Manager) Running() (running, limit int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.runningLocked(), m.opts.MaxRunning
+}
+
+// Stop cancela os crawlings em andamento e aguarda que terminem.
+func (m *Manager) Stop() {
+	m.cancel()
+	m.wg.Wait()
+}
+
+func (m *Manager) runningLocked() int {
+	running := 0
+	for _, j := range m.jobs {
+		if j.Status == StatusRunning {
+			running++
+		}
+	}
+	return running
+}
+
+// pruneLocked descarta os crawlings encerrados mais antigos além de Retain.
+func (m *Manager) pruneLocked() {
+	var finished []*job
+	for _, j := range m.jobs {
+		if j.Status != StatusRunning {
+			finished = append(finished, j)
+		}
+	}
+	if len(finished) <= m.opts.Retain {
+		return
+	}
+	sort.Slice(finished, func(i, k int) bool { return finished[i].StartedAt.Before(finished[k].StartedAt) })
+	for _, j := range finished[:len(finished)-m.opts.Retain] {
+		delete(m.jobs, j.ID)
+	}
+}
+
+// quotaFetcher desconta cada página de JobRequest.Charge antes de buscá-la e
+// interrompe o crawling quando a cobrança falha.
+type quotaFetcher struct {
+	next   extractor.Fetcher
+	charge func(pages int) error
+	stop   context.CancelCauseFunc
+}
+
+func (f quotaFetcher) Fetch(ctx context.Context, link string) (*crawler.ResponseDTO, error) {
+	if err := f.charge(1); err != nil {
+		f.stop(err)
+		return nil, err
+	}
+	return f.next.Fetch(ctx, link)
+}
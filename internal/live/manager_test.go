@@ -0,0 +1,191 @@
+package live
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nettojulio/ufape-crawler-golang/internal/crawler"
+	"github.com/nettojulio/ufape-crawler-golang/internal/extractor"
+)
+
+// blockingFetcher serve um site de três páginas e, se release não for nil,
+// aguarda o canal antes de cada busca.
+type blockingFetcher struct {
+	release chan struct{}
+}
+
+func (f *blockingFetcher) Fetch(ctx context.Context, link string) (*crawler.ResponseDTO, error) {
+	if f.release != nil {
+		select {
+		case <-f.release:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	switch link {
+	case "https://example.com":
+		return &crawler.ResponseDTO{StatusCode: 200, Links: crawler.LinksResponse{
+			Available:   []string{"https://example.com/a", "https://example.com/b"},
+			Unavailable: []string{"https://other.example"},
+		}}, nil
+	case "https://example.com/a":
+		return &crawler.ResponseDTO{StatusCode: 200}, nil
+	default:
+		return nil, errors.New("connection refused")
+	}
+}
+
+func newTestManager(t *testing.T, fetcher extractor.Fetcher, opts Options) *Manager {
+	t.Helper()
+	m := NewManager(nil, nil, opts)
+	m.newFetcher = func(JobRequest) extractor.Fetcher { return fetcher }
+	t.Cleanup(m.Stop)
+	return m
+}
+
+func collect(t *testing.T, ch <-chan Message) []Message {
+	t.Helper()
+	var msgs []Message
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case msg, ok := <-ch:
+			if !ok {
+				return msgs
+			}
+			msgs = append(msgs, msg)
+		case <-timeout:
+			t.Fatal("subscription was not closed in time")
+		}
+	}
+}
+
+func TestManagerPublishesProgress(t *testing.T) {
+	fetcher := &blockingFetcher{release: make(chan struct{})}
+	m := newTestManager(t, fetcher, Options{})
+
+	job, err := m.Start(JobRequest{SeedURL: "https://example.com"})
+	if err != nil {
+		t.Fatalf("Start() returned an unexpected error: %v", err)
+	}
+	ch, unsubscribe, err := m.Subscribe(job.ID, "", 0)
+	if err != nil {
+		t.Fatalf("Subscribe() returned an unexpected error: %v", err)
+	}
+	defer unsubscribe()
+	close(fetcher.release)
+
+	msgs := collect(t, ch)
+	if len(msgs) == 0 || msgs[0].Type != extractor.EventStarted || msgs[len(msgs)-1].Type != extractor.EventFinished {
+		t.Fatalf("expected events from start to finish, got %+v", msgs)
+	}
+	for i, msg := range msgs {
+		if msg.Seq != int64(i+1) {
+			t.Fatalf("expected sequential events, got seq %d at %d", msg.Seq, i)
+		}
+	}
+
+	final := msgs[len(msgs)-1].Counters
+	expected := extractor.Counters{Discovered: 3, Fetched: 2, Failed: 1, External: 1, Links: 3}
+	if final != expected {
+		t.Errorf("expected final counters %+v, got %+v", expected, final)
+	}
+
+	got, _ := m.Get(job.ID, "")
+	if got.Status != StatusCompleted || got.FinishedAt == nil || got.Counters != expected {
+		t.Errorf("unexpected finished job: %+v", got)
+	}
+
+	replay, _, _ := m.Subscribe(job.ID, "", msgs[len(msgs)-3].Seq)
+	if replayed := collect(t, replay); len(replayed) != 2 {
+		t.Errorf("expected only the events after the given seq to be replayed, got %d", len(replayed))
+	}
+}
+
+func TestManagerCancelAndLimit(t *testing.T) {
+	fetcher := &blockingFetcher{release: make(chan struct{})}
+	m := newTestManager(t, fetcher, Options{MaxRunning: 1})
+
+	job, _ := m.Start(JobRequest{SeedURL: "https://example.com"})
+	if _, err := m.Start(JobRequest{SeedURL: "https://example.org"}); !errors.Is(err, ErrTooManyJobs) {
+		t.Fatalf("expected ErrTooManyJobs, got %v", err)
+	}
+
+	ch, _, _ := m.Subscribe(job.ID, "", 0)
+	if err := m.Cancel(job.ID, ""); err != nil {
+		t.Fatalf("Cancel() returned an unexpected error: %v", err)
+	}
+	msgs := collect(t, ch)
+	if last := msgs[len(msgs)-1]; last.Type != extractor.EventFinished || last.Error == "" {
+		t.Errorf("expected a finished event with the cancellation error, got %+v", last)
+	}
+	if got, _ := m.Get(job.ID, ""); got.Status != StatusCanceled {
+		t.Errorf("expected a canceled job, got %s", got.Status)
+	}
+	if err := m.Cancel(job.ID, ""); !errors.Is(err, ErrFinished) {
+		t.Errorf("expected ErrFinished, got %v", err)
+	}
+}
+
+func TestManagerStopsWhenChargeFails(t *testing.T) {
+	m := newTestManager(t, &blockingFetcher{}, Options{})
+
+	quotaErr := errors.New("daily page quota exceeded")
+	charged := 0
+	job, err := m.Start(JobRequest{SeedURL: "https://example.com", Charge: func(pages int) error {
+		if charged+pages > 1 {
+			return quotaErr
+		}
+		charged += pages
+		return nil
+	}})
+	if err != nil {
+		t.Fatalf("Start() returned an unexpected error: %v", err)
+	}
+	ch, _, _ := m.Subscribe(job.ID, "", 0)
+	msgs := collect(t, ch)
+
+	if last := msgs[len(msgs)-1]; last.Type != extractor.EventFinished || last.Error != quotaErr.Error() {
+		t.Errorf("expected a finished event with the charge error, got %+v", last)
+	}
+	got, _ := m.Get(job.ID, "")
+	if got.Status != StatusCanceled || got.Error != quotaErr.Error() || got.Counters.Fetched != 1 {
+		t.Errorf("expected the crawl to stop after one page with the charge error, got %+v", got)
+	}
+}
+
+func TestManagerSessions(t *testing.T) {
+	sessions, err := crawler.NewSessionStore([]crawler.SessionConfig{{Name: "intranet"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := newTestManager(t, &blockingFetcher{}, Options{Sessions: sessions})
+
+	if _, err := m.Start(JobRequest{SeedURL: "https://example.com", Session: "outra"}); !errors.Is(err, crawler.ErrUnknownSession) {
+		t.Fatalf("expected ErrUnknownSession, got %v", err)
+	}
+	job, err := m.Start(JobRequest{SeedURL: "https://example.com", Session: "intranet"})
+	if err != nil {
+		t.Fatalf("Start() returned an unexpected error: %v", err)
+	}
+	if job.Session != "intranet" {
+		t.Errorf("expected the job to report its session, got %q", job.Session)
+	}
+}
+
+func TestManagerRejectsDeepCrawls(t *testing.T) {
+	m := newTestManager(t, &blockingFetcher{}, Options{MaxDepth: 2})
+
+	if _, err := m.Start(JobRequest{SeedURL: "https://example.com", MaxDepth: 3}); err == nil {
+		t.Error("expected a maxDepth above the limit to be rejected")
+	}
+	job, err := m.Start(JobRequest{SeedURL: "https://example.com"})
+	if err != nil {
+		t.Fatalf("Start() returned an unexpected error: %v", err)
+	}
+	if job.MaxDepth != 2 {
+		t.Errorf("expected the default depth to respect the limit, got %d", job.MaxDepth)
+	}
+}
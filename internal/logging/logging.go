@@ -0,0 +1,91 @@
+// Package logging monta o logger slog da aplicação e transporta, pelo
+// context.Context, o logger de cada requisição ou crawling, já com os
+// atributos que identificam a operação (request_id, url, crawl, ...).
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/nettojulio/ufape-crawler-golang/internal/config"
+)
+
+// Formatos aceitos em config.LogConfig.Format.
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// New cria um logger que escreve em w com o nível e o formato de cfg. Formato
+// vazio equivale a JSON. As linhas registradas com um contexto que carrega um
+// span recebem trace_id e span_id.
+func New(w io.Writer, cfg config.LogConfig) (*slog.Logger, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
+		return nil, fmt.Errorf("invalid log level %q", cfg.Level)
+	}
+
+	opts := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	switch strings.ToLower(cfg.Format) {
+	case "", FormatJSON:
+		handler = slog.NewJSONHandler(w, opts)
+	case FormatText:
+		handler = slog.NewTextHandler(w, opts)
+	default:
+		return nil, fmt.Errorf("unknown log format %q", cfg.Format)
+	}
+	return slog.New(traceHandler{handler}), nil
+}
+
+// Discard retorna um logger que descarta todas as linhas.
+func Discard() *slog.Logger {
+	return slog.New(slog.DiscardHandler)
+}
+
+type contextKey struct{}
+
+// WithContext retorna uma cópia de ctx que carrega logger.
+func WithContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext retorna o logger guardado em ctx por WithContext. Sem logger em
+// ctx, retorna fallback ou, se fallback for nil, slog.Default().
+func FromContext(ctx context.Context, fallback *slog.Logger) *slog.Logger {
+	if logger, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	if fallback != nil {
+		return fallback
+	}
+	return slog.Default()
+}
+
+// traceHandler acrescenta a cada linha o trace e o span ativos no contexto.
+type traceHandler struct {
+	slog.Handler
+}
+
+func (h traceHandler) Handle(ctx context.Context, r slog.Record) error {
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r.AddAttrs(
+			slog.String("trace_id", sc.TraceID().String()),
+			slog.String("span_id", sc.SpanID().String()),
+		)
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h traceHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return traceHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h traceHandler) WithGroup(name string) slog.Handler {
+	return traceHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,96 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/nettojulio/ufape-crawler-golang/internal/config"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("should write JSON by default and respect the level", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, err := New(&buf, config.LogConfig{Level: "warn"})
+		if err != nil {
+			t.Fatalf("New returned an error: %v", err)
+		}
+
+		logger.Info("hidden")
+		logger.Warn("shown", "url", "https://ufape.edu.br")
+
+		var line map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+			t.Fatalf("expected a single JSON line, got %q: %v", buf.String(), err)
+		}
+		if line["msg"] != "shown" || line["url"] != "https://ufape.edu.br" {
+			t.Errorf("unexpected log line: %v", line)
+		}
+	})
+
+	t.Run("should write text when requested", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, err := New(&buf, config.LogConfig{Level: "debug", Format: FormatText})
+		if err != nil {
+			t.Fatalf("New returned an error: %v", err)
+		}
+
+		logger.Debug("page crawled", "depth", 2)
+		if got := buf.String(); !strings.Contains(got, "msg=\"page crawled\" depth=2") {
+			t.Errorf("expected a text line, got %q", got)
+		}
+	})
+
+	t.Run("should reject invalid settings", func(t *testing.T) {
+		if _, err := New(&bytes.Buffer{}, config.LogConfig{Level: "verbose"}); err == nil {
+			t.Error("expected an error for an invalid level")
+		}
+		if _, err := New(&bytes.Buffer{}, config.LogConfig{Level: "info", Format: "xml"}); err == nil {
+			t.Error("expected an error for an unknown format")
+		}
+	})
+
+	t.Run("should add the active trace to each line", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, err := New(&buf, config.LogConfig{Level: "info"})
+		if err != nil {
+			t.Fatalf("New returned an error: %v", err)
+		}
+
+		traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+		spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+		ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID: traceID,
+			SpanID:  spanID,
+		}))
+		logger.With("request_id", "req-1").InfoContext(ctx, "fetch completed")
+
+		var line map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+			t.Fatalf("failed to decode log line: %v", err)
+		}
+		if line["trace_id"] != traceID.String() || line["span_id"] != spanID.String() || line["request_id"] != "req-1" {
+			t.Errorf("expected trace and request attributes, got %v", line)
+		}
+	})
+}
+
+func TestFromContext(t *testing.T) {
+	fallback := Discard()
+	if got := FromContext(context.Background(), fallback); got != fallback {
+		t.Error("expected the fallback logger when the context carries none")
+	}
+	if got := FromContext(context.Background(), nil); got != slog.Default() {
+		t.Error("expected slog.Default() without a fallback")
+	}
+
+	logger := Discard().With("url", "https://ufape.edu.br")
+	if got := FromContext(WithContext(context.Background(), logger), fallback); got != logger {
+		t.Error("expected the logger stored in the context")
+	}
+}
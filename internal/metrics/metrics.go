@@ -0,0 +1,145 @@
+// Package metrics concentra as métricas Prometheus da aplicação, expostas em
+// /metrics pela API.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "crawler"
+
+// Registry guarda todas as métricas da aplicação, além das do runtime Go e
+// do processo.
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+var (
+	// APIRequests conta as requisições atendidas pela API REST, pela rota
+	// registrada (e não pela URL) para manter a cardinalidade baixa.
+	APIRequests = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "api",
+		Name:      "requests_total",
+		Help:      "Requisições atendidas pela API REST.",
+	}, []string{"method", "route", "code"})
+
+	APIRequestDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "api",
+		Name:      "request_duration_seconds",
+		Help:      "Duração das requisições atendidas pela API REST.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	// FetchDuration mede o tempo até os cabeçalhos de cada busca HTTP. O
+	// status é agrupado em classes (2xx, 3xx, 4xx, 5xx) ou "error" quando a
+	// busca falhou sem resposta.
+	FetchDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "fetch",
+		Name:      "duration_seconds",
+		Help:      "Latência das buscas HTTP do crawler, por host e classe de status.",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"host", "status_class"})
+
+	FetchRedirects = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "fetch",
+		Name:      "redirects_total",
+		Help:      "Redirecionamentos seguidos pelo crawler.",
+	})
+
+	BytesDownloaded = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "fetch",
+		Name:      "bytes_downloaded_total",
+		Help:      "Bytes de corpo de resposta lidos pelo crawler.",
+	})
+
+	RetryAttempts = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "crawl",
+		Name:      "retry_attempts_total",
+		Help:      "Novas tentativas de crawling após uma resposta diferente de 200 e 404.",
+	})
+
+	ParseFailures = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "crawl",
+		Name:      "parse_failures_total",
+		Help:      "Páginas cujo HTML não pôde ser interpretado.",
+	})
+
+	// Crawls conta os crawlings de página concluídos pela classe de status
+	// do resultado.
+	Crawls = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "crawl",
+		Name:      "pages_total",
+		Help:      "Crawlings de página concluídos, por classe de status.",
+	}, []string{"status_class"})
+
+	CrawlsInFlight = factory.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "crawl",
+		Name:      "in_flight",
+		Help:      "Crawlings de página em andamento.",
+	})
+
+	APIKeyPages = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "api",
+		Name:      "key_pages_total",
+		Help:      "Páginas descontadas da cota de cada chave de API.",
+	}, []string{"key"})
+
+	RateLimited = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "api",
+		Name:      "rate_limited_total",
+		Help:      "Requisições recusadas pelo limite de taxa, por política.",
+	}, []string{"policy"})
+
+	// ProxyUp indica, por proxy (host:porta), se ele está em uso (1) ou
+	// afastado após falhas (0).
+	ProxyUp = factory.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "proxy",
+		Name:      "up",
+		Help:      "Proxies de saída disponíveis (1) ou afastados (0).",
+	}, []string{"proxy"})
+
+	ProxyFailures = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "proxy",
+		Name:      "failures_total",
+		Help:      "Falhas de conexão com os proxies de saída.",
+	}, []string{"proxy"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// Handler retorna o handler HTTP que expõe as métricas no formato Prometheus.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// StatusClass agrupa um código de status HTTP em 1xx, 2xx, 3xx, 4xx ou 5xx.
+func StatusClass(code int) string {
+	if code < 100 || code > 599 {
+		return "unknown"
+	}
+	return strconv.Itoa(code/100) + "xx"
+}
@@ -0,0 +1,12 @@
+package metrics
+
+import "testing"
+
+func TestStatusClass(t *testing.T) {
+	cases := map[int]string{200: "2xx", 301: "3xx", 404: "4xx", 503: "5xx", 0: "unknown", 999: "unknown"}
+	for code, want := range cases {
+		if got := StatusClass(code); got != want {
+			t.Errorf("StatusClass(%d) = %q, want %q", code, got, want)
+		}
+	}
+}
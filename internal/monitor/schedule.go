@@ -0,0 +1,162 @@
+// Package monitor executa crawlings recorrentes de sites e notifica, por
+// webhook, as mudanças encontradas em relação à execução anterior.
+package monitor
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/nettojulio/ufape-crawler-golang/internal/diff"
+)
+
+// MinInterval é o menor intervalo aceito entre execuções de um agendamento.
+const MinInterval = time.Minute
+
+// DefaultMaxDepth é a profundidade usada quando o agendamento não define uma.
+const DefaultMaxDepth = 3
+
+// Status da última execução de um agendamento.
+const (
+	StatusRunning   = "running"
+	StatusCompleted = "completed"
+	StatusFailed    = "failed"
+)
+
+var (
+	ErrNotFound       = errors.New("schedule not found")
+	ErrAlreadyRunning = errors.New("schedule is already running")
+)
+
+// Schedule é um crawling recorrente de uma URL inicial.
+type Schedule struct {
+	ID      string `json:"id"`
+	SeedURL string `json:"seedUrl"`
+	// Cron é uma expressão de cinco campos. Exclusivo com Interval.
+	Cron string `json:"cron,omitempty"`
+	// Interval é uma duração no formato Go, como "30m" ou "6h".
+	Interval  string `json:"interval,omitempty"`
+	MaxDepth  int    `json:"maxDepth"`
+	FullGraph bool   `json:"fullGraph"`
+	// WebhookURL recebe um POST assinado quando uma execução encontra mudanças.
+	WebhookURL    string `json:"webhookUrl,omitempty"`
+	WebhookSecret string `json:"webhookSecret,omitempty"`
+	Enabled       bool   `json:"enabled"`
+
+	CreatedAt   time.Time     `json:"createdAt"`
+	NextRunAt   *time.Time    `json:"nextRunAt,omitempty"`
+	LastRunAt   *time.Time    `json:"lastRunAt,omitempty"`
+	LastRunID   string        `json:"lastRunId,omitempty"`
+	LastStatus  string        `json:"lastStatus,omitempty"`
+	LastError   string        `json:"lastError,omitempty"`
+	LastChanges *diff.Summary `json:"lastChanges,omitempty"`
+	// BaselineRunID é a última execução concluída, base da próxima comparação
+	// quando a persistência está habilitada.
+	BaselineRunID string `json:"baselineRunId,omitempty"`
+	// BaselineGraph é o hash do grafo da última execução concluída no
+	// armazenamento de grafos, usado quando a persistência está desabilitada.
+	BaselineGraph string `json:"baselineGraph,omitempty"`
+}
+
+// Validate verifica a URL, o webhook e a recorrência do agendamento.
+func (s *Schedule) Validate() error {
+	if err := validateURL(s.SeedURL); err != nil {
+		return fmt.Errorf("invalid seedUrl: %w", err)
+	}
+	if s.WebhookURL != "" {
+		if err := validateURL(s.WebhookURL); err != nil {
+			return fmt.Errorf("invalid webhookUrl: %w", err)
+		}
+	}
+	if s.MaxDepth < 0 {
+		return errors.New("maxDepth must not be negative")
+	}
+
+	switch {
+	case s.Cron != "" && s.Interval != "":
+		return errors.New("cron and interval are mutually exclusive")
+	case s.Cron != "":
+		if _, err := ParseCron(s.Cron); err != nil {
+			return err
+		}
+	case s.Interval != "":
+		d, err := time.ParseDuration(s.Interval)
+		if err != nil {
+			return fmt.Errorf("invalid interval: %w", err)
+		}
+		if d < MinInterval {
+			return fmt.Errorf("interval must be at least %s", MinInterval)
+		}
+	default:
+		return errors.New("either cron or interval is required")
+	}
+	return nil
+}
+
+// next calcula a próxima execução depois de t. O agendamento deve ser válido.
+func (s *Schedule) next(t time.Time) time.Time {
+	if s.Cron != "" {
+		c, err := ParseCron(s.Cron)
+		if err != nil {
+			return time.Time{}
+		}
+		return c.Next(t)
+	}
+	d, err := time.ParseDuration(s.Interval)
+	if err != nil {
+		return time.Time{}
+	}
+	return t.Add(d)
+}
+
+func validateURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("must be an absolute http or https url")
+	}
+	return nil
+}
+
+// ScheduleRequest é o corpo aceito na criação de um agendamento.
+type ScheduleRequest struct {
+	SeedURL       string `json:"seedUrl" validate:"required,url" example:"https://ufape.edu.br"`
+	Cron          string `json:"cron,omitempty" example:"0 3 * * *"`
+	Interval      string `json:"interval,omitempty" example:"6h"`
+	MaxDepth      int    `json:"maxDepth,omitempty" validate:"gte=0" example:"3"`
+	FullGraph     *bool  `json:"fullGraph,omitempty"`
+	WebhookURL    string `json:"webhookUrl,omitempty" validate:"omitempty,url" example:"https://example.com/hooks/crawler"`
+	WebhookSecret string `json:"webhookSecret,omitempty"`
+	Enabled       *bool  `json:"enabled,omitempty"`
+}
+
+// Schedule converte a requisição em um agendamento, habilitado e com grafo
+// completo por padrão.
+func (r ScheduleRequest) Schedule() Schedule {
+	sched := Schedule{
+		SeedURL:       r.SeedURL,
+		Cron:          r.Cron,
+		Interval:      r.Interval,
+		MaxDepth:      r.MaxDepth,
+		FullGraph:     true,
+		WebhookURL:    r.WebhookURL,
+		WebhookSecret: r.WebhookSecret,
+		Enabled:       true,
+	}
+	if r.FullGraph != nil {
+		sched.FullGraph = *r.FullGraph
+	}
+	if r.Enabled != nil {
+		sched.Enabled = *r.Enabled
+	}
+	return sched
+}
+
+// Redacted retorna uma cópia do agendamento sem o segredo do webhook.
+func (s Schedule) Redacted() Schedule {
+	s.WebhookSecret = ""
+	return s
+}
@@ -0,0 +1,535 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nettojulio/ufape-crawler-golang/internal/crawler"
+	"github.com/nettojulio/ufape-crawler-golang/internal/diff"
+	"github.com/nettojulio/ufape-crawler-golang/internal/extractor"
+	"github.com/nettojulio/ufape-crawler-golang/internal/graph"
+	"github.com/nettojulio/ufape-crawler-golang/internal/logging"
+	"github.com/nettojulio/ufape-crawler-golang/internal/snapshot"
+	"github.com/nettojulio/ufape-crawler-golang/internal/storage"
+)
+
+// idleWait é a espera máxima do laço quando não há execuções previstas.
+const idleWait = time.Hour
+
+// Options configura o Scheduler.
+type Options struct {
+	// File, quando definido, guarda os agendamentos em JSON entre reinícios.
+	File           string
+	WebhookTimeout time.Duration
+	// Snapshots, quando definido, guarda o HTML das páginas de cada execução.
+	Snapshots *snapshot.Store
+	// AddressGuard, quando definido, recusa webhooks em endereços internos.
+	AddressGuard *crawler.AddressGuard
+	Logger       *slog.Logger
+}
+
+// Scheduler executa os agendamentos no momento previsto, compara cada
+// crawling com o anterior e envia as mudanças ao webhook do agendamento.
+type Scheduler struct {
+	mu        sync.Mutex
+	schedules map[string]*Schedule
+	running   map[string]bool
+
+	newFetcher func(Schedule) extractor.Fetcher
+	repository storage.Repository
+	// graphs guarda o grafo da última execução concluída de cada agendamento
+	// quando não há repository. O grafo é lido do disco apenas na comparação.
+	graphs    *snapshot.Store
+	webhooks  *WebhookSender
+	snapshots *snapshot.Store
+	file      string
+	logger    *slog.Logger
+
+	wake   chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewScheduler cria um Scheduler que busca as páginas por meio de service.
+// repo é opcional; quando definido, cada execução é registrada nele e serve de
+// base para a comparação. Sem repo, o grafo de cada execução concluída é
+// guardado no diretório graphs, ao lado de opts.File; sem nenhum dos dois, as
+// execuções não são comparadas.
+func NewScheduler(service crawler.Crawler, repo storage.Repository, opts Options) (*Scheduler, error) {
+	if opts.WebhookTimeout <= 0 {
+		opts.WebhookTimeout = 10 * time.Second
+	}
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+
+	var graphs *snapshot.Store
+	if repo == nil && opts.File != "" {
+		var err error
+		graphs, err = snapshot.NewStore(filepath.Join(filepath.Dir(opts.File), "graphs"))
+		if err != nil {
+			return nil, err
+		}
+	} else if repo == nil {
+		opts.Logger.Warn("scheduled runs will not be compared: enable storage or set a schedules file")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Scheduler{
+		schedules: make(map[string]*Schedule),
+		running:   make(map[string]bool),
+		newFetcher: func(sched Schedule) extractor.Fetcher {
+			payload := seedPayload(sched.SeedURL)
+			if opts.Snapshots != nil {
+				payload = extractor.SnapshotPayload(payload)
+			}
+			return extractor.NewServiceFetcher(service, payload)
+		},
+		repository: repo,
+		graphs:     graphs,
+		webhooks:   NewWebhookSender(opts.WebhookTimeout, opts.AddressGuard),
+		snapshots:  opts.Snapshots,
+		file:       opts.File,
+		logger:     opts.Logger,
+		wake:       make(chan struct{}, 1),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+
+	if err := s.load(); err != nil {
+		cancel()
+		return nil, err
+	}
+	return s, nil
+}
+
+// seedPayload restringe o crawling ao domínio da URL inicial e seus subdomínios.
+func seedPayload(seedURL string) crawler.Payload {
+	var domains []string
+	if u, err := url.Parse(seedURL); err == nil {
+		domains = []string{strings.TrimPrefix(u.Host, "www.")}
+	}
+	return crawler.Payload{AllowedDomains: &domains}
+}
+
+// Start inicia o laço de agendamento em segundo plano.
+func (s *Scheduler) Start() {
+	s.wg.Add(1)
+	go s.loop()
+}
+
+// Stop interrompe o laço e as execuções em andamento, aguardando que terminem.
+func (s *Scheduler) Stop() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+// Create valida e registra um agendamento, gerando o ID e, quando há webhook
+// sem segredo, um segredo aleatório.
+func (s *Scheduler) Create(sched Schedule) (Schedule, error) {
+	if err := sched.Validate(); err != nil {
+		return Schedule{}, err
+	}
+	if sched.MaxDepth == 0 {
+		sched.MaxDepth = DefaultMaxDepth
+	}
+	if sched.WebhookURL != "" && sched.WebhookSecret == "" {
+		sched.WebhookSecret = storage.NewID()
+	}
+	sched.ID = storage.NewID()
+	sched.CreatedAt = time.Now().UTC()
+	sched.NextRunAt = nil
+	sched.LastRunAt = nil
+	sched.LastRunID = ""
+	sched.LastStatus = ""
+	sched.LastError = ""
+	sched.LastChanges = nil
+	sched.BaselineRunID = ""
+	sched.BaselineGraph = ""
+	if sched.Enabled {
+		next := sched.next(time.Now())
+		sched.NextRunAt = &next
+	}
+
+	s.mu.Lock()
+	s.schedules[sched.ID] = &sched
+	s.persistLocked()
+	s.mu.Unlock()
+
+	s.notify()
+	return sched, nil
+}
+
+// Get retorna o agendamento id.
+func (s *Scheduler) Get(id string) (Schedule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sched, ok := s.schedules[id]
+	if !ok {
+		return Schedule{}, ErrNotFound
+	}
+	return *sched, nil
+}
+
+// List retorna todos os agendamentos, do mais antigo para o mais recente.
+func (s *Scheduler) List() []Schedule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := make([]Schedule, 0, len(s.schedules))
+	for _, sched := range s.schedules {
+		list = append(list, *sched)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].CreatedAt.Before(list[j].CreatedAt) })
+	return list
+}
+
+// Delete remove o agendamento id. Uma execução em andamento termina normalmente.
+func (s *Scheduler) Delete(id string) error {
+	s.mu.Lock()
+	sched, ok := s.schedules[id]
+	if !ok {
+		s.mu.Unlock()
+		return ErrNotFound
+	}
+	delete(s.schedules, id)
+	s.persistLocked()
+	s.mu.Unlock()
+
+	s.dropGraph(sched.BaselineGraph)
+	return nil
+}
+
+// Trigger inicia imediatamente uma execução do agendamento id, sem alterar a
+// próxima execução prevista.
+func (s *Scheduler) Trigger(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sched, ok := s.schedules[id]
+	if !ok {
+		return ErrNotFound
+	}
+	if s.running[id] {
+		return ErrAlreadyRunning
+	}
+	if s.ctx.Err() != nil {
+		return errors.New("scheduler is stopped")
+	}
+	s.startLocked(sched)
+	return nil
+}
+
+func (s *Scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Scheduler) loop() {
+	defer s.wg.Done()
+
+	for {
+		wait := s.dispatchDue(time.Now())
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-s.ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		case <-s.wake:
+			timer.Stop()
+		}
+	}
+}
+
+// dispatchDue inicia os agendamentos vencidos e retorna quanto esperar até o próximo.
+func (s *Scheduler) dispatchDue(now time.Time) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wait := idleWait
+	for _, sched := range s.schedules {
+		if !sched.Enabled || sched.NextRunAt == nil || sched.NextRunAt.IsZero() {
+			continue
+		}
+		if !sched.NextRunAt.After(now) {
+			next := sched.next(now)
+			sched.NextRunAt = &next
+			if s.running[sched.ID] {
+				s.logger.Warn("skipping scheduled crawl, previous run still in progress", "schedule", sched.ID)
+			} else {
+				s.startLocked(sched)
+			}
+		}
+		if sched.NextRunAt.IsZero() {
+			continue
+		}
+		wait = min(wait, sched.NextRunAt.Sub(now))
+	}
+	return max(wait, 0)
+}
+
+func (s *Scheduler) startLocked(sched *Schedule) {
+	s.running[sched.ID] = true
+	sched.LastStatus = StatusRunning
+	s.persistLocked()
+
+	s.wg.Add(1)
+	go s.execute(*sched)
+}
+
+// execute roda um crawling completo do agendamento e registra o resultado.
+func (s *Scheduler) execute(sched Schedule) {
+	defer s.wg.Done()
+
+	startedAt := time.Now().UTC()
+	logger := s.logger.With("schedule", sched.ID, "seed", sched.SeedURL)
+	logger.Info("scheduled crawl started")
+	ctx := logging.WithContext(s.ctx, logger)
+
+	c := extractor.NewCrawler(s.newFetcher(sched), extractor.Options{
+		MaxDepth:  sched.MaxDepth,
+		FullGraph: sched.FullGraph,
+		Source:    storage.SourceScheduler,
+		Snapshots: s.snapshots,
+		Logger:    logger,
+	})
+	if s.repository != nil {
+		if err := c.RecordTo(ctx, s.repository, sched.SeedURL); err != nil {
+			logger.Warn("failed to record scheduled run", "error", err)
+		}
+	}
+
+	crawlErr := c.Crawl(ctx, sched.SeedURL)
+
+	status := storage.RunStatusCompleted
+	if crawlErr != nil {
+		status = storage.RunStatusFailed
+	}
+	if err := c.FinishRecording(context.WithoutCancel(s.ctx), status); err != nil {
+		logger.Warn("failed to finish scheduled run", "error", err)
+	}
+
+	outcome := runOutcome{startedAt: startedAt, runID: c.RunID(), err: crawlErr}
+	if crawlErr == nil {
+		outcome.completed = true
+		current := c.Result()
+		if previous := s.previousGraph(sched); previous != nil {
+			outcome.report = diff.Compare(previous, current)
+		}
+		outcome.graph = s.saveGraph(sched, current)
+
+		if outcome.report != nil && outcome.report.HasChanges() && sched.WebhookURL != "" {
+			event := Event{
+				Event:      EventChanged,
+				ScheduleID: sched.ID,
+				SeedURL:    sched.SeedURL,
+				RunID:      outcome.runID,
+				OccurredAt: time.Now().UTC(),
+				Report:     outcome.report,
+			}
+			if err := s.webhooks.Send(s.ctx, sched.WebhookURL, sched.WebhookSecret, event); err != nil {
+				outcome.err = err
+			}
+		}
+	}
+
+	if outcome.err != nil {
+		logger.Error("scheduled crawl failed", "error", outcome.err)
+	} else {
+		logger.Info("scheduled crawl finished", "changed", outcome.report != nil && outcome.report.HasChanges())
+	}
+	s.dropGraph(s.finish(sched.ID, outcome))
+}
+
+type runOutcome struct {
+	startedAt time.Time
+	runID     string
+	// completed indica que o crawling terminou e serve de base para a
+	// próxima comparação, mesmo que o webhook tenha falhado.
+	completed bool
+	// graph é o hash do grafo guardado em Scheduler.graphs.
+	graph  string
+	report *diff.Report
+	err    error
+}
+
+// previousGraph carrega o grafo base para a comparação: a execução
+// BaselineRunID do repositório ou o grafo BaselineGraph do armazenamento de
+// grafos. Retorna nil na primeira execução ou se a base não puder ser lida.
+func (s *Scheduler) previousGraph(sched Schedule) *graph.FinalResponse {
+	var (
+		previous *graph.FinalResponse
+		err      error
+	)
+	switch {
+	case s.repository != nil && sched.BaselineRunID != "":
+		previous, err = storage.LoadGraph(s.ctx, s.repository, sched.BaselineRunID)
+	case s.graphs != nil && sched.BaselineGraph != "":
+		previous, err = s.loadGraph(sched.BaselineGraph)
+	default:
+		return nil
+	}
+	if err != nil {
+		s.logger.Warn("failed to load previous run, recording a new baseline", "schedule", sched.ID, "error", err)
+		return nil
+	}
+	return previous
+}
+
+func (s *Scheduler) loadGraph(hash string) (*graph.FinalResponse, error) {
+	data, err := s.graphs.Get(hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load graph %s: %w", hash, err)
+	}
+	var g graph.FinalResponse
+	if err := json.Unmarshal(data, &g); err != nil {
+		return nil, fmt.Errorf("failed to decode graph %s: %w", hash, err)
+	}
+	return &g, nil
+}
+
+// saveGraph guarda current no armazenamento de grafos, quando habilitado, e
+// retorna seu hash. Uma falha apenas impede a próxima comparação.
+func (s *Scheduler) saveGraph(sched Schedule, current *graph.FinalResponse) string {
+	if s.graphs == nil {
+		return ""
+	}
+	data, err := json.Marshal(current)
+	if err == nil {
+		var hash string
+		if hash, err = s.graphs.Put(data); err == nil {
+			return hash
+		}
+	}
+	s.logger.Warn("failed to store scheduled run graph", "schedule", sched.ID, "error", err)
+	return ""
+}
+
+// dropGraph remove do armazenamento um grafo que deixou de ser base.
+func (s *Scheduler) dropGraph(hash string) {
+	if hash == "" || s.graphs == nil {
+		return
+	}
+	if err := s.graphs.Delete(hash); err != nil {
+		s.logger.Warn("failed to delete stale graph", "error", err)
+	}
+}
+
+// finish registra o resultado da execução e retorna o hash do grafo que
+// deixou de ser base, a ser removido fora do lock.
+func (s *Scheduler) finish(id string, outcome runOutcome) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.running, id)
+	sched, ok := s.schedules[id]
+	if !ok {
+		return outcome.graph
+	}
+
+	sched.LastRunAt = &outcome.startedAt
+	sched.LastStatus = StatusCompleted
+	sched.LastError = ""
+	if outcome.err != nil {
+		sched.LastStatus = StatusFailed
+		sched.LastError = outcome.err.Error()
+	}
+	if outcome.runID != "" && !errors.Is(outcome.err, context.Canceled) {
+		sched.LastRunID = outcome.runID
+	}
+	if outcome.report != nil {
+		summary := outcome.report.Summary
+		sched.LastChanges = &summary
+	}
+
+	stale := ""
+	if outcome.completed {
+		if outcome.runID != "" {
+			sched.BaselineRunID = outcome.runID
+		}
+		if outcome.graph != sched.BaselineGraph {
+			stale, sched.BaselineGraph = sched.BaselineGraph, outcome.graph
+		}
+	}
+	s.persistLocked()
+	return stale
+}
+
+// load lê os agendamentos do arquivo, se houver. Execuções interrompidas por
+// um encerramento são marcadas como falhas.
+func (s *Scheduler) load() error {
+	if s.file == "" {
+		return nil
+	}
+	data, err := os.ReadFile(s.file)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read schedules file: %w", err)
+	}
+
+	var schedules []*Schedule
+	if err := json.Unmarshal(data, &schedules); err != nil {
+		return fmt.Errorf("failed to decode schedules file: %w", err)
+	}
+	for _, sched := range schedules {
+		if sched.LastStatus == StatusRunning {
+			sched.LastStatus = StatusFailed
+			sched.LastError = "interrupted by shutdown"
+		}
+		// Arquivos anteriores a BaselineRunID usavam a última execução como base.
+		if sched.BaselineRunID == "" && sched.LastStatus == StatusCompleted {
+			sched.BaselineRunID = sched.LastRunID
+		}
+		s.schedules[sched.ID] = sched
+	}
+	return nil
+}
+
+func (s *Scheduler) persistLocked() {
+	if s.file == "" {
+		return
+	}
+
+	list := make([]*Schedule, 0, len(s.schedules))
+	for _, sched := range s.schedules {
+		list = append(list, sched)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].CreatedAt.Before(list[j].CreatedAt) })
+
+	if err := writeFileAtomic(s.file, list); err != nil {
+		s.logger.Error("failed to persist schedules", "file", s.file, "error", err)
+	}
+}
+
+func writeFileAtomic(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
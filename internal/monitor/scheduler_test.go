@@ -0,0 +1,226 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nettojulio/ufape-crawler-golang/internal/crawler"
+	"github.com/nettojulio/ufape-crawler-golang/internal/diff"
+	"github.com/nettojulio/ufape-crawler-golang/internal/extractor"
+)
+
+// siteFetcher simula um site cujas páginas podem mudar entre execuções.
+type siteFetcher struct {
+	mu    sync.Mutex
+	pages map[string]*crawler.ResponseDTO
+}
+
+func (f *siteFetcher) Fetch(ctx context.Context, link string) (*crawler.ResponseDTO, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	page, ok := f.pages[link]
+	if !ok {
+		return nil, errors.New("connection refused")
+	}
+	copied := *page
+	return &copied, nil
+}
+
+func (f *siteFetcher) set(link string, page *crawler.ResponseDTO) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.pages[link] = page
+}
+
+type webhookRecorder struct {
+	mu     sync.Mutex
+	events []Event
+	secret string
+	t      *testing.T
+}
+
+func (r *webhookRecorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	timestamp, _ := strconv.ParseInt(req.Header.Get(HeaderTimestamp), 10, 64)
+	if !Verify(r.secret, timestamp, body, req.Header.Get(HeaderSignature)) {
+		r.t.Errorf("invalid webhook signature %q", req.Header.Get(HeaderSignature))
+	}
+	if req.Header.Get(HeaderEvent) != EventChanged {
+		r.t.Errorf("unexpected event header %q", req.Header.Get(HeaderEvent))
+	}
+
+	var event Event
+	if err := json.Unmarshal(body, &event); err != nil {
+		r.t.Errorf("invalid webhook body: %v", err)
+	}
+	r.mu.Lock()
+	r.events = append(r.events, event)
+	r.mu.Unlock()
+}
+
+func (r *webhookRecorder) received() []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Event(nil), r.events...)
+}
+
+func newTestScheduler(t *testing.T, fetcher extractor.Fetcher, file string) *Scheduler {
+	t.Helper()
+	s, err := NewScheduler(nil, nil, Options{File: file, Logger: slog.New(slog.NewTextHandler(io.Discard, nil))})
+	if err != nil {
+		t.Fatalf("NewScheduler() returned an unexpected error: %v", err)
+	}
+	s.newFetcher = func(Schedule) extractor.Fetcher { return fetcher }
+	t.Cleanup(s.Stop)
+	return s
+}
+
+func runAndWait(t *testing.T, s *Scheduler, id string) Schedule {
+	t.Helper()
+	if err := s.Trigger(id); err != nil {
+		t.Fatalf("Trigger() returned an unexpected error: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		sched, _ := s.Get(id)
+		if sched.LastStatus != StatusRunning {
+			return sched
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("scheduled run did not finish in time")
+	return Schedule{}
+}
+
+func TestSchedulerDetectsChanges(t *testing.T) {
+	fetcher := &siteFetcher{pages: map[string]*crawler.ResponseDTO{
+		"https://example.com":   {StatusCode: 200, Title: "Home", Links: crawler.LinksResponse{Available: []string{"https://example.com/a"}}},
+		"https://example.com/a": {StatusCode: 200, Title: "A"},
+	}}
+	recorder := &webhookRecorder{t: t}
+	server := httptest.NewServer(recorder)
+	defer server.Close()
+
+	s := newTestScheduler(t, fetcher, filepath.Join(t.TempDir(), "schedules.json"))
+	sched, err := s.Create(Schedule{SeedURL: "https://example.com", Interval: "1h", WebhookURL: server.URL, Enabled: true})
+	if err != nil {
+		t.Fatalf("Create() returned an unexpected error: %v", err)
+	}
+	if sched.WebhookSecret == "" || sched.MaxDepth != DefaultMaxDepth || sched.NextRunAt == nil {
+		t.Fatalf("expected defaults to be filled, got %+v", sched)
+	}
+	recorder.secret = sched.WebhookSecret
+
+	first := runAndWait(t, s, sched.ID)
+	if first.LastStatus != StatusCompleted || first.LastChanges != nil {
+		t.Fatalf("expected the first run to record a baseline, got %+v", first)
+	}
+
+	unchanged := runAndWait(t, s, sched.ID)
+	if unchanged.LastChanges == nil || *unchanged.LastChanges != (diff.Summary{}) {
+		t.Fatalf("expected an empty change summary, got %+v", unchanged.LastChanges)
+	}
+	if len(recorder.received()) != 0 {
+		t.Fatalf("expected no webhook for an unchanged site, got %d", len(recorder.received()))
+	}
+
+	fetcher.set("https://example.com/a", &crawler.ResponseDTO{StatusCode: 404, Title: "A"})
+	changed := runAndWait(t, s, sched.ID)
+	if changed.LastStatus != StatusCompleted || changed.LastChanges == nil || changed.LastChanges.StatusChanges != 1 {
+		t.Fatalf("expected a status change to be detected, got %+v", changed)
+	}
+
+	events := recorder.received()
+	if len(events) != 1 {
+		t.Fatalf("expected 1 webhook, got %d", len(events))
+	}
+	if events[0].ScheduleID != sched.ID || events[0].Report.Summary.NewlyBrokenLinks != 1 {
+		t.Errorf("unexpected webhook event: %+v", events[0])
+	}
+}
+
+func TestSchedulerKeepsBaselineOnDisk(t *testing.T) {
+	fetcher := &siteFetcher{pages: map[string]*crawler.ResponseDTO{
+		"https://example.com": {StatusCode: 200, Title: "Home"},
+	}}
+	dir := t.TempDir()
+	file := filepath.Join(dir, "schedules.json")
+
+	s := newTestScheduler(t, fetcher, file)
+	sched, _ := s.Create(Schedule{SeedURL: "https://example.com", Interval: "1h"})
+	first := runAndWait(t, s, sched.ID)
+	if first.BaselineGraph == "" {
+		t.Fatalf("expected the first run to store its graph, got %+v", first)
+	}
+	s.Stop()
+
+	fetcher.set("https://example.com", &crawler.ResponseDTO{StatusCode: 500, Title: "Home"})
+	reloaded := newTestScheduler(t, fetcher, file)
+	second := runAndWait(t, reloaded, sched.ID)
+	if second.LastChanges == nil || second.LastChanges.StatusChanges != 1 {
+		t.Fatalf("expected the run after a restart to be compared with the stored graph, got %+v", second.LastChanges)
+	}
+
+	graphs, _ := filepath.Glob(filepath.Join(dir, "graphs", "*", "*"))
+	if len(graphs) != 1 || second.BaselineGraph == first.BaselineGraph {
+		t.Errorf("expected only the latest graph to be kept, got %v", graphs)
+	}
+	if err := reloaded.Delete(sched.ID); err != nil {
+		t.Fatalf("Delete() returned an unexpected error: %v", err)
+	}
+	if graphs, _ := filepath.Glob(filepath.Join(dir, "graphs", "*", "*")); len(graphs) != 0 {
+		t.Errorf("expected the graph to be removed with its schedule, got %v", graphs)
+	}
+}
+
+func TestSchedulerPersistsSchedules(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "schedules.json")
+	fetcher := &siteFetcher{pages: map[string]*crawler.ResponseDTO{}}
+
+	s := newTestScheduler(t, fetcher, file)
+	kept, _ := s.Create(Schedule{SeedURL: "https://example.com", Cron: "0 3 * * *", Enabled: true})
+	removed, _ := s.Create(Schedule{SeedURL: "https://example.org", Interval: "30m"})
+	if err := s.Delete(removed.ID); err != nil {
+		t.Fatalf("Delete() returned an unexpected error: %v", err)
+	}
+
+	reloaded := newTestScheduler(t, fetcher, file)
+	list := reloaded.List()
+	if len(list) != 1 || list[0].ID != kept.ID || list[0].Cron != "0 3 * * *" {
+		t.Fatalf("unexpected schedules after reload: %+v", list)
+	}
+	if _, err := reloaded.Get(removed.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for a deleted schedule, got %v", err)
+	}
+}
+
+func TestScheduleValidate(t *testing.T) {
+	testCases := []struct {
+		name  string
+		sched Schedule
+	}{
+		{"missing recurrence", Schedule{SeedURL: "https://example.com"}},
+		{"cron and interval", Schedule{SeedURL: "https://example.com", Cron: "* * * * *", Interval: "1h"}},
+		{"interval too short", Schedule{SeedURL: "https://example.com", Interval: "10s"}},
+		{"relative seed", Schedule{SeedURL: "/path", Interval: "1h"}},
+		{"invalid webhook", Schedule{SeedURL: "https://example.com", Interval: "1h", WebhookURL: "ftp://example.com"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.sched.Validate(); err == nil {
+				t.Error("expected a validation error")
+			}
+		})
+	}
+}
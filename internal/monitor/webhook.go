@@ -0,0 +1,128 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/nettojulio/ufape-crawler-golang/internal/crawler"
+	"github.com/nettojulio/ufape-crawler-golang/internal/diff"
+)
+
+// EventChanged é enviado quando uma execução encontra mudanças.
+const EventChanged = "crawl.changed"
+
+// Cabeçalhos enviados em cada entrega de webhook.
+const (
+	HeaderEvent     = "X-Crawler-Event"
+	HeaderTimestamp = "X-Crawler-Timestamp"
+	HeaderSignature = "X-Crawler-Signature"
+)
+
+// Event é o corpo JSON enviado ao webhook de um agendamento.
+type Event struct {
+	Event      string       `json:"event"`
+	ScheduleID string       `json:"scheduleId"`
+	SeedURL    string       `json:"seedUrl"`
+	RunID      string       `json:"runId,omitempty"`
+	OccurredAt time.Time    `json:"occurredAt"`
+	Report     *diff.Report `json:"report"`
+}
+
+// Sign calcula a assinatura de um corpo de webhook: "sha256=" seguido do
+// HMAC-SHA256 hexadecimal de "<timestamp>.<corpo>" com o segredo do agendamento.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify confere uma assinatura gerada por Sign em tempo constante.
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// WebhookSender entrega eventos, tentando novamente em falhas de rede e
+// respostas 5xx ou 429.
+type WebhookSender struct {
+	client      *http.Client
+	maxAttempts int
+	backoff     time.Duration
+}
+
+// NewWebhookSender cria um WebhookSender com o timeout informado por
+// tentativa. guard é opcional; quando definido, os webhooks em endereços
+// internos são recusados, como as buscas do crawler.
+func NewWebhookSender(timeout time.Duration, guard *crawler.AddressGuard) *WebhookSender {
+	client := &http.Client{Timeout: timeout}
+	if guard != nil {
+		client.Transport = crawler.NewGuardedTransport(guard)
+	}
+	return &WebhookSender{
+		client:      client,
+		maxAttempts: 3,
+		backoff:     2 * time.Second,
+	}
+}
+
+// Send entrega event em webhookURL, assinando-o com secret.
+func (w *WebhookSender) Send(ctx context.Context, webhookURL, secret string, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook event: %w", err)
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= w.maxAttempts; attempt++ {
+		retry, err := w.deliver(ctx, webhookURL, secret, event.Event, body)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retry || attempt == w.maxAttempts {
+			break
+		}
+
+		select {
+		case <-time.After(w.backoff * time.Duration(1<<(attempt-1))):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return fmt.Errorf("failed to deliver webhook after %d attempts: %w", w.maxAttempts, lastErr)
+}
+
+func (w *WebhookSender) deliver(ctx context.Context, webhookURL, secret, event string, body []byte) (retry bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, event)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(secret, timestamp, body))
+
+	res, err := w.client.Do(req)
+	if err != nil {
+		// Um destino bloqueado pelo AddressGuard não muda entre tentativas.
+		return !errors.Is(err, crawler.ErrBlockedAddress), err
+	}
+	res.Body.Close()
+
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return false, nil
+	}
+	retry = res.StatusCode >= http.StatusInternalServerError || res.StatusCode == http.StatusTooManyRequests
+	return retry, fmt.Errorf("webhook responded with status %s", res.Status)
+}
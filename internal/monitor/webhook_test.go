@@ -0,0 +1,55 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nettojulio/ufape-crawler-golang/internal/crawler"
+)
+
+func TestWebhookSenderAddressGuard(t *testing.T) {
+	var received atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received.Add(1)
+	}))
+	defer server.Close()
+	event := Event{Event: EventChanged, ScheduleID: "s1", OccurredAt: time.Now()}
+
+	t.Run("should refuse internal webhooks without retrying", func(t *testing.T) {
+		guard, err := crawler.NewAddressGuard(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sender := NewWebhookSender(time.Second, guard)
+
+		start := time.Now()
+		err = sender.Send(context.Background(), server.URL, "secret", event)
+		if !errors.Is(err, crawler.ErrBlockedAddress) {
+			t.Fatalf("expected ErrBlockedAddress, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("expected no retries for a blocked address, took %s", elapsed)
+		}
+		if received.Load() != 0 {
+			t.Errorf("expected the webhook not to be called, got %d calls", received.Load())
+		}
+	})
+
+	t.Run("should deliver to allowlisted addresses", func(t *testing.T) {
+		guard, err := crawler.NewAddressGuard([]string{"127.0.0.1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := NewWebhookSender(time.Second, guard).Send(context.Background(), server.URL, "secret", event); err != nil {
+			t.Fatalf("Send() returned an unexpected error: %v", err)
+		}
+		if received.Load() != 1 {
+			t.Errorf("expected one delivery, got %d", received.Load())
+		}
+	})
+}
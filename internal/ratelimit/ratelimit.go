@@ -0,0 +1,119 @@
+// Package ratelimit limita a taxa de requisições por cliente com um token
+// bucket por chave (ID da chave de API ou IP).
+package ratelimit
+
+import (
+	"math"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// sweepInterval é de quanto em quanto tempo os buckets cheios são descartados.
+const sweepInterval = time.Minute
+
+// Decision é o resultado de Allow, com os valores dos cabeçalhos RateLimit-*.
+type Decision struct {
+	Allowed bool
+	// Limit é a capacidade do bucket (a rajada permitida).
+	Limit int
+	// Remaining é quantas requisições ainda cabem agora.
+	Remaining int
+	// Reset é quanto falta para o bucket voltar a ficar cheio.
+	Reset time.Duration
+	// RetryAfter é quanto falta para a próxima requisição ser aceita, quando
+	// Allowed é false.
+	RetryAfter time.Duration
+}
+
+// Seconds formata d em segundos inteiros, arredondando para cima, como nos
+// cabeçalhos RateLimit-Reset e Retry-After.
+func Seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
+
+// Limits são os limites compartilhados pela API REST e pelo gRPC: Crawl para
+// as buscas de uma página e Jobs para os lotes e os crawlings de várias
+// páginas. Um Limits nil, ou um campo nil, não limita.
+type Limits struct {
+	Crawl *Limiter
+	Jobs  *Limiter
+}
+
+// Limiter aceita até Burst requisições seguidas por chave e repõe Rate
+// requisições a cada Per.
+type Limiter struct {
+	rate  float64 // tokens por segundo
+	burst float64
+	now   func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// New cria um Limiter que repõe rate requisições a cada per, com rajadas de
+// até burst. burst <= 0 usa rate.
+func New(rate int, per time.Duration, burst int) *Limiter {
+	if burst <= 0 {
+		burst = rate
+	}
+	return &Limiter{
+		rate:    float64(rate) / per.Seconds(),
+		burst:   float64(burst),
+		now:     time.Now,
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// Allow consome uma requisição do bucket de key, se houver.
+func (l *Limiter) Allow(key string) Decision {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweepLocked(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+
+	d := Decision{Limit: int(l.burst)}
+	if b.tokens >= 1 {
+		b.tokens--
+		d.Allowed = true
+	} else {
+		d.RetryAfter = l.duration(1 - b.tokens)
+	}
+	d.Remaining = int(b.tokens)
+	d.Reset = l.duration(l.burst - b.tokens)
+	return d
+}
+
+// duration converte tokens no tempo necessário para repô-los.
+func (l *Limiter) duration(tokens float64) time.Duration {
+	return time.Duration(math.Ceil(tokens / l.rate * float64(time.Second)))
+}
+
+// sweepLocked descarta os buckets que já estariam cheios, equivalentes a
+// clientes que nunca fizeram requisições.
+func (l *Limiter) sweepLocked(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*l.rate >= l.burst {
+			delete(l.buckets, key)
+		}
+	}
+}
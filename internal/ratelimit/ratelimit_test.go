@@ -0,0 +1,68 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestLimiter(rate int, per time.Duration, burst int) (*Limiter, *time.Time) {
+	l := New(rate, per, burst)
+	now := time.Date(2026, 1, 31, 12, 0, 0, 0, time.UTC)
+	l.now = func() time.Time { return now }
+	return l, &now
+}
+
+func TestAllow(t *testing.T) {
+	l, now := newTestLimiter(60, time.Minute, 2)
+
+	first := l.Allow("a")
+	if !first.Allowed || first.Limit != 2 || first.Remaining != 1 || first.Reset != time.Second {
+		t.Errorf("unexpected first decision: %+v", first)
+	}
+	if d := l.Allow("a"); !d.Allowed || d.Remaining != 0 || d.Reset != 2*time.Second {
+		t.Errorf("expected the burst to allow a second request, got %+v", d)
+	}
+
+	denied := l.Allow("a")
+	if denied.Allowed || denied.RetryAfter != time.Second || denied.Remaining != 0 {
+		t.Errorf("expected a denial with Retry-After, got %+v", denied)
+	}
+	if d := l.Allow("b"); !d.Allowed {
+		t.Errorf("expected clients to have separate buckets, got %+v", d)
+	}
+
+	*now = now.Add(500 * time.Millisecond)
+	if d := l.Allow("a"); d.Allowed || d.RetryAfter != 500*time.Millisecond {
+		t.Errorf("expected half a token to be refilled, got %+v", d)
+	}
+	*now = now.Add(500 * time.Millisecond)
+	if d := l.Allow("a"); !d.Allowed {
+		t.Errorf("expected a refilled token to be allowed, got %+v", d)
+	}
+}
+
+func TestDefaultBurstAndSweep(t *testing.T) {
+	l, now := newTestLimiter(3, time.Second, 0)
+	for i := range 3 {
+		if d := l.Allow("a"); !d.Allowed {
+			t.Fatalf("expected request %d within the default burst, got %+v", i, d)
+		}
+	}
+	if d := l.Allow("a"); d.Allowed {
+		t.Errorf("expected the burst to default to the rate, got %+v", d)
+	}
+
+	*now = now.Add(2 * sweepInterval)
+	l.Allow("b")
+	if _, ok := l.buckets["a"]; ok {
+		t.Error("expected the idle full bucket to be swept")
+	}
+}
+
+func TestSeconds(t *testing.T) {
+	for d, want := range map[time.Duration]string{0: "0", time.Second: "1", 1500 * time.Millisecond: "2", time.Millisecond: "1"} {
+		if got := Seconds(d); got != want {
+			t.Errorf("Seconds(%s): expected %s, got %s", d, want, got)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+// Package snapshot guarda o HTML das páginas buscadas em um armazenamento
+// endereçado pelo conteúdo, para que análises posteriores não precisem
+// buscá-las novamente.
+package snapshot
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// ErrNotFound é retornado quando não há snapshot para o hash informado.
+var ErrNotFound = errors.New("snapshot not found")
+
+// Store guarda corpos de páginas em arquivos gzip nomeados pelo SHA-256 do
+// conteúdo original, em <dir>/<dois primeiros dígitos>/<hash>.gz. Conteúdos
+// repetidos são gravados uma única vez.
+type Store struct {
+	dir string
+}
+
+// NewStore cria o diretório do armazenamento, se necessário.
+func NewStore(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	return &Store{dir: dir}, nil
+}
+
+// OpenStore abre um armazenamento existente, para leitura, sem criar o
+// diretório: um caminho errado falha em vez de parecer um armazenamento vazio.
+func OpenStore(dir string) (*Store, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open snapshot directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("failed to open snapshot directory: %s is not a directory", dir)
+	}
+	return &Store{dir: dir}, nil
+}
+
+// Hash retorna a chave de body no armazenamento: o SHA-256 em hexadecimal,
+// o mesmo valor de ContentHash nas respostas do crawler.
+func Hash(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// Put grava body, se ainda não existir, e retorna seu hash.
+func (s *Store) Put(body []byte) (string, error) {
+	hash := Hash(body)
+	path := s.path(hash)
+	if _, err := os.Stat(path); err == nil {
+		return hash, nil
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(body); err != nil {
+		return "", err
+	}
+	if err := gz.Close(); err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), hash+".*.tmp")
+	if err != nil {
+		return "", fmt.Errorf("failed to create snapshot: %w", err)
+	}
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return "", fmt.Errorf("failed to store snapshot: %w", err)
+	}
+	return hash, nil
+}
+
+// Has informa se há um snapshot para hash.
+func (s *Store) Has(hash string) bool {
+	if !validHash(hash) {
+		return false
+	}
+	_, err := os.Stat(s.path(hash))
+	return err == nil
+}
+
+// Get retorna o conteúdo guardado sob hash, verificando sua integridade.
+func (s *Store) Get(hash string) ([]byte, error) {
+	if !validHash(hash) {
+		return nil, fmt.Errorf("invalid snapshot hash %q", hash)
+	}
+
+	file, err := os.Open(s.path(hash))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open snapshot %s: %w", hash, err)
+	}
+	body, err := io.ReadAll(gz)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot %s: %w", hash, err)
+	}
+	if Hash(body) != hash {
+		return nil, fmt.Errorf("snapshot %s is corrupted", hash)
+	}
+	return body, nil
+}
+
+// Delete remove o snapshot guardado sob hash. Remover um hash ausente não é
+// um erro.
+func (s *Store) Delete(hash string) error {
+	if !validHash(hash) {
+		return fmt.Errorf("invalid snapshot hash %q", hash)
+	}
+	if err := os.Remove(s.path(hash)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete snapshot %s: %w", hash, err)
+	}
+	return nil
+}
+
+func (s *Store) path(hash string) string {
+	return filepath.Join(s.dir, hash[:2], hash+".gz")
+}
+
+func validHash(hash string) bool {
+	if len(hash) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(hash)
+	return err == nil
+}
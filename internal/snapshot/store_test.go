@@ -0,0 +1,87 @@
+package snapshot
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewStore(dir)
+	if err != nil {
+		t.Fatalf("NewStore() returned an unexpected error: %v", err)
+	}
+
+	body := []byte("<html><title>UFAPE</title></html>")
+	hash, err := store.Put(body)
+	if err != nil {
+		t.Fatalf("Put() returned an unexpected error: %v", err)
+	}
+	if hash != Hash(body) || !store.Has(hash) {
+		t.Fatalf("expected the snapshot to be stored under its sha256, got %s", hash)
+	}
+
+	again, err := store.Put(body)
+	if err != nil || again != hash {
+		t.Fatalf("expected a repeated Put to return the same hash, got %s (%v)", again, err)
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "*", "*"))
+	if len(files) != 1 {
+		t.Errorf("expected identical content to be stored once, got %v", files)
+	}
+
+	got, err := store.Get(hash)
+	if err != nil || string(got) != string(body) {
+		t.Errorf("Get() = %q, %v", got, err)
+	}
+
+	if err := store.Delete(hash); err != nil || store.Has(hash) {
+		t.Errorf("expected Delete() to remove the snapshot, got %v", err)
+	}
+	if err := store.Delete(hash); err != nil {
+		t.Errorf("expected deleting a missing snapshot to succeed, got %v", err)
+	}
+}
+
+func TestStoreGetErrors(t *testing.T) {
+	dir := t.TempDir()
+	store, _ := NewStore(dir)
+
+	if _, err := store.Get(Hash([]byte("missing"))); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if _, err := store.Get("../../etc/passwd"); err == nil {
+		t.Error("expected an error for an invalid hash")
+	}
+
+	hash, _ := store.Put([]byte("original"))
+	other, _ := store.Put([]byte("other"))
+	_ = os.Rename(store.path(other), store.path(hash))
+	if _, err := store.Get(hash); err == nil {
+		t.Error("expected an error for a corrupted snapshot")
+	}
+}
+
+func TestOpenStore(t *testing.T) {
+	dir := t.TempDir()
+	store, _ := NewStore(dir)
+	hash, _ := store.Put([]byte("<html></html>"))
+
+	opened, err := OpenStore(dir)
+	if err != nil {
+		t.Fatalf("OpenStore() returned an unexpected error: %v", err)
+	}
+	if body, err := opened.Get(hash); err != nil || string(body) != "<html></html>" {
+		t.Errorf("expected the stored page, got %q (%v)", body, err)
+	}
+
+	missing := filepath.Join(dir, "typo")
+	if _, err := OpenStore(missing); err == nil {
+		t.Error("expected an error for a missing directory")
+	}
+	if _, err := os.Stat(missing); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected OpenStore not to create the directory, got %v", err)
+	}
+}
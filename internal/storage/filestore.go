@@ -0,0 +1,311 @@
+package storage
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	runFile     = "run.json"
+	pagesFile   = "pages.ndjson"
+	fetchesFile = "fetches.ndjson"
+	edgesFile   = "edges.ndjson"
+	adHocDir    = "_adhoc"
+)
+
+// runIDPattern é o formato dos IDs gerados por NewID. Os demais são
+// recusados antes de virar caminho, para que IDs como ".." não saiam de root.
+var runIDPattern = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+// FileStore é um Repository embarcado que grava cada execução em um diretório
+// próprio, com registros em NDJSON anexados ao final dos arquivos. Páginas e
+// arestas repetidas substituem as anteriores na leitura.
+type FileStore struct {
+	mu   sync.Mutex
+	root string
+}
+
+// NewFileStore cria um FileStore no diretório root, criando-o se necessário.
+func NewFileStore(root string) (*FileStore, error) {
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory %s: %w", root, err)
+	}
+	return &FileStore{root: root}, nil
+}
+
+func (s *FileStore) CreateRun(_ context.Context, run *Run) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if run.ID == "" {
+		run.ID = NewID()
+	}
+	if run.Status == "" {
+		run.Status = RunStatusRunning
+	}
+	if run.StartedAt.IsZero() {
+		run.StartedAt = time.Now().UTC()
+	}
+	dir, err := s.runDir(run.ID)
+	if err != nil {
+		return fmt.Errorf("invalid run id %q", run.ID)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create run directory: %w", err)
+	}
+	return s.writeRun(run)
+}
+
+func (s *FileStore) FinishRun(_ context.Context, runID string, status RunStatus, finishedAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	run, err := s.readRun(runID)
+	if err != nil {
+		return err
+	}
+	run.Status = status
+	run.FinishedAt = &finishedAt
+	return s.writeRun(run)
+}
+
+func (s *FileStore) GetRun(_ context.Context, runID string) (*Run, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.readRun(runID)
+}
+
+func (s *FileStore) ListRuns(_ context.Context, limit int) ([]Run, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := os.ReadDir(s.root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list runs: %w", err)
+	}
+
+	runs := []Run{}
+	for _, entry := range entries {
+		if !entry.IsDir() || entry.Name() == adHocDir {
+			continue
+		}
+		run, err := s.readRun(entry.Name())
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, *run)
+	}
+
+	sort.SliceStable(runs, func(i, j int) bool {
+		return runs[i].StartedAt.After(runs[j].StartedAt)
+	})
+	if limit > 0 && len(runs) > limit {
+		runs = runs[:limit]
+	}
+	return runs, nil
+}
+
+func (s *FileStore) SavePage(_ context.Context, page Page) error {
+	if page.SavedAt.IsZero() {
+		page.SavedAt = time.Now().UTC()
+	}
+	return s.append(page.RunID, pagesFile, page)
+}
+
+func (s *FileStore) ListPages(_ context.Context, runID string) ([]Page, error) {
+	pages := []Page{}
+	positions := make(map[string]int)
+	err := s.scan(runID, pagesFile, func(data []byte) error {
+		var page Page
+		if err := json.Unmarshal(data, &page); err != nil {
+			return err
+		}
+		if i, ok := positions[page.URL]; ok {
+			pages[i] = page
+			return nil
+		}
+		positions[page.URL] = len(pages)
+		pages = append(pages, page)
+		return nil
+	})
+	return pages, err
+}
+
+func (s *FileStore) SaveFetch(_ context.Context, fetch Fetch) error {
+	if fetch.FetchedAt.IsZero() {
+		fetch.FetchedAt = time.Now().UTC()
+	}
+	return s.append(fetch.RunID, fetchesFile, fetch)
+}
+
+func (s *FileStore) ListFetches(_ context.Context, runID string) ([]Fetch, error) {
+	fetches := []Fetch{}
+	err := s.scan(runID, fetchesFile, func(data []byte) error {
+		var fetch Fetch
+		if err := json.Unmarshal(data, &fetch); err != nil {
+			return err
+		}
+		fetches = append(fetches, fetch)
+		return nil
+	})
+	return fetches, err
+}
+
+func (s *FileStore) SaveEdges(_ context.Context, edges []Edge) error {
+	for _, edge := range edges {
+		if err := s.append(edge.RunID, edgesFile, edge); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *FileStore) ListEdges(_ context.Context, runID string) ([]Edge, error) {
+	edges := []Edge{}
+	positions := make(map[[2]string]int)
+	err := s.scan(runID, edgesFile, func(data []byte) error {
+		var edge Edge
+		if err := json.Unmarshal(data, &edge); err != nil {
+			return err
+		}
+		key := [2]string{edge.Source, edge.Target}
+		if i, ok := positions[key]; ok {
+			edges[i] = edge
+			return nil
+		}
+		positions[key] = len(edges)
+		edges = append(edges, edge)
+		return nil
+	})
+	return edges, err
+}
+
+func (s *FileStore) Ping(_ context.Context) error {
+	_, err := os.Stat(s.root)
+	return err
+}
+
+func (s *FileStore) Close() error {
+	return nil
+}
+
+// runDir retorna o diretório da execução runID ou, com runID vazio, o das
+// buscas avulsas. IDs fora do formato de NewID retornam ErrNotFound.
+func (s *FileStore) runDir(runID string) (string, error) {
+	if runID == "" {
+		return filepath.Join(s.root, adHocDir), nil
+	}
+	if !runIDPattern.MatchString(runID) {
+		return "", ErrNotFound
+	}
+	return filepath.Join(s.root, runID), nil
+}
+
+func (s *FileStore) readRun(runID string) (*Run, error) {
+	dir, err := s.runDir(runID)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, runFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read run %s: %w", runID, err)
+	}
+	var run Run
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil, fmt.Errorf("failed to decode run %s: %w", runID, err)
+	}
+	return &run, nil
+}
+
+func (s *FileStore) writeRun(run *Run) error {
+	data, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode run %s: %w", run.ID, err)
+	}
+	dir, err := s.runDir(run.ID)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, runFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write run %s: %w", run.ID, err)
+	}
+	return os.Rename(tmp, path)
+}
+
+func (s *FileStore) append(runID, name string, record any) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode record: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dir, err := s.runDir(runID)
+	if err != nil {
+		return err
+	}
+	if runID != "" {
+		if _, err := os.Stat(filepath.Join(dir, runFile)); err != nil {
+			return ErrNotFound
+		}
+	} else if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+
+	f, err := os.OpenFile(filepath.Join(dir, name), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", name, err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to append to %s: %w", name, err)
+	}
+	return nil
+}
+
+func (s *FileStore) scan(runID, name string, fn func([]byte) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dir, err := s.runDir(runID)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(filepath.Join(dir, name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", name, err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if err := fn(scanner.Bytes()); err != nil {
+			return fmt.Errorf("failed to decode %s: %w", name, err)
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nettojulio/ufape-crawler-golang/internal/graph"
+)
+
+// GraphWriter grava os nós e arestas de um crawling em uma execução do Repository.
+type GraphWriter struct {
+	ctx   context.Context
+	repo  Repository
+	runID string
+}
+
+// NewGraphWriter cria um GraphWriter para a execução runID.
+func NewGraphWriter(ctx context.Context, repo Repository, runID string) *GraphWriter {
+	return &GraphWriter{ctx: ctx, repo: repo, runID: runID}
+}
+
+// WriteNode grava o nó como uma página da execução.
+func (w *GraphWriter) WriteNode(n graph.Node) error {
+	return w.repo.SavePage(w.ctx, PageFromNode(w.runID, n))
+}
+
+// WriteLink grava a aresta na execução.
+func (w *GraphWriter) WriteLink(l graph.Link) error {
+	return w.repo.SaveEdges(w.ctx, []Edge{{
+		RunID:  w.runID,
+		Source: l.Source,
+		Target: l.Target,
+		Count:  max(l.Count, 1),
+	}})
+}
+
+// PageFromNode converte um nó do grafo em uma página da execução runID.
+func PageFromNode(runID string, n graph.Node) Page {
+	return Page{
+		RunID:       runID,
+		URL:         n.ID,
+		Depth:       n.Depth,
+		StatusCode:  n.StatusCode,
+		ContentType: n.ContentType,
+		ElapsedTime: time.Duration(n.ElapsedTime),
+		Title:       n.Title,
+		Domain:      n.Domain,
+		ContentHash: n.ContentHash,
+		Tags:        n.Tags,
+		ErrorClass:  n.ErrorClass,
+		Error:       n.Error,
+		SavedAt:     time.Now().UTC(),
+	}
+}
+
+// LoadGraph reconstrói o grafo de uma execução a partir do Repository.
+func LoadGraph(ctx context.Context, repo Repository, runID string) (*graph.FinalResponse, error) {
+	run, err := repo.GetRun(ctx, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load run %s: %w", runID, err)
+	}
+	pages, err := repo.ListPages(ctx, runID)
+	if err != nil {
+		return nil, err
+	}
+	edges, err := repo.ListEdges(ctx, runID)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &graph.FinalResponse{
+		Nodes:       make([]graph.Node, 0, len(pages)),
+		Links:       make([]graph.Link, 0, len(edges)),
+		GeneratedAt: run.StartedAt.UnixMilli(),
+	}
+	for _, p := range pages {
+		g.Nodes = append(g.Nodes, graph.Node{
+			ID:          p.URL,
+			Depth:       p.Depth,
+			StatusCode:  p.StatusCode,
+			ContentType: p.ContentType,
+			ElapsedTime: p.ElapsedTime.Nanoseconds(),
+			Title:       p.Title,
+			Domain:      p.Domain,
+			ContentHash: p.ContentHash,
+			Tags:        p.Tags,
+			Error:       p.Error,
+			ErrorClass:  p.ErrorClass,
+		})
+	}
+	for _, e := range edges {
+		g.Links = append(g.Links, graph.NewCountedLink(e.Source, e.Target, e.Count))
+	}
+	return g, nil
+}
@@ -0,0 +1,25 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nettojulio/ufape-crawler-golang/internal/config"
+)
+
+// Open cria o Repository configurado. Retorna nil quando a persistência está desabilitada.
+func Open(ctx context.Context, cfg config.StorageConfig) (Repository, error) {
+	switch cfg.Driver {
+	case "", "none":
+		return nil, nil
+	case "file":
+		return NewFileStore(cfg.Path)
+	case "postgres":
+		if cfg.DSN == "" {
+			return nil, fmt.Errorf("STORAGE_DSN is required for the postgres storage driver")
+		}
+		return NewPostgres(ctx, cfg.DSN)
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Driver)
+	}
+}
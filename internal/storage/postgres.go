@@ -0,0 +1,311 @@
+package storage
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// Postgres é um Repository sobre um banco PostgreSQL.
+type Postgres struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgres conecta ao banco em dsn e aplica as migrações pendentes.
+func NewPostgres(ctx context.Context, dsn string) (*Postgres, error) {
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to postgres: %w", err)
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("failed to ping postgres: %w", err)
+	}
+
+	p := &Postgres{pool: pool}
+	if err := p.Migrate(ctx); err != nil {
+		pool.Close()
+		return nil, err
+	}
+	return p, nil
+}
+
+// Migrate aplica, em ordem, as migrações embarcadas que ainda não constam em schema_migrations.
+func (p *Postgres) Migrate(ctx context.Context) error {
+	if _, err := p.pool.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    TEXT PRIMARY KEY,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	files, err := fs.Glob(migrations, "migrations/*.sql")
+	if err != nil {
+		return fmt.Errorf("failed to list migrations: %w", err)
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		version := strings.TrimSuffix(strings.TrimPrefix(file, "migrations/"), ".sql")
+
+		var applied bool
+		if err := p.pool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)`, version).Scan(&applied); err != nil {
+			return fmt.Errorf("failed to check migration %s: %w", version, err)
+		}
+		if applied {
+			continue
+		}
+
+		sql, err := migrations.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read migration %s: %w", version, err)
+		}
+
+		err = pgx.BeginFunc(ctx, p.pool, func(tx pgx.Tx) error {
+			if _, err := tx.Exec(ctx, string(sql)); err != nil {
+				return err
+			}
+			_, err := tx.Exec(ctx, `INSERT INTO schema_migrations (version) VALUES ($1)`, version)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to apply migration %s: %w", version, err)
+		}
+	}
+	return nil
+}
+
+func (p *Postgres) CreateRun(ctx context.Context, run *Run) error {
+	if run.ID == "" {
+		run.ID = NewID()
+	}
+	if run.Status == "" {
+		run.Status = RunStatusRunning
+	}
+	if run.StartedAt.IsZero() {
+		run.StartedAt = time.Now().UTC()
+	}
+
+	_, err := p.pool.Exec(ctx, `INSERT INTO crawl_runs (id, seed_url, source, status, started_at, finished_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		run.ID, run.SeedURL, run.Source, string(run.Status), run.StartedAt, run.FinishedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create run: %w", err)
+	}
+	return nil
+}
+
+func (p *Postgres) FinishRun(ctx context.Context, runID string, status RunStatus, finishedAt time.Time) error {
+	tag, err := p.pool.Exec(ctx, `UPDATE crawl_runs SET status = $2, finished_at = $3 WHERE id = $1`,
+		runID, string(status), finishedAt)
+	if err != nil {
+		return fmt.Errorf("failed to finish run %s: %w", runID, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (p *Postgres) GetRun(ctx context.Context, runID string) (*Run, error) {
+	row := p.pool.QueryRow(ctx, `SELECT id, seed_url, source, status, started_at, finished_at
+		FROM crawl_runs WHERE id = $1`, runID)
+	run, err := scanRun(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get run %s: %w", runID, err)
+	}
+	return run, nil
+}
+
+func (p *Postgres) ListRuns(ctx context.Context, limit int) ([]Run, error) {
+	query := `SELECT id, seed_url, source, status, started_at, finished_at FROM crawl_runs ORDER BY started_at DESC`
+	args := []any{}
+	if limit > 0 {
+		query += ` LIMIT $1`
+		args = append(args, limit)
+	}
+
+	rows, err := p.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list runs: %w", err)
+	}
+	defer rows.Close()
+
+	runs := []Run{}
+	for rows.Next() {
+		run, err := scanRun(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan run: %w", err)
+		}
+		runs = append(runs, *run)
+	}
+	return runs, rows.Err()
+}
+
+func (p *Postgres) SavePage(ctx context.Context, page Page) error {
+	if page.SavedAt.IsZero() {
+		page.SavedAt = time.Now().UTC()
+	}
+	if page.Tags == nil {
+		page.Tags = []string{}
+	}
+
+	_, err := p.pool.Exec(ctx, `INSERT INTO pages
+		(run_id, url, depth, status_code, content_type, elapsed_ns, title, domain, content_hash, tags, error_class, error, saved_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT (run_id, url) DO UPDATE SET
+			depth = EXCLUDED.depth,
+			status_code = EXCLUDED.status_code,
+			content_type = EXCLUDED.content_type,
+			elapsed_ns = EXCLUDED.elapsed_ns,
+			title = EXCLUDED.title,
+			domain = EXCLUDED.domain,
+			content_hash = EXCLUDED.content_hash,
+			tags = EXCLUDED.tags,
+			error_class = EXCLUDED.error_class,
+			error = EXCLUDED.error,
+			saved_at = EXCLUDED.saved_at`,
+		page.RunID, page.URL, page.Depth, page.StatusCode, page.ContentType, page.ElapsedTime.Nanoseconds(), page.Title, page.Domain,
+		page.ContentHash, page.Tags, page.ErrorClass, page.Error, page.SavedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save page %s: %w", page.URL, err)
+	}
+	return nil
+}
+
+func (p *Postgres) ListPages(ctx context.Context, runID string) ([]Page, error) {
+	rows, err := p.pool.Query(ctx, `SELECT run_id, url, depth, status_code, content_type, elapsed_ns, title, domain,
+		content_hash, tags, error_class, error, saved_at FROM pages WHERE run_id = $1 ORDER BY saved_at, url`, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pages: %w", err)
+	}
+	defer rows.Close()
+
+	pages := []Page{}
+	for rows.Next() {
+		var page Page
+		var elapsed int64
+		if err := rows.Scan(&page.RunID, &page.URL, &page.Depth, &page.StatusCode, &page.ContentType, &elapsed,
+			&page.Title, &page.Domain, &page.ContentHash, &page.Tags, &page.ErrorClass, &page.Error, &page.SavedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan page: %w", err)
+		}
+		page.ElapsedTime = time.Duration(elapsed)
+		if len(page.Tags) == 0 {
+			page.Tags = nil
+		}
+		pages = append(pages, page)
+	}
+	return pages, rows.Err()
+}
+
+func (p *Postgres) SaveFetch(ctx context.Context, fetch Fetch) error {
+	if fetch.FetchedAt.IsZero() {
+		fetch.FetchedAt = time.Now().UTC()
+	}
+
+	var runID *string
+	if fetch.RunID != "" {
+		runID = &fetch.RunID
+	}
+	_, err := p.pool.Exec(ctx, `INSERT INTO fetches
+		(run_id, url, final_url, status_code, content_type, elapsed_ns, error_class, error, fetched_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		runID, fetch.URL, fetch.FinalURL, fetch.StatusCode, fetch.ContentType, fetch.ElapsedTime.Nanoseconds(),
+		fetch.ErrorClass, fetch.Error, fetch.FetchedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save fetch %s: %w", fetch.URL, err)
+	}
+	return nil
+}
+
+func (p *Postgres) ListFetches(ctx context.Context, runID string) ([]Fetch, error) {
+	rows, err := p.pool.Query(ctx, `SELECT COALESCE(run_id, ''), url, final_url, status_code, content_type,
+		elapsed_ns, error_class, error, fetched_at FROM fetches
+		WHERE COALESCE(run_id, '') = $1 ORDER BY id`, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fetches: %w", err)
+	}
+	defer rows.Close()
+
+	fetches := []Fetch{}
+	for rows.Next() {
+		var fetch Fetch
+		var elapsed int64
+		if err := rows.Scan(&fetch.RunID, &fetch.URL, &fetch.FinalURL, &fetch.StatusCode, &fetch.ContentType,
+			&elapsed, &fetch.ErrorClass, &fetch.Error, &fetch.FetchedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan fetch: %w", err)
+		}
+		fetch.ElapsedTime = time.Duration(elapsed)
+		fetches = append(fetches, fetch)
+	}
+	return fetches, rows.Err()
+}
+
+func (p *Postgres) SaveEdges(ctx context.Context, edges []Edge) error {
+	if len(edges) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+	for _, edge := range edges {
+		batch.Queue(`INSERT INTO edges (run_id, source, target, count) VALUES ($1, $2, $3, $4)
+			ON CONFLICT (run_id, source, target) DO UPDATE SET count = EXCLUDED.count`,
+			edge.RunID, edge.Source, edge.Target, edge.Count)
+	}
+	if err := p.pool.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("failed to save edges: %w", err)
+	}
+	return nil
+}
+
+func (p *Postgres) ListEdges(ctx context.Context, runID string) ([]Edge, error) {
+	rows, err := p.pool.Query(ctx, `SELECT run_id, source, target, count FROM edges
+		WHERE run_id = $1 ORDER BY source, target`, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list edges: %w", err)
+	}
+	defer rows.Close()
+
+	edges := []Edge{}
+	for rows.Next() {
+		var edge Edge
+		if err := rows.Scan(&edge.RunID, &edge.Source, &edge.Target, &edge.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan edge: %w", err)
+		}
+		edges = append(edges, edge)
+	}
+	return edges, rows.Err()
+}
+
+func (p *Postgres) Ping(ctx context.Context) error {
+	return p.pool.Ping(ctx)
+}
+
+func (p *Postgres) Close() error {
+	p.pool.Close()
+	return nil
+}
+
+func scanRun(row pgx.Row) (*Run, error) {
+	var run Run
+	var status string
+	if err := row.Scan(&run.ID, &run.SeedURL, &run.Source, &status, &run.StartedAt, &run.FinishedAt); err != nil {
+		return nil, err
+	}
+	run.Status = RunStatus(status)
+	return &run, nil
+}
@@ -0,0 +1,109 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+// ErrNotFound é retornado quando o registro solicitado não existe.
+var ErrNotFound = errors.New("not found")
+
+// RunStatus representa o estado de uma execução de crawling.
+type RunStatus string
+
+const (
+	RunStatusRunning   RunStatus = "running"
+	RunStatusCompleted RunStatus = "completed"
+	RunStatusFailed    RunStatus = "failed"
+)
+
+// Origens conhecidas de uma execução de crawling.
+const (
+	SourceAPI       = "api"
+	SourceExtractor = "extractor"
+	SourceScheduler = "scheduler"
+)
+
+// Run é uma execução de crawling a partir de uma URL inicial.
+type Run struct {
+	ID         string     `json:"id"`
+	SeedURL    string     `json:"seedUrl"`
+	Source     string     `json:"source"`
+	Status     RunStatus  `json:"status"`
+	StartedAt  time.Time  `json:"startedAt"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
+
+// Page é o estado de uma página dentro de uma execução.
+type Page struct {
+	RunID       string        `json:"runId"`
+	URL         string        `json:"url"`
+	Depth       int           `json:"depth"`
+	StatusCode  int           `json:"statusCode"`
+	ContentType string        `json:"contentType"`
+	ElapsedTime time.Duration `json:"elapsedTime"`
+	Title       string        `json:"title"`
+	Domain      string        `json:"domain"`
+	ContentHash string        `json:"contentHash,omitempty"`
+	Tags        []string      `json:"tags,omitempty"`
+	ErrorClass  string        `json:"errorClass,omitempty"`
+	Error       string        `json:"error,omitempty"`
+	SavedAt     time.Time     `json:"savedAt"`
+}
+
+// Fetch é uma requisição feita a uma página. RunID vazio indica uma busca
+// avulsa, como as feitas pelo endpoint de crawling da API.
+type Fetch struct {
+	RunID       string        `json:"runId,omitempty"`
+	URL         string        `json:"url"`
+	FinalURL    string        `json:"finalUrl"`
+	StatusCode  int           `json:"statusCode"`
+	ContentType string        `json:"contentType"`
+	ElapsedTime time.Duration `json:"elapsedTime"`
+	ErrorClass  string        `json:"errorClass,omitempty"`
+	Error       string        `json:"error,omitempty"`
+	FetchedAt   time.Time     `json:"fetchedAt"`
+}
+
+// Edge é uma aresta dirigida entre duas páginas de uma execução.
+type Edge struct {
+	RunID  string `json:"runId"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Count  int    `json:"count"`
+}
+
+// Repository persiste execuções, páginas, buscas e arestas de crawling.
+type Repository interface {
+	// CreateRun registra uma nova execução, gerando o ID quando vazio.
+	CreateRun(ctx context.Context, run *Run) error
+	// FinishRun encerra uma execução com o status informado.
+	FinishRun(ctx context.Context, runID string, status RunStatus, finishedAt time.Time) error
+	GetRun(ctx context.Context, runID string) (*Run, error)
+	// ListRuns retorna as execuções mais recentes primeiro. limit <= 0 retorna todas.
+	ListRuns(ctx context.Context, limit int) ([]Run, error)
+
+	// SavePage grava ou substitui uma página da execução.
+	SavePage(ctx context.Context, page Page) error
+	ListPages(ctx context.Context, runID string) ([]Page, error)
+
+	SaveFetch(ctx context.Context, fetch Fetch) error
+	ListFetches(ctx context.Context, runID string) ([]Fetch, error)
+
+	// SaveEdges grava ou substitui as arestas informadas.
+	SaveEdges(ctx context.Context, edges []Edge) error
+	ListEdges(ctx context.Context, runID string) ([]Edge, error)
+
+	Ping(ctx context.Context) error
+	Close() error
+}
+
+// NewID gera um identificador aleatório para execuções.
+func NewID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,184 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/nettojulio/ufape-crawler-golang/internal/graph"
+)
+
+// testRepository verifica o contrato de Repository comum a todas as implementações.
+func testRepository(t *testing.T, repo Repository) {
+	t.Helper()
+	ctx := context.Background()
+
+	run := &Run{SeedURL: "https://example.com", Source: SourceExtractor}
+	if err := repo.CreateRun(ctx, run); err != nil {
+		t.Fatalf("CreateRun() returned an unexpected error: %v", err)
+	}
+	if run.ID == "" || run.Status != RunStatusRunning {
+		t.Fatalf("expected CreateRun to fill id and status, got %+v", run)
+	}
+
+	t.Run("pages are replaced by url", func(t *testing.T) {
+		_ = repo.SavePage(ctx, Page{RunID: run.ID, URL: "https://example.com", Depth: 1, StatusCode: 500})
+		_ = repo.SavePage(ctx, Page{RunID: run.ID, URL: "https://example.com", Depth: 1, StatusCode: 200, Title: "Home"})
+		_ = repo.SavePage(ctx, Page{RunID: run.ID, URL: "https://external.example", Tags: []string{graph.TagExternal}})
+
+		pages, err := repo.ListPages(ctx, run.ID)
+		if err != nil {
+			t.Fatalf("ListPages() returned an unexpected error: %v", err)
+		}
+		if len(pages) != 2 {
+			t.Fatalf("expected 2 pages, got %d", len(pages))
+		}
+		for _, p := range pages {
+			if p.URL == "https://example.com" && (p.StatusCode != 200 || p.Title != "Home") {
+				t.Errorf("expected the last saved page to win, got %+v", p)
+			}
+		}
+	})
+
+	t.Run("edges are replaced by source and target", func(t *testing.T) {
+		err := repo.SaveEdges(ctx, []Edge{
+			{RunID: run.ID, Source: "https://example.com", Target: "https://example.com/a", Count: 1},
+			{RunID: run.ID, Source: "https://example.com", Target: "https://example.com/a", Count: 3},
+		})
+		if err != nil {
+			t.Fatalf("SaveEdges() returned an unexpected error: %v", err)
+		}
+		edges, err := repo.ListEdges(ctx, run.ID)
+		if err != nil {
+			t.Fatalf("ListEdges() returned an unexpected error: %v", err)
+		}
+		if len(edges) != 1 || edges[0].Count != 3 {
+			t.Errorf("unexpected edges: %+v", edges)
+		}
+	})
+
+	t.Run("fetches with and without run", func(t *testing.T) {
+		_ = repo.SaveFetch(ctx, Fetch{RunID: run.ID, URL: "https://example.com", StatusCode: 200, ElapsedTime: time.Second})
+		_ = repo.SaveFetch(ctx, Fetch{URL: "https://adhoc.example", StatusCode: 404})
+
+		fetches, err := repo.ListFetches(ctx, run.ID)
+		if err != nil {
+			t.Fatalf("ListFetches() returned an unexpected error: %v", err)
+		}
+		if len(fetches) != 1 || fetches[0].ElapsedTime != time.Second {
+			t.Errorf("unexpected run fetches: %+v", fetches)
+		}
+
+		adHoc, err := repo.ListFetches(ctx, "")
+		if err != nil {
+			t.Fatalf("ListFetches() returned an unexpected error: %v", err)
+		}
+		if len(adHoc) == 0 || adHoc[len(adHoc)-1].URL != "https://adhoc.example" {
+			t.Errorf("unexpected ad hoc fetches: %+v", adHoc)
+		}
+	})
+
+	t.Run("finish and list runs", func(t *testing.T) {
+		if err := repo.FinishRun(ctx, run.ID, RunStatusCompleted, time.Now().UTC()); err != nil {
+			t.Fatalf("FinishRun() returned an unexpected error: %v", err)
+		}
+		got, err := repo.GetRun(ctx, run.ID)
+		if err != nil {
+			t.Fatalf("GetRun() returned an unexpected error: %v", err)
+		}
+		if got.Status != RunStatusCompleted || got.FinishedAt == nil {
+			t.Errorf("expected a completed run, got %+v", got)
+		}
+
+		runs, err := repo.ListRuns(ctx, 1)
+		if err != nil {
+			t.Fatalf("ListRuns() returned an unexpected error: %v", err)
+		}
+		if len(runs) != 1 {
+			t.Errorf("expected ListRuns to honor the limit, got %d runs", len(runs))
+		}
+	})
+
+	t.Run("graph round trip", func(t *testing.T) {
+		g, err := LoadGraph(ctx, repo, run.ID)
+		if err != nil {
+			t.Fatalf("LoadGraph() returned an unexpected error: %v", err)
+		}
+		if len(g.Nodes) != 2 || len(g.Links) != 1 || g.Links[0].Count != 3 {
+			t.Errorf("unexpected graph: %+v", g)
+		}
+	})
+
+	t.Run("unknown run", func(t *testing.T) {
+		if _, err := repo.GetRun(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+	})
+}
+
+func TestFileStore(t *testing.T) {
+	repo, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileStore() returned an unexpected error: %v", err)
+	}
+	defer repo.Close()
+
+	testRepository(t, repo)
+
+	t.Run("run ids outside the generated format", func(t *testing.T) {
+		ctx := context.Background()
+		for _, id := range []string{"..", ".", "../outside", "/etc"} {
+			if _, err := repo.GetRun(ctx, id); !errors.Is(err, ErrNotFound) {
+				t.Errorf("GetRun(%q): expected ErrNotFound, got %v", id, err)
+			}
+			if _, err := repo.ListPages(ctx, id); !errors.Is(err, ErrNotFound) {
+				t.Errorf("ListPages(%q): expected ErrNotFound, got %v", id, err)
+			}
+			if err := repo.SaveFetch(ctx, Fetch{RunID: id, URL: "https://example.com"}); !errors.Is(err, ErrNotFound) {
+				t.Errorf("SaveFetch(%q): expected ErrNotFound, got %v", id, err)
+			}
+		}
+		if err := repo.CreateRun(ctx, &Run{ID: ".."}); err == nil {
+			t.Error("expected CreateRun to reject an invalid id")
+		}
+	})
+}
+
+func TestPostgres(t *testing.T) {
+	dsn := os.Getenv("STORAGE_TEST_DSN")
+	if dsn == "" {
+		t.Skip("STORAGE_TEST_DSN not set, skipping postgres integration test")
+	}
+
+	repo, err := NewPostgres(context.Background(), dsn)
+	if err != nil {
+		t.Fatalf("NewPostgres() returned an unexpected error: %v", err)
+	}
+	defer repo.Close()
+
+	testRepository(t, repo)
+}
+
+func TestGraphWriter(t *testing.T) {
+	ctx := context.Background()
+	repo, _ := NewFileStore(t.TempDir())
+	run := &Run{SeedURL: "https://example.com", Source: SourceExtractor}
+	_ = repo.CreateRun(ctx, run)
+
+	w := NewGraphWriter(ctx, repo, run.ID)
+	_ = w.WriteNode(graph.Node{ID: "https://example.com", Depth: 1, StatusCode: 200, ElapsedTime: 10})
+	_ = w.WriteLink(graph.NewLink("https://example.com", "https://example.com/a"))
+
+	g, err := LoadGraph(ctx, repo, run.ID)
+	if err != nil {
+		t.Fatalf("LoadGraph() returned an unexpected error: %v", err)
+	}
+	if len(g.Nodes) != 1 || g.Nodes[0].ElapsedTime != 10 {
+		t.Errorf("unexpected nodes: %+v", g.Nodes)
+	}
+	if len(g.Links) != 1 || g.Links[0].Count != 1 {
+		t.Errorf("expected links without count to be stored with count 1, got %+v", g.Links)
+	}
+}
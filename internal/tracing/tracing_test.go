@@ -0,0 +1,68 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/nettojulio/ufape-crawler-golang/internal/config"
+)
+
+func TestSetupFileExporter(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "traces.json")
+	shutdown, err := Setup(context.Background(), config.TracingConfig{
+		Exporter:    ExporterFile,
+		File:        file,
+		ServiceName: "crawler-test",
+		SampleRatio: 1,
+	}, "v1.2.3-test")
+	if err != nil {
+		t.Fatalf("Setup returned an error: %v", err)
+	}
+
+	_, span := otel.Tracer("test").Start(context.Background(), "test-span")
+	span.End()
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown returned an error: %v", err)
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("failed to read trace file: %v", err)
+	}
+	for _, want := range []string{`"Name":"test-span"`, "crawler-test", "v1.2.3-test"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected trace file to contain %q, got %s", want, data)
+		}
+	}
+}
+
+func TestSetupPropagatesTraceContext(t *testing.T) {
+	shutdown, err := Setup(context.Background(), config.TracingConfig{Exporter: ExporterNone}, "test")
+	if err != nil {
+		t.Fatalf("Setup returned an error: %v", err)
+	}
+	defer shutdown(context.Background())
+
+	header := http.Header{}
+	header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(header))
+
+	sc := trace.SpanContextFromContext(ctx)
+	if sc.TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" || !sc.IsRemote() {
+		t.Errorf("expected the remote trace context to be extracted, got %+v", sc)
+	}
+}
+
+func TestSetupUnknownExporter(t *testing.T) {
+	if _, err := Setup(context.Background(), config.TracingConfig{Exporter: "zipkin"}, "test"); err == nil {
+		t.Error("expected an error for an unknown exporter")
+	}
+}
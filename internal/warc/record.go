@@ -0,0 +1,127 @@
+package warc
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/textproto"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Tipos de registro usados pelo Writer.
+const (
+	TypeWarcinfo = "warcinfo"
+	TypeRequest  = "request"
+	TypeResponse = "response"
+)
+
+const version = "WARC/1.1"
+
+// Record é um registro WARC. Fields guarda os campos de cabeçalho além de
+// WARC-Type, WARC-Record-ID, WARC-Date, WARC-Target-URI, Content-Length e
+// WARC-Block-Digest, que são escritos a partir dos demais atributos.
+type Record struct {
+	Type      string
+	ID        string
+	Date      time.Time
+	TargetURI string
+	Fields    http.Header
+	Block     []byte
+}
+
+func (r Record) write(w io.Writer) error {
+	var b bytes.Buffer
+	b.WriteString(version + "\r\n")
+	fmt.Fprintf(&b, "WARC-Type: %s\r\n", r.Type)
+	fmt.Fprintf(&b, "WARC-Record-ID: %s\r\n", r.ID)
+	fmt.Fprintf(&b, "WARC-Date: %s\r\n", r.Date.UTC().Format(time.RFC3339Nano))
+	if r.TargetURI != "" {
+		fmt.Fprintf(&b, "WARC-Target-URI: %s\r\n", r.TargetURI)
+	}
+
+	names := make([]string, 0, len(r.Fields))
+	for name := range r.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range r.Fields[name] {
+			fmt.Fprintf(&b, "%s: %s\r\n", canonicalField(name), value)
+		}
+	}
+	fmt.Fprintf(&b, "WARC-Block-Digest: %s\r\n", digest(r.Block))
+	fmt.Fprintf(&b, "Content-Length: %d\r\n\r\n", len(r.Block))
+
+	if _, err := w.Write(b.Bytes()); err != nil {
+		return err
+	}
+	if _, err := w.Write(r.Block); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\r\n\r\n")
+	return err
+}
+
+// Reader lê os registros de um arquivo WARC não comprimido ou de um fluxo
+// gzip já descomprimido, em que os membros são concatenados.
+type Reader struct {
+	r *bufio.Reader
+}
+
+// NewReader cria um Reader sobre r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReader(r)}
+}
+
+// Next retorna o próximo registro ou io.EOF ao fim do arquivo.
+func (rd *Reader) Next() (*Record, error) {
+	tp := textproto.NewReader(rd.r)
+	line, err := tp.ReadLine()
+	if err != nil {
+		return nil, err
+	}
+	if line != version {
+		return nil, fmt.Errorf("unexpected warc version line %q", line)
+	}
+	header, err := tp.ReadMIMEHeader()
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to read warc header: %w", err)
+	}
+
+	length, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid warc content length: %w", err)
+	}
+	block := make([]byte, length)
+	if _, err := io.ReadFull(rd.r, block); err != nil {
+		return nil, fmt.Errorf("failed to read warc block: %w", err)
+	}
+	trailer := make([]byte, 4)
+	if _, err := io.ReadFull(rd.r, trailer); err != nil || string(trailer) != "\r\n\r\n" {
+		return nil, errors.New("missing warc record trailer")
+	}
+
+	date, _ := time.Parse(time.RFC3339Nano, header.Get("WARC-Date"))
+	record := &Record{
+		Type:      header.Get("WARC-Type"),
+		ID:        header.Get("WARC-Record-ID"),
+		Date:      date,
+		TargetURI: header.Get("WARC-Target-URI"),
+		Fields:    http.Header{},
+		Block:     block,
+	}
+	for name, values := range header {
+		switch strings.ToLower(name) {
+		case "warc-type", "warc-record-id", "warc-date", "warc-target-uri", "content-length", "warc-block-digest":
+			continue
+		}
+		record.Fields[name] = values
+	}
+	return record, nil
+}
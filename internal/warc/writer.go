@@ -0,0 +1,277 @@
+// Package warc grava as trocas HTTP do crawler em arquivos WARC 1.1
+// (ISO 28500:2017), com rotação por tamanho.
+package warc
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nettojulio/ufape-crawler-golang/internal/crawler"
+)
+
+// DefaultMaxSize é o tamanho a partir do qual um arquivo é encerrado e outro é aberto.
+const DefaultMaxSize = 1 << 30
+
+// Options configura o Writer.
+type Options struct {
+	// Dir é o diretório onde os arquivos são criados.
+	Dir string
+	// Prefix inicia o nome de cada arquivo. O padrão é "ufape-crawler".
+	Prefix string
+	// MaxSize é o tamanho, em bytes, a partir do qual o arquivo é rotacionado.
+	// Um registro nunca é dividido, então os arquivos podem exceder levemente o limite.
+	MaxSize int64
+	// Gzip comprime cada registro como um membro gzip independente (.warc.gz).
+	Gzip bool
+	// Software identifica o crawler no registro warcinfo de cada arquivo.
+	Software string
+}
+
+// Writer grava registros WARC. É seguro para uso concorrente.
+type Writer struct {
+	opts Options
+
+	mu      sync.Mutex
+	file    *os.File
+	name    string
+	size    int64
+	serial  int
+	records int
+}
+
+// NewWriter cria o diretório de destino e retorna um Writer. O primeiro
+// arquivo só é criado quando o primeiro registro é gravado.
+func NewWriter(opts Options) (*Writer, error) {
+	if opts.Prefix == "" {
+		opts.Prefix = "ufape-crawler"
+	}
+	if opts.MaxSize <= 0 {
+		opts.MaxSize = DefaultMaxSize
+	}
+	if opts.Software == "" {
+		opts.Software = "ufape-crawler-golang"
+	}
+	if err := os.MkdirAll(opts.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create warc directory: %w", err)
+	}
+	return &Writer{opts: opts}, nil
+}
+
+// RecordExchange grava a troca como um registro request seguido de um
+// registro response, ligados por WARC-Concurrent-To.
+func (w *Writer) RecordExchange(ex crawler.Exchange) error {
+	target := ex.Request.URL.String()
+	date := ex.FetchedAt
+	if date.IsZero() {
+		date = time.Now().UTC()
+	}
+
+	responseID := newRecordID()
+	responseBlock := responseBlock(ex.Response, ex.Body)
+	response := Record{
+		Type:      TypeResponse,
+		ID:        responseID,
+		Date:      date,
+		TargetURI: target,
+		Fields: http.Header{
+			"Content-Type":        {"application/http;msgtype=response"},
+			"Warc-Payload-Digest": {digest(ex.Body)},
+		},
+		Block: responseBlock,
+	}
+	if ex.Truncated {
+		response.Fields.Set("WARC-Truncated", "length")
+	}
+
+	request := Record{
+		Type:      TypeRequest,
+		ID:        newRecordID(),
+		Date:      date,
+		TargetURI: target,
+		Fields: http.Header{
+			"Content-Type":       {"application/http;msgtype=request"},
+			"Warc-Concurrent-To": {responseID},
+		},
+		Block: requestBlock(ex.Request),
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.writeLocked(request); err != nil {
+		return err
+	}
+	return w.writeLocked(response)
+}
+
+// Close encerra o arquivo atual.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.closeLocked()
+}
+
+// Files retorna os arquivos WARC já criados por este Writer, em ordem.
+func (w *Writer) Files() ([]string, error) {
+	pattern := filepath.Join(w.opts.Dir, w.opts.Prefix+"-*"+w.extension())
+	files, err := filepath.Glob(pattern)
+	sort.Strings(files)
+	return files, err
+}
+
+func (w *Writer) extension() string {
+	if w.opts.Gzip {
+		return ".warc.gz"
+	}
+	return ".warc"
+}
+
+func (w *Writer) writeLocked(r Record) error {
+	if w.file != nil && w.size >= w.opts.MaxSize {
+		if err := w.closeLocked(); err != nil {
+			return err
+		}
+	}
+	if w.file == nil {
+		if err := w.openLocked(); err != nil {
+			return err
+		}
+	}
+
+	n, err := w.writeRecord(r)
+	w.size += n
+	if err != nil {
+		return fmt.Errorf("failed to write warc record to %s: %w", w.name, err)
+	}
+	w.records++
+	return nil
+}
+
+func (w *Writer) openLocked() error {
+	w.serial++
+	w.name = fmt.Sprintf("%s-%s-%05d%s", w.opts.Prefix, time.Now().UTC().Format("20060102150405"), w.serial, w.extension())
+
+	file, err := os.OpenFile(filepath.Join(w.opts.Dir, w.name), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to create warc file: %w", err)
+	}
+	w.file = file
+	w.size = 0
+
+	info := fmt.Sprintf("software: %s\r\nformat: WARC File Format 1.1\r\nconformsTo: http://iipc.github.io/warc-specifications/specifications/warc-format/warc-1.1/\r\n", w.opts.Software)
+	n, err := w.writeRecord(Record{
+		Type: TypeWarcinfo,
+		ID:   newRecordID(),
+		Date: time.Now().UTC(),
+		Fields: http.Header{
+			"Content-Type":  {"application/warc-fields"},
+			"Warc-Filename": {w.name},
+		},
+		Block: []byte(info),
+	})
+	w.size += n
+	return err
+}
+
+func (w *Writer) closeLocked() error {
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+// writeRecord grava r no arquivo atual e retorna quantos bytes foram escritos no disco.
+func (w *Writer) writeRecord(r Record) (int64, error) {
+	counter := &countingWriter{w: w.file}
+	if !w.opts.Gzip {
+		err := r.write(counter)
+		return counter.n, err
+	}
+
+	gz := gzip.NewWriter(counter)
+	if err := r.write(gz); err != nil {
+		return counter.n, err
+	}
+	err := gz.Close()
+	return counter.n, err
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// requestBlock serializa a requisição como enviada: linha de requisição,
+// Host e cabeçalhos.
+func requestBlock(req *http.Request) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s %s HTTP/1.1\r\n", req.Method, req.URL.RequestURI())
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	fmt.Fprintf(&b, "Host: %s\r\n", host)
+	_ = req.Header.WriteSubset(&b, map[string]bool{"Host": true})
+	b.WriteString("\r\n")
+	return b.Bytes()
+}
+
+// responseBlock serializa a resposta: linha de status, cabeçalhos e corpo.
+// O corpo é o entregue pelo transporte, já sem compressão ou chunked.
+func responseBlock(resp *http.Response, body []byte) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "HTTP/%d.%d %s\r\n", resp.ProtoMajor, resp.ProtoMinor, statusLine(resp))
+	_ = resp.Header.WriteSubset(&b, map[string]bool{"Content-Length": true})
+	fmt.Fprintf(&b, "Content-Length: %d\r\n\r\n", len(body))
+	b.Write(body)
+	return b.Bytes()
+}
+
+func statusLine(resp *http.Response) string {
+	if resp.Status != "" {
+		return resp.Status
+	}
+	return strconv.Itoa(resp.StatusCode) + " " + http.StatusText(resp.StatusCode)
+}
+
+func digest(b []byte) string {
+	sum := sha256.Sum256(b)
+	return "sha256:" + base32.StdEncoding.EncodeToString(sum[:])
+}
+
+func newRecordID() string {
+	var u [16]byte
+	_, _ = rand.Read(u[:])
+	u[6] = (u[6] & 0x0f) | 0x40
+	u[8] = (u[8] & 0x3f) | 0x80
+	return fmt.Sprintf("<urn:uuid:%x-%x-%x-%x-%x>", u[0:4], u[4:6], u[6:8], u[8:10], u[10:])
+}
+
+// canonicalField devolve o nome do campo WARC na grafia da especificação.
+func canonicalField(name string) string {
+	if strings.HasPrefix(name, "Warc-") {
+		return "WARC-" + name[len("Warc-"):]
+	}
+	return name
+}
@@ -0,0 +1,132 @@
+package warc
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/nettojulio/ufape-crawler-golang/internal/crawler"
+)
+
+func readRecords(t *testing.T, path string, compressed bool) []*Record {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open %s: %v", path, err)
+	}
+	defer file.Close()
+
+	var r io.Reader = file
+	if compressed {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			t.Fatalf("failed to open gzip stream: %v", err)
+		}
+		r = gz
+	}
+
+	var records []*Record
+	reader := NewReader(r)
+	for {
+		record, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return records
+		}
+		if err != nil {
+			t.Fatalf("Next() returned an unexpected error: %v", err)
+		}
+		records = append(records, record)
+	}
+}
+
+func TestWriterRecordsHTTPClientExchanges(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/old", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/page", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/page", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = io.WriteString(w, "<html><title>Página</title></html>")
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	writer, err := NewWriter(Options{Dir: t.TempDir(), Gzip: true})
+	if err != nil {
+		t.Fatalf("NewWriter() returned an unexpected error: %v", err)
+	}
+	client := crawler.NewHTTPClient(0, crawler.WithRecorder(writer))
+
+	resp, err := client.Get(context.Background(), server.URL+"/old", crawler.RequestOptions{})
+	if err != nil {
+		t.Fatalf("Get() returned an unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close() returned an unexpected error: %v", err)
+	}
+
+	files, _ := writer.Files()
+	if len(files) != 1 || !strings.HasSuffix(files[0], ".warc.gz") {
+		t.Fatalf("expected a single .warc.gz file, got %v", files)
+	}
+	records := readRecords(t, files[0], true)
+
+	types := make([]string, len(records))
+	for i, r := range records {
+		types[i] = r.Type
+	}
+	expected := []string{TypeWarcinfo, TypeRequest, TypeResponse, TypeRequest, TypeResponse}
+	if strings.Join(types, ",") != strings.Join(expected, ",") {
+		t.Fatalf("expected records %v, got %v", expected, types)
+	}
+
+	redirect, page := records[2], records[4]
+	if !bytes.HasPrefix(redirect.Block, []byte("HTTP/1.1 301")) || redirect.TargetURI != server.URL+"/old" {
+		t.Errorf("expected the redirect to be archived, got %q", redirect.Block)
+	}
+	if !bytes.HasSuffix(page.Block, []byte("<html><title>Página</title></html>")) {
+		t.Errorf("expected the raw payload in the response block, got %q", page.Block)
+	}
+	if records[3].Fields.Get("WARC-Concurrent-To") != page.ID {
+		t.Error("expected the request to reference its response")
+	}
+	if !bytes.Contains(records[3].Block, []byte("User-Agent: Mozilla/5.0")) {
+		t.Errorf("expected request headers to be archived, got %q", records[3].Block)
+	}
+}
+
+func TestWriterRotatesBySize(t *testing.T) {
+	writer, err := NewWriter(Options{Dir: t.TempDir(), MaxSize: 1024})
+	if err != nil {
+		t.Fatalf("NewWriter() returned an unexpected error: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/", nil)
+	resp := &http.Response{StatusCode: http.StatusOK, ProtoMajor: 1, ProtoMinor: 1, Header: http.Header{}}
+	body := bytes.Repeat([]byte("a"), 800)
+	for range 3 {
+		if err := writer.RecordExchange(crawler.Exchange{Request: req, Response: resp, Body: body}); err != nil {
+			t.Fatalf("RecordExchange() returned an unexpected error: %v", err)
+		}
+	}
+	_ = writer.Close()
+
+	files, _ := writer.Files()
+	if len(files) != 3 {
+		t.Fatalf("expected 3 rotated files, got %d", len(files))
+	}
+	for _, f := range files {
+		records := readRecords(t, f, false)
+		if len(records) != 3 || records[0].Type != TypeWarcinfo {
+			t.Errorf("expected each file to start with warcinfo and hold one exchange, got %d records", len(records))
+		}
+	}
+}
@@ -0,0 +1,63 @@
+-- Schema inicial aplicado pelo container do Postgres na primeira inicialização.
+-- Mantido em sincronia com internal/storage/migrations; a aplicação registra
+-- as migrações em schema_migrations e não as reaplica.
+
+CREATE TABLE IF NOT EXISTS schema_migrations (
+    version    TEXT PRIMARY KEY,
+    applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS crawl_runs (
+    id          TEXT PRIMARY KEY,
+    seed_url    TEXT        NOT NULL,
+    source      TEXT        NOT NULL,
+    status      TEXT        NOT NULL,
+    started_at  TIMESTAMPTZ NOT NULL,
+    finished_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS crawl_runs_started_at_idx ON crawl_runs (started_at DESC);
+
+CREATE TABLE IF NOT EXISTS pages (
+    run_id       TEXT        NOT NULL REFERENCES crawl_runs (id) ON DELETE CASCADE,
+    url          TEXT        NOT NULL,
+    depth        INTEGER     NOT NULL DEFAULT 0,
+    status_code  INTEGER     NOT NULL DEFAULT 0,
+    content_type TEXT        NOT NULL DEFAULT '',
+    elapsed_ns   BIGINT      NOT NULL DEFAULT 0,
+    title        TEXT        NOT NULL DEFAULT '',
+    domain       TEXT        NOT NULL DEFAULT '',
+    tags         TEXT[]      NOT NULL DEFAULT '{}',
+    error_class  TEXT        NOT NULL DEFAULT '',
+    error        TEXT        NOT NULL DEFAULT '',
+    saved_at     TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (run_id, url)
+);
+
+CREATE TABLE IF NOT EXISTS fetches (
+    id           BIGSERIAL PRIMARY KEY,
+    run_id       TEXT REFERENCES crawl_runs (id) ON DELETE CASCADE,
+    url          TEXT        NOT NULL,
+    final_url    TEXT        NOT NULL DEFAULT '',
+    status_code  INTEGER     NOT NULL DEFAULT 0,
+    content_type TEXT        NOT NULL DEFAULT '',
+    elapsed_ns   BIGINT      NOT NULL DEFAULT 0,
+    error_class  TEXT        NOT NULL DEFAULT '',
+    error        TEXT        NOT NULL DEFAULT '',
+    fetched_at   TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS fetches_run_id_idx ON fetches (run_id);
+CREATE INDEX IF NOT EXISTS fetches_url_idx ON fetches (url);
+
+CREATE TABLE IF NOT EXISTS edges (
+    run_id TEXT    NOT NULL REFERENCES crawl_runs (id) ON DELETE CASCADE,
+    source TEXT    NOT NULL,
+    target TEXT    NOT NULL,
+    count  INTEGER NOT NULL DEFAULT 1,
+    PRIMARY KEY (run_id, source, target)
+);
+
+ALTER TABLE pages ADD COLUMN IF NOT EXISTS content_hash TEXT NOT NULL DEFAULT '';
+
+INSERT INTO schema_migrations (version) VALUES ('0001_initial'), ('0002_page_content_hash') ON CONFLICT DO NOTHING;